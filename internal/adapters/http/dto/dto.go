@@ -11,15 +11,22 @@ import (
 )
 
 type subsReq struct {
-	ServiceName string `json:"service_name"`
-	Price       int    `json:"price"`
-	UserID      string `json:"user_id"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date"`
+	ServiceName   string               `json:"service_name"`
+	Price         int                  `json:"price"`
+	UserID        string               `json:"user_id"`
+	StartDate     string               `json:"start_date"`
+	EndDate       string               `json:"end_date"`
+	AutoRenew     *bool                `json:"auto_renew"`
+	BillingPeriod domain.BillingPeriod `json:"billing_period"`
+	Tags          []string             `json:"tags"`
+	TrialEndDate  string               `json:"trial_end_date"`
+	TrialPrice    int                  `json:"trial_price"`
+	Discounts     []domain.Discount    `json:"discounts"`
 }
 
 // GetSubsJSON extracts subscription data from the request context.
 // It returns a domain.Subscription object or an error if the data is invalid.
+// A subscription renews automatically by default unless it is given an end date.
 func GetSubsJSON(ctx *gin.Context) (domain.Subscription, error) {
 	var (
 		subsReq subsReq
@@ -30,9 +37,17 @@ func GetSubsJSON(ctx *gin.Context) (domain.Subscription, error) {
 	}
 
 	subs := domain.Subscription{
-		ServiceName: subsReq.ServiceName,
-		Price:       subsReq.Price,
-		UserID:      subsReq.UserID,
+		ServiceName:   subsReq.ServiceName,
+		Price:         subsReq.Price,
+		UserID:        subsReq.UserID,
+		BillingPeriod: subsReq.BillingPeriod,
+		Tags:          subsReq.Tags,
+		TrialPrice:    subsReq.TrialPrice,
+		Discounts:     subsReq.Discounts,
+		AutoRenew:     len(subsReq.EndDate) == 0,
+	}
+	if subsReq.AutoRenew != nil {
+		subs.AutoRenew = *subsReq.AutoRenew
 	}
 
 	timeLayout := time.DateOnly
@@ -48,78 +63,577 @@ func GetSubsJSON(ctx *gin.Context) (domain.Subscription, error) {
 		}
 	}
 
+	if len(subsReq.TrialEndDate) != 0 {
+		trialEnd, err := time.Parse(timeLayout, subsReq.TrialEndDate)
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+		subs.TrialEndDate = &trialEnd
+	}
+
 	return subs, nil
 }
 
-type SummaryQueries struct {
-	Start       time.Time
-	End         time.Time
-	ServiceName string
-	UserID      string
-	PageNumber  int
-	PageSize    int
+type subsPatchReq struct {
+	Price         *int                  `json:"price"`
+	StartDate     *string               `json:"start_date"`
+	EndDate       *string               `json:"end_date"`
+	BillingPeriod *domain.BillingPeriod `json:"billing_period"`
+	AutoRenew     *bool                 `json:"auto_renew"`
+}
+
+// GetSubsPatchJSON extracts the subscription fields to change from the request context.
+func GetSubsPatchJSON(ctx *gin.Context) (domain.SubsPatch, error) {
+	var req subsPatchReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return domain.SubsPatch{}, err
+	}
+
+	patch := domain.SubsPatch{
+		Price:         req.Price,
+		BillingPeriod: req.BillingPeriod,
+		AutoRenew:     req.AutoRenew,
+	}
+
+	timeLayout := time.DateOnly
+	if req.StartDate != nil {
+		startDate, err := time.Parse(timeLayout, *req.StartDate)
+		if err != nil {
+			return domain.SubsPatch{}, err
+		}
+		patch.StartDate = &startDate
+	}
+
+	if req.EndDate != nil {
+		endDate, err := time.Parse(timeLayout, *req.EndDate)
+		if err != nil {
+			return domain.SubsPatch{}, err
+		}
+		patch.EndDate = &endDate
+	}
+
+	return patch, nil
+}
+
+type serviceReq struct {
+	Name         string               `json:"name"`
+	Aliases      []string             `json:"aliases"`
+	Category     string               `json:"category"`
+	Vendor       string               `json:"vendor"`
+	DefaultPrice int                  `json:"default_price"`
+	Status       domain.ServiceStatus `json:"status"`
+}
+
+// GetServiceJSON extracts catalog service data from the request context.
+func GetServiceJSON(ctx *gin.Context) (domain.Service, error) {
+	var req serviceReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return domain.Service{}, err
+	}
+
+	return domain.Service{
+		Name:         req.Name,
+		Aliases:      req.Aliases,
+		Category:     req.Category,
+		Vendor:       req.Vendor,
+		DefaultPrice: req.DefaultPrice,
+		Status:       req.Status,
+	}, nil
+}
+
+type budgetReq struct {
+	UserID      string `json:"user_id"`
+	Limit       int    `json:"limit"`
+	Category    string `json:"category"`
+	ServiceName string `json:"service_name"`
+	Thresholds  []int  `json:"thresholds"`
+}
+
+// GetBudgetJSON extracts budget data from the request context.
+func GetBudgetJSON(ctx *gin.Context) (domain.Budget, error) {
+	var req budgetReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return domain.Budget{}, err
+	}
+
+	return domain.Budget{
+		UserID:      req.UserID,
+		Limit:       req.Limit,
+		Category:    req.Category,
+		ServiceName: req.ServiceName,
+		Thresholds:  req.Thresholds,
+	}, nil
+}
+
+type webhookReq struct {
+	URL        string             `json:"url"`
+	Secret     string             `json:"secret"`
+	EventTypes []domain.EventType `json:"event_types"`
+}
+
+// GetWebhookJSON extracts webhook endpoint data from the request context.
+func GetWebhookJSON(ctx *gin.Context) (domain.WebhookEndpoint, error) {
+	var req webhookReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return domain.WebhookEndpoint{}, err
+	}
+
+	return domain.WebhookEndpoint{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	}, nil
+}
+
+type tagsReq struct {
+	Tags []string `json:"tags"`
+}
+
+// GetTagsJSON extracts the subscription tags from the request context, an empty list removes all tags.
+func GetTagsJSON(ctx *gin.Context) ([]string, error) {
+	var req tagsReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	return req.Tags, nil
+}
+
+type discountsReq struct {
+	Discounts []domain.Discount `json:"discounts"`
+}
+
+// GetDiscountsJSON extracts the subscription discount phases from the request context,
+// an empty list removes all discounts.
+func GetDiscountsJSON(ctx *gin.Context) ([]domain.Discount, error) {
+	var req discountsReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	return req.Discounts, nil
+}
+
+type participantsReq struct {
+	Participants []struct {
+		UserID string `json:"user_id"`
+		Weight int    `json:"weight"`
+		Amount int    `json:"amount"`
+	} `json:"participants"`
+}
+
+// GetParticipantsJSON extracts participants sharing the subscription from the request context,
+// an empty list stops sharing the subscription.
+func GetParticipantsJSON(ctx *gin.Context) ([]domain.Participant, error) {
+	var req participantsReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+
+	participants := make([]domain.Participant, 0, len(req.Participants))
+	for _, p := range req.Participants {
+		participants = append(participants, domain.Participant{UserID: p.UserID, Weight: p.Weight, Amount: p.Amount})
+	}
+	return participants, nil
+}
+
+type shareStatusReq struct {
+	Status domain.ShareStatus `json:"status"`
+}
+
+// GetShareStatusJSON extracts the answer to the share invitation from the request context.
+func GetShareStatusJSON(ctx *gin.Context) (domain.ShareStatus, error) {
+	var req shareStatusReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return "", err
+	}
+
+	if req.Status != domain.ShareAccepted && req.Status != domain.ShareDeclined {
+		return "", domain.ErrInvalidShareStatus
+	}
+	return req.Status, nil
+}
+
+type statusReq struct {
+	Status domain.Status `json:"status"`
+}
+
+// GetStatusJSON extracts the requested subscription status from the request context.
+func GetStatusJSON(ctx *gin.Context) (domain.Status, error) {
+	var req statusReq
+	if err := ctx.BindJSON(&req); err != nil {
+		return "", err
+	}
+
+	if !req.Status.IsValid() {
+		return "", domain.ErrInvalidStatus
+	}
+	return req.Status, nil
+}
+
+// GetCancellationJSON extracts the reason and the comment of a cancellation from the request context.
+func GetCancellationJSON(ctx *gin.Context) (domain.Cancellation, error) {
+	var req domain.Cancellation
+	if err := ctx.BindJSON(&req); err != nil {
+		return domain.Cancellation{}, err
+	}
+
+	if err := req.Validate(); err != nil {
+		return domain.Cancellation{}, err
+	}
+	return req, nil
 }
 
 // GetSummaryQuery extracts summary query parameters from the request context.
-// It returns a SummaryQueries struct or an error if required parameters are missing or invalid.
-func GetSummaryQuery(ctx *gin.Context) (SummaryQueries, error) {
+// It returns a domain.SummaryFilter or an error if required parameters are missing or invalid.
+// The "active" mode looks at a single "at" date, other modes require a "start" and "end" window.
+func GetSummaryQuery(ctx *gin.Context) (domain.SummaryFilter, error) {
+	var (
+		filter domain.SummaryFilter
+		err    error
+	)
+	errFormat := "missing required query value %s"
+	timeLayout := time.DateOnly
+
+	filter.Mode = domain.FilterMode(ctx.DefaultQuery("mode", string(domain.FilterOverlap)))
+	if !filter.Mode.IsValid() {
+		return domain.SummaryFilter{}, domain.ErrInvalidFilterMode
+	}
+
+	if filter.Mode == domain.FilterActive {
+		atStr, ok := ctx.GetQuery("at")
+		if !ok {
+			return domain.SummaryFilter{}, fmt.Errorf(errFormat, "at")
+		}
+
+		filter.Start, err = time.Parse(timeLayout, atStr)
+		if err != nil {
+			return domain.SummaryFilter{}, err
+		}
+		filter.End = filter.Start
+	} else {
+		startStr, ok := ctx.GetQuery("start")
+		if !ok {
+			return domain.SummaryFilter{}, fmt.Errorf(errFormat, "start")
+		}
+
+		endStr, ok := ctx.GetQuery("end")
+		if !ok {
+			return domain.SummaryFilter{}, fmt.Errorf(errFormat, "end")
+		}
+
+		filter.Start, err = time.Parse(timeLayout, startStr)
+		if err != nil {
+			return domain.SummaryFilter{}, err
+		}
+
+		filter.End, err = time.Parse(timeLayout, endStr)
+		if err != nil {
+			return domain.SummaryFilter{}, err
+		}
+
+		if filter.End.Before(filter.Start) {
+			return domain.SummaryFilter{}, domain.ErrInvalidDate
+		}
+	}
+
+	filter.UserID, _ = ctx.GetQuery("user_ID")
+	filter.ServiceName, _ = ctx.GetQuery("service_name")
+	filter.Tags = ctx.QueryArray("tag")
+
+	filter.GroupBy = domain.GroupBy(ctx.Query("group_by"))
+	if filter.GroupBy != "" && !filter.GroupBy.IsValid() {
+		return domain.SummaryFilter{}, domain.ErrInvalidGroupBy
+	}
+
+	// Использование:
+	filter.Pagination, err = GetPaginationArgs(ctx)
+	if err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// GetSpendSeriesQuery extracts spend series query parameters from the request context.
+// The "start" and "end" dates are required and inclusive, the "bucket" defaults to month.
+func GetSpendSeriesQuery(ctx *gin.Context) (domain.SpendSeriesFilter, error) {
+	var (
+		filter domain.SpendSeriesFilter
+		err    error
+	)
+	errFormat := "missing required query value %s"
+	timeLayout := time.DateOnly
+
+	startStr, ok := ctx.GetQuery("start")
+	if !ok {
+		return domain.SpendSeriesFilter{}, fmt.Errorf(errFormat, "start")
+	}
+
+	endStr, ok := ctx.GetQuery("end")
+	if !ok {
+		return domain.SpendSeriesFilter{}, fmt.Errorf(errFormat, "end")
+	}
+
+	filter.Start, err = time.Parse(timeLayout, startStr)
+	if err != nil {
+		return domain.SpendSeriesFilter{}, err
+	}
+
+	filter.End, err = time.Parse(timeLayout, endStr)
+	if err != nil {
+		return domain.SpendSeriesFilter{}, err
+	}
+
+	filter.Bucket = domain.PeriodUnit(ctx.DefaultQuery("bucket", string(domain.PeriodMonth)))
+	filter.UserID, _ = ctx.GetQuery("user_ID")
+	filter.ServiceName, _ = ctx.GetQuery("service_name")
+	filter.Tags = ctx.QueryArray("tag")
+
+	if err := filter.Validate(); err != nil {
+		return domain.SpendSeriesFilter{}, err
+	}
+	return filter, nil
+}
+
+// GetChurnQuery extracts churn report query parameters from the request context.
+// The "start" and "end" dates are required and inclusive, the "bucket" defaults to month.
+func GetChurnQuery(ctx *gin.Context) (domain.ChurnFilter, error) {
 	var (
-		sumQuery SummaryQueries
-		err      error
+		filter domain.ChurnFilter
+		err    error
 	)
 	errFormat := "missing required query value %s"
+	timeLayout := time.DateOnly
+
 	startStr, ok := ctx.GetQuery("start")
 	if !ok {
-		return SummaryQueries{}, fmt.Errorf(errFormat, "start")
+		return domain.ChurnFilter{}, fmt.Errorf(errFormat, "start")
 	}
 
 	endStr, ok := ctx.GetQuery("end")
 	if !ok {
-		return SummaryQueries{}, fmt.Errorf(errFormat, "end")
+		return domain.ChurnFilter{}, fmt.Errorf(errFormat, "end")
 	}
 
+	filter.Start, err = time.Parse(timeLayout, startStr)
+	if err != nil {
+		return domain.ChurnFilter{}, err
+	}
+
+	filter.End, err = time.Parse(timeLayout, endStr)
+	if err != nil {
+		return domain.ChurnFilter{}, err
+	}
+
+	filter.Bucket = domain.PeriodUnit(ctx.DefaultQuery("bucket", string(domain.PeriodMonth)))
+	filter.ServiceName, _ = ctx.GetQuery("service_name")
+
+	if err := filter.Validate(); err != nil {
+		return domain.ChurnFilter{}, err
+	}
+	return filter, nil
+}
+
+// GetAnalyticsQuery extracts admin analytics query parameters from the request context.
+// The "start" and "end" dates are required and inclusive, "limit" and "min_group_size"
+// are optional and left zero if missing, so the service defaults apply.
+func GetAnalyticsQuery(ctx *gin.Context) (domain.AnalyticsFilter, error) {
+	var (
+		filter domain.AnalyticsFilter
+		err    error
+	)
+	errFormat := "missing required query value %s"
 	timeLayout := time.DateOnly
-	sumQuery.Start, err = time.Parse(timeLayout, startStr)
+
+	startStr, ok := ctx.GetQuery("start")
+	if !ok {
+		return domain.AnalyticsFilter{}, fmt.Errorf(errFormat, "start")
+	}
+
+	endStr, ok := ctx.GetQuery("end")
+	if !ok {
+		return domain.AnalyticsFilter{}, fmt.Errorf(errFormat, "end")
+	}
+
+	filter.Start, err = time.Parse(timeLayout, startStr)
 	if err != nil {
-		return SummaryQueries{}, err
+		return domain.AnalyticsFilter{}, err
 	}
 
-	sumQuery.End, err = time.Parse(timeLayout, endStr)
+	filter.End, err = time.Parse(timeLayout, endStr)
 	if err != nil {
-		return SummaryQueries{}, err
+		return domain.AnalyticsFilter{}, err
 	}
 
-	sumQuery.UserID, _ = ctx.GetQuery("user_ID")
-	sumQuery.ServiceName, _ = ctx.GetQuery("service_name")
-	// Использование:
-	sumQuery.PageNumber, sumQuery.PageSize, err = GetPaginationArgs(ctx)
+	if filter.End.Before(filter.Start) {
+		return domain.AnalyticsFilter{}, domain.ErrInvalidDate
+	}
+
+	filter.ServiceName, _ = ctx.GetQuery("service_name")
+	filter.Bucket = domain.PeriodUnit(ctx.Query("bucket"))
+
+	for key, dest := range map[string]*int{"limit": &filter.Limit, "min_group_size": &filter.MinGroupSize} {
+		valueStr, ok := ctx.GetQuery(key)
+		if !ok {
+			continue
+		}
+		if *dest, err = strconv.Atoi(valueStr); err != nil || *dest < 1 {
+			return domain.AnalyticsFilter{}, domain.ErrInvalidAnalytics
+		}
+	}
+	return filter, nil
+}
+
+// GetTrialReportQuery extracts trial report query parameters from the request context.
+// Both "start" and "end" dates are optional and inclusive, they select trials by the start date.
+func GetTrialReportQuery(ctx *gin.Context) (domain.TrialReportFilter, error) {
+	var (
+		filter domain.TrialReportFilter
+		err    error
+	)
+	timeLayout := time.DateOnly
+
+	filter.ServiceName, _ = ctx.GetQuery("service_name")
+
+	if startStr, ok := ctx.GetQuery("start"); ok {
+		filter.Start, err = time.Parse(timeLayout, startStr)
+		if err != nil {
+			return domain.TrialReportFilter{}, err
+		}
+	}
+
+	if endStr, ok := ctx.GetQuery("end"); ok {
+		filter.End, err = time.Parse(timeLayout, endStr)
+		if err != nil {
+			return domain.TrialReportFilter{}, err
+		}
+	}
+
+	if !filter.Start.IsZero() && !filter.End.IsZero() && filter.End.Before(filter.Start) {
+		return domain.TrialReportFilter{}, domain.ErrInvalidDate
+	}
+	return filter, nil
+}
+
+// GetForecastMonths extracts the forecast horizon from the "months" query parameter,
+// zero is returned if it is missing so the default horizon is used.
+func GetForecastMonths(ctx *gin.Context) (int, error) {
+	monthsStr, ok := ctx.GetQuery("months")
+	if !ok {
+		return 0, nil
+	}
+
+	months, err := strconv.Atoi(monthsStr)
+	if err != nil || months < 1 || months > domain.MaxForecastMonths {
+		return 0, domain.ErrInvalidForecast
+	}
+	return months, nil
+}
+
+// GetAuditQuery extracts audit log query parameters from the request context.
+// Both "start" and "end" dates are optional and inclusive.
+func GetAuditQuery(ctx *gin.Context) (domain.AuditFilter, error) {
+	var (
+		filter domain.AuditFilter
+		err    error
+	)
+	timeLayout := time.DateOnly
+
+	if startStr, ok := ctx.GetQuery("start"); ok {
+		filter.Start, err = time.Parse(timeLayout, startStr)
+		if err != nil {
+			return domain.AuditFilter{}, err
+		}
+	}
+
+	if endStr, ok := ctx.GetQuery("end"); ok {
+		filter.End, err = time.Parse(timeLayout, endStr)
+		if err != nil {
+			return domain.AuditFilter{}, err
+		}
+	}
+
+	if !filter.Start.IsZero() && !filter.End.IsZero() && filter.End.Before(filter.Start) {
+		return domain.AuditFilter{}, domain.ErrInvalidDate
+	}
+
+	filter.Pagination, err = GetPaginationArgs(ctx)
 	if err != nil {
-		return sumQuery, err
+		return domain.AuditFilter{}, err
+	}
+
+	// Audit events are paged by their sequential IDs
+	for _, cursor := range []*domain.Cursor{filter.After, filter.Before} {
+		if cursor == nil {
+			continue
+		}
+		if _, err := cursor.SeqID(); err != nil {
+			return domain.AuditFilter{}, err
+		}
 	}
-	return sumQuery, nil
+	return filter, nil
 }
 
-func GetPaginationArgs(ctx *gin.Context) (int, int, error) {
-	pageNumberStr := ctx.DefaultQuery("page_number", "1") // по умолчанию 1
-	pageSizeStr := ctx.DefaultQuery("page_size", "10")    // по умолчанию 10
+// GetBoolQuery extracts an optional boolean query parameter, false by default.
+func GetBoolQuery(ctx *gin.Context, name string) (bool, error) {
+	value, ok := ctx.GetQuery(name)
+	if !ok {
+		return false, nil
+	}
 
-	pageNumber, err := strconv.Atoi(pageNumberStr)
+	b, err := strconv.ParseBool(value)
 	if err != nil {
-		return 0, 0, errors.New("page_number must be integer")
+		return false, fmt.Errorf("%s must be boolean", name)
 	}
+	return b, nil
+}
+
+// GetPaginationArgs extracts pagination query parameters from the request context.
+// Pages are addressed by the "after" or "before" cursor, "page_number" is kept as a legacy mode
+// and ignored when a cursor is provided.
+func GetPaginationArgs(ctx *gin.Context) (domain.Pagination, error) {
+	var page domain.Pagination
+	pageSizeStr := ctx.DefaultQuery("page_size", "10") // по умолчанию 10
 
 	pageSize, err := strconv.Atoi(pageSizeStr)
 	if err != nil {
-		return 0, 0, errors.New("page_size must be integer")
+		return page, errors.New("page_size must be integer")
 	}
 
-	if pageNumber < 1 {
-		return 0, 0, errors.New("page_number must be greater than 0")
+	if pageSize < 1 || pageSize > 100 {
+		return page, errors.New("page_size must be betqeen 1 and 100")
 	}
+	page.PageSize = pageSize
 
-	if pageSize < 1 || pageSize > 100 {
-		return 0, 0, errors.New("page_size must be betqeen 1 and 100")
+	after, hasAfter := ctx.GetQuery("after")
+	before, hasBefore := ctx.GetQuery("before")
+	switch {
+	case hasAfter && hasBefore:
+		return page, errors.New("only one of after and before cursors can be provided")
+	case hasAfter:
+		cursor, err := domain.DecodeCursor(after)
+		if err != nil {
+			return page, err
+		}
+		page.After = &cursor
+		return page, nil
+	case hasBefore:
+		cursor, err := domain.DecodeCursor(before)
+		if err != nil {
+			return page, err
+		}
+		page.Before = &cursor
+		return page, nil
+	}
+
+	pageNumberStr := ctx.DefaultQuery("page_number", "1") // по умолчанию 1
+	pageNumber, err := strconv.Atoi(pageNumberStr)
+	if err != nil {
+		return page, errors.New("page_number must be integer")
+	}
+
+	if pageNumber < 1 {
+		return page, errors.New("page_number must be greater than 0")
 	}
+	page.PageNumber = pageNumber
 
-	return pageNumber, pageSize, nil
+	return page, nil
 }
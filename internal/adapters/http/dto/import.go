@@ -0,0 +1,162 @@
+package dto
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importColumns are the subscription fields which can be imported, the required ones come first.
+var importColumns = []string{"service_name", "start_date", "user_id", "price", "end_date", "auto_renew"}
+
+const requiredImportColumns = 3
+
+// GetImportCSV extracts the subscriptions of the CSV file uploaded in the "file" form field.
+// The first line is the header, columns are matched to the fields by the "mapping[<field>]" form values
+// and default to the field names, so a file with a service_name,price,start_date,end_date,user_id header
+// needs no mapping. Lines which can not be parsed are returned with the error, so they are reported as failed.
+func GetImportCSV(ctx *gin.Context) ([]domain.ImportRow, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("missing required form value %s", "file")
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mapping := ctx.PostFormMap("mapping")
+	for field := range mapping {
+		if !slices.Contains(importColumns, field) {
+			return nil, fmt.Errorf("unknown mapping field %s", field)
+		}
+	}
+	return parseImportCSV(file, mapping)
+}
+
+func parseImportCSV(r io.Reader, mapping map[string]string) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	names, err := reader.Read()
+	if err != nil {
+		return nil, domain.ErrInvalidImport
+	}
+	if len(names) > 0 {
+		names[0] = strings.TrimPrefix(names[0], "\ufeff")
+	}
+
+	// Index of the column of every field in the record, optional fields may be missing
+	columns := make(map[string]int, len(importColumns))
+	for i, field := range importColumns {
+		name := field
+		if mapped, ok := mapping[field]; ok {
+			name = mapped
+		}
+
+		index := columnIndex(names, name)
+		if index < 0 && i < requiredImportColumns {
+			return nil, fmt.Errorf("%w: column %s is missing", domain.ErrInvalidImport, name)
+		}
+		if index >= 0 {
+			columns[field] = index
+		}
+	}
+
+	var rows []domain.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		// Lines with a wrong number of fields are reported, any other error means the file is not CSV
+		var parseErr *csv.ParseError
+		if err != nil && !(errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount)) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrInvalidImport, err)
+		}
+
+		if len(rows) == domain.MaxImportRows {
+			return nil, domain.ErrInvalidImport
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := domain.ImportRow{Line: line, Err: err}
+		if row.Err == nil {
+			row.Subscription, row.Err = importSubs(record, columns)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows to import", domain.ErrInvalidImport)
+	}
+	return rows, nil
+}
+
+// importSubs builds the subscription from the fields of the record, empty optional fields are left zero.
+// Like a created one, the subscription renews automatically unless it is given an end date or auto_renew says otherwise.
+func importSubs(record []string, columns map[string]int) (domain.Subscription, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var (
+		subs = domain.Subscription{ServiceName: field("service_name"), UserID: field("user_id")}
+		err  error
+	)
+	timeLayout := time.DateOnly
+
+	if price := field("price"); price != "" {
+		subs.Price, err = strconv.Atoi(price)
+		if err != nil {
+			return domain.Subscription{}, domain.ErrPriceField
+		}
+	}
+
+	if startDate := field("start_date"); startDate != "" {
+		subs.StartDate, err = time.Parse(timeLayout, startDate)
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+
+	if endDate := field("end_date"); endDate != "" {
+		subs.EndDate, err = time.Parse(timeLayout, endDate)
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+
+	subs.AutoRenew = subs.EndDate.IsZero()
+	if autoRenew := field("auto_renew"); autoRenew != "" {
+		subs.AutoRenew, err = strconv.ParseBool(autoRenew)
+		if err != nil {
+			return domain.Subscription{}, fmt.Errorf("%s must be boolean", "auto_renew")
+		}
+	}
+	return subs, nil
+}
+
+// columnIndex returns the index of the header column with the name ignoring case and surrounding spaces, or -1.
+func columnIndex(names []string, name string) int {
+	for i, n := range names {
+		if strings.EqualFold(strings.TrimSpace(n), strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,105 @@
+package routers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
+	"submanager/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles cross-user analytics routes, which require the admin token.
+type AdminHandler struct {
+	serv  domain.AnalyticsService
+	token string
+	log   logger.Logger
+}
+
+func NewAdminHandler(serv domain.AnalyticsService, token string, log logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		serv:  serv,
+		token: token,
+		log:   log,
+	}
+}
+
+// RegisterAdminRoutes registers all admin http operations behind the admin token check
+func (h *AdminHandler) RegisterAdminRoutes(r *gin.RouterGroup) {
+	r.Use(h.authorize)
+	r.GET("/analytics/services", h.ServiceStatsHandler)
+	r.GET("/analytics/prices", h.PriceStatsHandler)
+	r.GET("/analytics/users", h.ServiceUsersHandler)
+}
+
+// authorize lets through requests carrying the admin token as a bearer token.
+func (h *AdminHandler) authorize(ctx *gin.Context) {
+	token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		h.log.Error("Admin request rejected", "error", domain.ErrAdminToken, "path", ctx.Request.URL.Path)
+		httputils.SendError(ctx, http.StatusUnauthorized, domain.ErrAdminToken)
+		ctx.Abort()
+		return
+	}
+	ctx.Next()
+}
+
+// ServiceStatsHandler returns the most popular services with their user counts and total spend.
+func (h *AdminHandler) ServiceStatsHandler(ctx *gin.Context) {
+	filter, err := dto.GetAnalyticsQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get analytics queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	stats, err := h.serv.GetServiceStats(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get service stats", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// PriceStatsHandler returns the price distribution per service.
+func (h *AdminHandler) PriceStatsHandler(ctx *gin.Context) {
+	filter, err := dto.GetAnalyticsQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get analytics queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	stats, err := h.serv.GetPriceStats(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get price stats", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// ServiceUsersHandler returns user counts per service over time.
+func (h *AdminHandler) ServiceUsersHandler(ctx *gin.Context) {
+	filter, err := dto.GetAnalyticsQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get analytics queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	points, err := h.serv.GetServiceUsers(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get service users", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, points)
+}
@@ -0,0 +1,171 @@
+package routers
+
+import (
+	"net/http"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
+	"submanager/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BudgetHandler handles budget CRUDL and alert routes.
+type BudgetHandler struct {
+	serv domain.BudgetService
+	log  logger.Logger
+}
+
+func NewBudgetHandler(serv domain.BudgetService, log logger.Logger) *BudgetHandler {
+	return &BudgetHandler{
+		serv: serv,
+		log:  log,
+	}
+}
+
+// RegisterBudgetRoutes registers all budget http operations
+func (h *BudgetHandler) RegisterBudgetRoutes(r *gin.RouterGroup) {
+	r.POST("/", h.CreateBudgetHandler)
+	r.GET("/", h.ListBudgetsHandler)
+	r.GET("/alerts", h.ListAlertsHandler)
+	r.GET("/:id", h.GetBudgetHandler)
+	r.PUT("/:id", h.UpdateBudgetHandler)
+	r.DELETE("/:id", h.DeleteBudgetHandler)
+}
+
+// CreateBudgetHandler creates a new budget.
+func (h *BudgetHandler) CreateBudgetHandler(ctx *gin.Context) {
+	budget, err := dto.GetBudgetJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind budget JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	if err := validateSubsParams("notempty", budget.UserID); err != nil {
+		h.log.Error("Failed to create budget", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := h.serv.CreateBudget(ctx.Request.Context(), budget)
+	if err != nil {
+		h.log.Error("Failed to create budget", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Budget created",
+		"id":      id,
+	})
+}
+
+// ListBudgetsHandler returns budgets of the user given by the user_id query parameter
+func (h *BudgetHandler) ListBudgetsHandler(ctx *gin.Context) {
+	userID := ctx.Query("user_id")
+
+	if err := validateSubsParams("notempty", userID); err != nil {
+		h.log.Error("Failed to list budgets", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	budgets, err := h.serv.ListBudgets(ctx.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("Failed to list budgets", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, budgets)
+}
+
+// ListAlertsHandler returns budget alerts of the user, optionally of a single YYYY-MM month
+func (h *BudgetHandler) ListAlertsHandler(ctx *gin.Context) {
+	filter := domain.AlertFilter{
+		UserID: ctx.Query("user_id"),
+		Month:  ctx.Query("month"),
+	}
+
+	if err := validateSubsParams("notempty", filter.UserID); err != nil {
+		h.log.Error("Failed to list budget alerts", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	alerts, err := h.serv.GetAlerts(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to list budget alerts", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, alerts)
+}
+
+// GetBudgetHandler returns budget by its ID
+func (h *BudgetHandler) GetBudgetHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to get budget", "error", domain.ErrInvalidBudgetID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidBudgetID)
+		return
+	}
+
+	budget, err := h.serv.GetBudget(ctx.Request.Context(), id)
+	if err != nil {
+		h.log.Error("Failed to get budget", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, budget)
+}
+
+// UpdateBudgetHandler overwrites budget by its ID
+func (h *BudgetHandler) UpdateBudgetHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to update budget", "error", domain.ErrInvalidBudgetID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidBudgetID)
+		return
+	}
+
+	budget, err := dto.GetBudgetJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind budget JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+	budget.ID = id
+
+	if err := h.serv.UpdateBudget(ctx.Request.Context(), budget); err != nil {
+		h.log.Error("Failed to update budget", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Budget updated")
+}
+
+// DeleteBudgetHandler removes budget by its ID
+func (h *BudgetHandler) DeleteBudgetHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to delete budget", "error", domain.ErrInvalidBudgetID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidBudgetID)
+		return
+	}
+
+	if err := h.serv.DeleteBudget(ctx.Request.Context(), id); err != nil {
+		h.log.Error("Failed to delete budget", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Budget deleted")
+}
@@ -0,0 +1,135 @@
+package routers
+
+import (
+	"net/http"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
+	"submanager/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogHandler handles service catalog CRUDL routes.
+type CatalogHandler struct {
+	serv domain.CatalogService
+	log  logger.Logger
+}
+
+func NewCatalogHandler(serv domain.CatalogService, log logger.Logger) *CatalogHandler {
+	return &CatalogHandler{
+		serv: serv,
+		log:  log,
+	}
+}
+
+// RegisterCatalogRoutes registers all catalog http operations
+func (h *CatalogHandler) RegisterCatalogRoutes(r *gin.RouterGroup) {
+	r.POST("/", h.CreateServiceHandler)
+	r.GET("/", h.ListServicesHandler)
+	r.GET("/:id", h.GetServiceHandler)
+	r.PUT("/:id", h.UpdateServiceHandler)
+	r.DELETE("/:id", h.DeleteServiceHandler)
+}
+
+// CreateServiceHandler adds a new service to the catalog.
+func (h *CatalogHandler) CreateServiceHandler(ctx *gin.Context) {
+	service, err := dto.GetServiceJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind service JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	id, err := h.serv.CreateService(ctx.Request.Context(), service)
+	if err != nil {
+		h.log.Error("Failed to create catalog service", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Service created",
+		"id":      id,
+	})
+}
+
+// ListServicesHandler returns catalog services, optionally filtered by status
+func (h *CatalogHandler) ListServicesHandler(ctx *gin.Context) {
+	status := domain.ServiceStatus(ctx.Query("status"))
+
+	services, err := h.serv.ListServices(ctx.Request.Context(), status)
+	if err != nil {
+		h.log.Error("Failed to list catalog services", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, services)
+}
+
+// GetServiceHandler returns catalog service by its ID
+func (h *CatalogHandler) GetServiceHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to get catalog service", "error", domain.ErrInvalidServiceID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidServiceID)
+		return
+	}
+
+	service, err := h.serv.GetService(ctx.Request.Context(), id)
+	if err != nil {
+		h.log.Error("Failed to get catalog service", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, service)
+}
+
+// UpdateServiceHandler overwrites catalog service by its ID
+func (h *CatalogHandler) UpdateServiceHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to update catalog service", "error", domain.ErrInvalidServiceID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidServiceID)
+		return
+	}
+
+	service, err := dto.GetServiceJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind service JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+	service.ID = id
+
+	if err := h.serv.UpdateService(ctx.Request.Context(), service); err != nil {
+		h.log.Error("Failed to update catalog service", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Service updated")
+}
+
+// DeleteServiceHandler removes catalog service by its ID
+func (h *CatalogHandler) DeleteServiceHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to delete catalog service", "error", domain.ErrInvalidServiceID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidServiceID)
+		return
+	}
+
+	if err := h.serv.DeleteService(ctx.Request.Context(), id); err != nil {
+		h.log.Error("Failed to delete catalog service", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Service deleted")
+}
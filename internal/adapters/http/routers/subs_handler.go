@@ -26,9 +26,32 @@ func NewSubsHandler(serv domain.SubsService, log logger.Logger) *SubsHandler {
 // RegisterSubsRoutes registers all subs http operations
 func (h *SubsHandler) RegisterSubsRoutes(r *gin.RouterGroup) {
 	r.POST("/", h.CreateSubsHandler)
+	r.POST("/import", h.ImportSubsHandler)
+	r.GET("/by-id/:id", h.GetSubsByIDHandler)
+	r.PUT("/by-id/:id", h.UpdateSubsByIDHandler)
+	r.PATCH("/by-id/:id", h.PatchSubsHandler)
+	r.DELETE("/by-id/:id", h.DeleteSubsByIDHandler)
+	r.PUT("/by-id/:id/tags", h.SetSubsTagsHandler)
+	r.PUT("/by-id/:id/discounts", h.SetSubsDiscountsHandler)
+	r.PUT("/by-id/:id/participants", h.SetSubsParticipantsHandler)
+	r.POST("/by-id/:id/participants/:user_id", h.RespondToShareHandler)
 	r.GET("/:user_id/:service_name", h.GetSubsHandler)
 	r.GET("/:user_id", h.ListSubsHandler)
+	// Views of a user live under their own prefix, so they never shadow a service name
+	r.GET("/users/:user_id/audit", h.AuditLogHandler)
+	r.GET("/users/:user_id/shared", h.SharedSubsHandler)
+	r.GET("/users/:user_id/forecast", h.ForecastHandler)
+	r.POST("/:user_id/:service_name/status", h.ChangeStatusHandler)
+	r.GET("/:user_id/:service_name/history", h.StatusHistoryHandler)
+	r.GET("/:user_id/:service_name/prices", h.PriceHistoryHandler)
+	r.POST("/:user_id/:service_name/pause", h.PauseSubsHandler)
+	r.POST("/:user_id/:service_name/resume", h.ResumeSubsHandler)
+	r.POST("/:user_id/:service_name/cancel", h.CancelSubsHandler)
+	r.POST("/:user_id/:service_name/restore", h.RestoreSubsHandler)
 	r.GET("/summary", h.SummaryHandler)
+	r.GET("/trials", h.TrialReportHandler)
+	r.GET("/churn", h.ChurnReportHandler)
+	r.GET("/analytics/spend", h.SpendSeriesHandler)
 	r.PUT("/", h.UpdateSubsHandler)
 	r.DELETE("/:user_id/:service_name", h.DeleteSubsHandler)
 	r.DELETE("/:user_id", h.DeleteSubsListHandler)
@@ -49,13 +72,17 @@ func (h *SubsHandler) CreateSubsHandler(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.serv.CreateSubscription(ctx.Request.Context(), subs); err != nil {
+	id, err := h.serv.CreateSubscription(ctx.Request.Context(), subs)
+	if err != nil {
 		h.log.Error("Failed to create subscription", "error", err)
 		httputils.SendError(ctx, httputils.GetStatus(err), err)
 		return
 	}
 
-	httputils.SendMessage(ctx, http.StatusCreated, "Subscription created")
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Subscription created",
+		"id":      id,
+	})
 }
 
 // GetSubsHandler returns user subscription by specific service
@@ -79,7 +106,28 @@ func (h *SubsHandler) GetSubsHandler(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, subs)
 }
 
-// ListSubsHandler returns user subscriptions list
+// PriceHistoryHandler returns price timeline of user subscription
+func (h *SubsHandler) PriceHistoryHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to get subscription prices", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	prices, err := h.serv.GetPriceHistory(ctx.Request.Context(), serviceName, userID)
+	if err != nil {
+		h.log.Error("Failed to get subscription prices", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prices)
+}
+
+// ListSubsHandler returns user subscriptions list, optionally filtered by repeated "tag" parameters
 func (h *SubsHandler) ListSubsHandler(ctx *gin.Context) {
 	userID := ctx.Param("user_id")
 
@@ -89,7 +137,15 @@ func (h *SubsHandler) ListSubsHandler(ctx *gin.Context) {
 		return
 	}
 
-	list, err := h.serv.GetSubscriptionList(ctx, userID)
+	page, err := dto.GetPaginationArgs(ctx)
+	if err != nil {
+		h.log.Error("Failed to get pagination args", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	filter := domain.ListFilter{UserID: userID, Tags: ctx.QueryArray("tag"), Pagination: page}
+	list, err := h.serv.GetSubscriptionList(ctx, filter)
 	if err != nil {
 		h.log.Error("Failed to get subscription list", "error", err)
 		httputils.SendError(ctx, httputils.GetStatus(err), err)
@@ -114,7 +170,7 @@ func (h *SubsHandler) UpdateSubsHandler(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.serv.UpdateSubscription(ctx, subs); err != nil {
+	if err := h.serv.UpdateSubscription(ctx.Request.Context(), subs); err != nil {
 		h.log.Error("Failed to update subscription", "error", err)
 		httputils.SendError(ctx, httputils.GetStatus(err), err)
 		return
@@ -134,7 +190,7 @@ func (h *SubsHandler) DeleteSubsHandler(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.serv.DeleteSubscription(ctx, serviceName, userID); err != nil {
+	if err := h.serv.DeleteSubscription(ctx.Request.Context(), serviceName, userID); err != nil {
 		h.log.Error("Failed to delete subscription", "error", err)
 		httputils.SendError(ctx, httputils.GetStatus(err), err)
 		return
@@ -153,7 +209,7 @@ func (h *SubsHandler) DeleteSubsListHandler(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.serv.DeleteSubscriptionList(ctx, userID); err != nil {
+	if err := h.serv.DeleteSubscriptionList(ctx.Request.Context(), userID); err != nil {
 		h.log.Error("Failed to delete subscription list", "error", err)
 		httputils.SendError(ctx, httputils.GetStatus(err), err)
 		return
@@ -162,16 +218,231 @@ func (h *SubsHandler) DeleteSubsListHandler(ctx *gin.Context) {
 	httputils.SendMessage(ctx, http.StatusOK, "All user subscriptions deleted")
 }
 
+// ChangeStatusHandler moves user subscription to another lifecycle status
+func (h *SubsHandler) ChangeStatusHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to change subscription status", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := dto.GetStatusJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind status JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.ChangeSubscriptionStatus(ctx.Request.Context(), serviceName, userID, status)
+	if err != nil {
+		h.log.Error("Failed to change subscription status", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// AuditLogHandler returns audit events of user subscriptions
+func (h *SubsHandler) AuditLogHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	if err := validateSubsParams("notempty", userID); err != nil {
+		h.log.Error("Failed to get audit log", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := dto.GetAuditQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get audit log queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	filter.UserID = userID
+
+	auditLog, err := h.serv.GetAuditLog(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get audit log", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, auditLog)
+}
+
+// RestoreSubsHandler restores deleted user subscription
+func (h *SubsHandler) RestoreSubsHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to restore subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.RestoreSubscription(ctx.Request.Context(), serviceName, userID)
+	if err != nil {
+		h.log.Error("Failed to restore subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// PauseSubsHandler pauses user subscription
+func (h *SubsHandler) PauseSubsHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to pause subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.PauseSubscription(ctx.Request.Context(), serviceName, userID)
+	if err != nil {
+		h.log.Error("Failed to pause subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// ResumeSubsHandler resumes paused user subscription
+func (h *SubsHandler) ResumeSubsHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to resume subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	extendEndDate, err := dto.GetBoolQuery(ctx, "extend_end_date")
+	if err != nil {
+		h.log.Error("Failed to resume subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.ResumeSubscription(ctx.Request.Context(), serviceName, userID, extendEndDate)
+	if err != nil {
+		h.log.Error("Failed to resume subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// ImportSubsHandler creates subscriptions from an uploaded CSV file.
+// Every row is validated like a single created subscription and reported on its own.
+func (h *SubsHandler) ImportSubsHandler(ctx *gin.Context) {
+	dryRun, err := dto.GetBoolQuery(ctx, "dry_run")
+	if err != nil {
+		h.log.Error("Failed to import subscriptions", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	atomic, err := dto.GetBoolQuery(ctx, "atomic")
+	if err != nil {
+		h.log.Error("Failed to import subscriptions", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := dto.GetImportCSV(ctx)
+	if err != nil {
+		h.log.Error("Failed to read import file", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	for i := range rows {
+		if rows[i].Err == nil {
+			rows[i].Err = validateSubs(rows[i].Subscription)
+		}
+	}
+
+	report, err := h.serv.ImportSubscriptions(ctx.Request.Context(), rows, domain.ImportOptions{DryRun: dryRun, Atomic: atomic})
+	if err != nil {
+		h.log.Error("Failed to import subscriptions", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// CancelSubsHandler cancels user subscription with a reason
+func (h *SubsHandler) CancelSubsHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to cancel subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cancellation, err := dto.GetCancellationJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind cancellation JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.CancelSubscription(ctx.Request.Context(), serviceName, userID, cancellation)
+	if err != nil {
+		h.log.Error("Failed to cancel subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// StatusHistoryHandler returns status transitions of user subscription
+func (h *SubsHandler) StatusHistoryHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	serviceName := ctx.Param("service_name")
+
+	if err := validateSubsParams(serviceName, userID); err != nil {
+		h.log.Error("Failed to get subscription status history", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	history, err := h.serv.GetStatusHistory(ctx.Request.Context(), serviceName, userID)
+	if err != nil {
+		h.log.Error("Failed to get subscription status history", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
 // SummaryHandler retrieves a summary of subscriptions based on the filter.
 func (h *SubsHandler) SummaryHandler(ctx *gin.Context) {
-	summQuery, err := dto.GetSummaryQuery(ctx)
+	filter, err := dto.GetSummaryQuery(ctx)
 	if err != nil {
 		h.log.Error("Failed to get summary queries", "error", err)
 		httputils.SendError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	summResp, err := h.serv.GetSummaryByFilter(ctx, summQuery.Start, summQuery.End, summQuery.ServiceName, summQuery.UserID, summQuery.PageNumber, summQuery.PageSize)
+	summResp, err := h.serv.GetSummaryByFilter(ctx, filter)
 	if err != nil {
 		h.log.Error("Failed to get summary by filter", "error", err)
 		httputils.SendError(ctx, httputils.GetStatus(err), err)
@@ -180,3 +451,93 @@ func (h *SubsHandler) SummaryHandler(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, summResp)
 }
+
+// SpendSeriesHandler returns spend of filtered subscriptions bucketed over the date range.
+func (h *SubsHandler) SpendSeriesHandler(ctx *gin.Context) {
+	filter, err := dto.GetSpendSeriesQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get spend series queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(filter.UserID) != 0 && !IsValidUUID(filter.UserID) {
+		h.log.Error("Failed to get spend series", "error", domain.ErrInvalidUserID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidUserID)
+		return
+	}
+
+	series, err := h.serv.GetSpendSeries(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get spend series", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, series)
+}
+
+// TrialReportHandler returns trial conversions and cancellations per service.
+func (h *SubsHandler) TrialReportHandler(ctx *gin.Context) {
+	filter, err := dto.GetTrialReportQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get trial report queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := h.serv.GetTrialReport(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get trial report", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// ChurnReportHandler returns cancellations, average lifetime and reasons per service and period.
+func (h *SubsHandler) ChurnReportHandler(ctx *gin.Context) {
+	filter, err := dto.GetChurnQuery(ctx)
+	if err != nil {
+		h.log.Error("Failed to get churn report queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := h.serv.GetChurnReport(ctx.Request.Context(), filter)
+	if err != nil {
+		h.log.Error("Failed to get churn report", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// ForecastHandler returns upcoming charges of user subscriptions month by month.
+func (h *SubsHandler) ForecastHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	if err := validateSubsParams("notempty", userID); err != nil {
+		h.log.Error("Failed to get forecast", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	months, err := dto.GetForecastMonths(ctx)
+	if err != nil {
+		h.log.Error("Failed to get forecast queries", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	forecast, err := h.serv.GetForecast(ctx.Request.Context(), userID, months)
+	if err != nil {
+		h.log.Error("Failed to get forecast", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, forecast)
+}
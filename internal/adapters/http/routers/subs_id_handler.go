@@ -0,0 +1,163 @@
+package routers
+
+import (
+	"net/http"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSubsByIDHandler returns subscription by its ID
+func (h *SubsHandler) GetSubsByIDHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to get subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.GetSubscriptionByID(ctx.Request.Context(), id)
+	if err != nil {
+		h.log.Error("Failed to get subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// UpdateSubsByIDHandler overwrites subscription by its ID
+func (h *SubsHandler) UpdateSubsByIDHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to update subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := dto.GetSubsJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind subscription JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	if err := validateSubs(subs); err != nil {
+		h.log.Error("Failed to update subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	subs.ID = id
+
+	if err := h.serv.UpdateSubscriptionByID(ctx.Request.Context(), subs); err != nil {
+		h.log.Error("Failed to update subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Subscription updated")
+}
+
+// PatchSubsHandler changes provided fields of subscription by its ID
+func (h *SubsHandler) PatchSubsHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to patch subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	patch, err := dto.GetSubsPatchJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind subscription patch JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	subs, err := h.serv.PatchSubscription(ctx.Request.Context(), id, patch)
+	if err != nil {
+		h.log.Error("Failed to patch subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// DeleteSubsByIDHandler deletes subscription by its ID
+func (h *SubsHandler) DeleteSubsByIDHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to delete subscription", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.serv.DeleteSubscriptionByID(ctx.Request.Context(), id); err != nil {
+		h.log.Error("Failed to delete subscription", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Subscription deleted")
+}
+
+// SetSubsTagsHandler replaces tags of subscription by its ID
+func (h *SubsHandler) SetSubsTagsHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to set subscription tags", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	tags, err := dto.GetTagsJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind subscription tags JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	subs, err := h.serv.SetSubscriptionTags(ctx.Request.Context(), id, tags)
+	if err != nil {
+		h.log.Error("Failed to set subscription tags", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// SetSubsDiscountsHandler replaces discount phases of subscription by its ID
+func (h *SubsHandler) SetSubsDiscountsHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to set subscription discounts", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	discounts, err := dto.GetDiscountsJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind subscription discounts JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	subs, err := h.serv.SetSubscriptionDiscounts(ctx.Request.Context(), id, discounts)
+	if err != nil {
+		h.log.Error("Failed to set subscription discounts", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
@@ -0,0 +1,99 @@
+package routers
+
+import (
+	"net/http"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetSubsParticipantsHandler replaces participants sharing the subscription by its ID
+func (h *SubsHandler) SetSubsParticipantsHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to set subscription participants", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	participants, err := dto.GetParticipantsJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind subscription participants JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	for _, p := range participants {
+		if !IsValidUUID(p.UserID) {
+			h.log.Error("Failed to set subscription participants", "error", domain.ErrInvalidUserID)
+			httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidUserID)
+			return
+		}
+	}
+
+	subs, err := h.serv.SetSubscriptionParticipants(ctx.Request.Context(), id, participants)
+	if err != nil {
+		h.log.Error("Failed to set subscription participants", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// RespondToShareHandler accepts or declines the invitation of the user to share the subscription
+func (h *SubsHandler) RespondToShareHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+	userID := ctx.Param("user_id")
+
+	if err := validateSubsID(id); err != nil {
+		h.log.Error("Failed to respond to share", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := validateSubsParams("notempty", userID); err != nil {
+		h.log.Error("Failed to respond to share", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := dto.GetShareStatusJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind share status JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subs, err := h.serv.RespondToShare(ctx.Request.Context(), id, userID, status)
+	if err != nil {
+		h.log.Error("Failed to respond to share", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subs)
+}
+
+// SharedSubsHandler returns subscriptions the user has been invited to share
+func (h *SubsHandler) SharedSubsHandler(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	if err := validateSubsParams("notempty", userID); err != nil {
+		h.log.Error("Failed to get shared subscriptions", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	subsList, err := h.serv.GetSharedSubscriptions(ctx.Request.Context(), userID)
+	if err != nil {
+		h.log.Error("Failed to get shared subscriptions", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subsList)
+}
@@ -21,7 +21,8 @@ func validateSubs(subs domain.Subscription) error {
 		return fmt.Errorf(errFormat, "start_date")
 	}
 
-	if subs.Price <= 0 {
+	// Missing price is taken from the catalog service by the service layer
+	if subs.Price < 0 {
 		return domain.ErrPriceField
 	}
 
@@ -29,7 +30,8 @@ func validateSubs(subs domain.Subscription) error {
 		return domain.ErrInvalidUserID
 	}
 
-	if subs.StartDate.After(subs.EndDate) {
+	// An empty end date is derived from the billing period by the service
+	if !subs.EndDate.IsZero() && subs.StartDate.After(subs.EndDate) {
 		return domain.ErrInvalidDate
 	}
 
@@ -53,6 +55,18 @@ func validateSubsParams(serviceName, userID string) error {
 	return nil
 }
 
+// validateSubsID validates the subscription ID path parameter.
+func validateSubsID(id string) error {
+	if len(id) == 0 {
+		return fmt.Errorf("missing required query parameter %s", "id")
+	}
+
+	if !IsValidUUID(id) {
+		return domain.ErrInvalidSubsID
+	}
+	return nil
+}
+
 // xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
 // 185925eb-2114-4c2a-bae7-6fdafa58d1d5
 
@@ -0,0 +1,139 @@
+package routers
+
+import (
+	"net/http"
+	"strconv"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
+	"submanager/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook endpoint and delivery routes.
+type WebhookHandler struct {
+	serv domain.WebhookService
+	log  logger.Logger
+}
+
+func NewWebhookHandler(serv domain.WebhookService, log logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		serv: serv,
+		log:  log,
+	}
+}
+
+// RegisterWebhookRoutes registers all webhook http operations
+func (h *WebhookHandler) RegisterWebhookRoutes(r *gin.RouterGroup) {
+	r.POST("/", h.CreateWebhookHandler)
+	r.GET("/", h.ListWebhooksHandler)
+	r.GET("/dead-letters", h.ListDeadLettersHandler)
+	r.POST("/deliveries/:id/redeliver", h.RedeliverHandler)
+	r.GET("/:id", h.GetWebhookHandler)
+	r.DELETE("/:id", h.DeleteWebhookHandler)
+}
+
+// CreateWebhookHandler registers a new webhook endpoint, its secret is only returned here
+func (h *WebhookHandler) CreateWebhookHandler(ctx *gin.Context) {
+	endpoint, err := dto.GetWebhookJSON(ctx)
+	if err != nil {
+		h.log.Error("Failed to bind webhook JSON request", "error", err)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidJSON)
+		return
+	}
+
+	endpoint, err = h.serv.CreateEndpoint(ctx.Request.Context(), endpoint)
+	if err != nil {
+		h.log.Error("Failed to create webhook endpoint", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created",
+		"id":      endpoint.ID,
+		"secret":  endpoint.Secret,
+	})
+}
+
+// ListWebhooksHandler returns all webhook endpoints
+func (h *WebhookHandler) ListWebhooksHandler(ctx *gin.Context) {
+	endpoints, err := h.serv.ListEndpoints(ctx.Request.Context())
+	if err != nil {
+		h.log.Error("Failed to list webhook endpoints", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, endpoints)
+}
+
+// GetWebhookHandler returns webhook endpoint by its ID
+func (h *WebhookHandler) GetWebhookHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to get webhook endpoint", "error", domain.ErrInvalidWebhookID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidWebhookID)
+		return
+	}
+
+	endpoint, err := h.serv.GetEndpoint(ctx.Request.Context(), id)
+	if err != nil {
+		h.log.Error("Failed to get webhook endpoint", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteWebhookHandler removes webhook endpoint by its ID
+func (h *WebhookHandler) DeleteWebhookHandler(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if !IsValidUUID(id) {
+		h.log.Error("Failed to delete webhook endpoint", "error", domain.ErrInvalidWebhookID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidWebhookID)
+		return
+	}
+
+	if err := h.serv.DeleteEndpoint(ctx.Request.Context(), id); err != nil {
+		h.log.Error("Failed to delete webhook endpoint", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusOK, "Webhook deleted")
+}
+
+// ListDeadLettersHandler returns deliveries that ran out of attempts
+func (h *WebhookHandler) ListDeadLettersHandler(ctx *gin.Context) {
+	deliveries, err := h.serv.GetDeadLetters(ctx.Request.Context())
+	if err != nil {
+		h.log.Error("Failed to list dead letters", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// RedeliverHandler schedules the delivery to be sent again
+func (h *WebhookHandler) RedeliverHandler(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		h.log.Error("Failed to redeliver webhook", "error", domain.ErrInvalidDeliveryID)
+		httputils.SendError(ctx, http.StatusBadRequest, domain.ErrInvalidDeliveryID)
+		return
+	}
+
+	if err := h.serv.Redeliver(ctx.Request.Context(), id); err != nil {
+		h.log.Error("Failed to redeliver webhook", "error", err)
+		httputils.SendError(ctx, httputils.GetStatus(err), err)
+		return
+	}
+
+	httputils.SendMessage(ctx, http.StatusAccepted, "Webhook delivery scheduled")
+}
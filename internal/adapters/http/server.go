@@ -1,12 +1,15 @@
 package httpserver
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"submanager/internal/adapters/http/routers"
 	"submanager/internal/core/domain"
+	"submanager/internal/pkg/httputils"
 	"submanager/internal/pkg/logger"
 	"time"
 
@@ -19,10 +22,14 @@ type API struct {
 	server *http.Server
 }
 
-func New(host, port string, subsService domain.SubsService, log logger.Logger) *API {
+// New creates the HTTP API, admin routes are only registered if the admin token is set.
+func New(host, port string, subsService domain.SubsService, catalogService domain.CatalogService,
+	budgetService domain.BudgetService, webhookService domain.WebhookService,
+	analyticsService domain.AnalyticsService, adminToken string, log logger.Logger) *API {
 	r := gin.New()
 	SetSwagger(r)
 
+	r.Use(auditMeta)
 	r.Use(func(c *gin.Context) {
 		start := time.Now()
 		c.Next()
@@ -37,6 +44,20 @@ func New(host, port string, subsService domain.SubsService, log logger.Logger) *
 	subsHandler := routers.NewSubsHandler(subsService, log)
 	subsHandler.RegisterSubsRoutes(r.Group("/subs"))
 
+	catalogHandler := routers.NewCatalogHandler(catalogService, log)
+	catalogHandler.RegisterCatalogRoutes(r.Group("/services"))
+
+	budgetHandler := routers.NewBudgetHandler(budgetService, log)
+	budgetHandler.RegisterBudgetRoutes(r.Group("/budgets"))
+
+	webhookHandler := routers.NewWebhookHandler(webhookService, log)
+	webhookHandler.RegisterWebhookRoutes(r.Group("/webhooks"))
+
+	if len(adminToken) != 0 {
+		adminHandler := routers.NewAdminHandler(analyticsService, adminToken, log)
+		adminHandler.RegisterAdminRoutes(r.Group("/admin"))
+	}
+
 	return &API{
 		server: &http.Server{
 			Handler: r,
@@ -45,6 +66,39 @@ func New(host, port string, subsService domain.SubsService, log logger.Logger) *
 	}
 }
 
+// auditMeta stores the actor and request ID of the request in its context, so they end up in the audit log.
+// The request ID is taken from the X-Request-ID header or generated and echoed back.
+func auditMeta(c *gin.Context) {
+	meta := domain.AuditMeta{
+		Actor:     c.GetHeader("X-Actor"),
+		RequestID: c.GetHeader("X-Request-ID"),
+	}
+	if len(meta.Actor) == 0 {
+		meta.Actor = "anonymous"
+	}
+	if len(meta.RequestID) == 0 {
+		requestID, err := newRequestID()
+		if err != nil {
+			httputils.SendError(c, http.StatusInternalServerError, fmt.Errorf("generate request ID: %w", err))
+			c.Abort()
+			return
+		}
+		meta.RequestID = requestID
+	}
+
+	c.Header("X-Request-ID", meta.RequestID)
+	c.Request = c.Request.WithContext(domain.WithAuditMeta(c.Request.Context(), meta))
+	c.Next()
+}
+
+func newRequestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 func SetSwagger(r *gin.Engine) {
 	// swagger json path
 	url := ginSwagger.URL("/swagger-docs/swagger.json")
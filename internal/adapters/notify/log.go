@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+)
+
+// LogNotifier only writes notifications to the log, it is used when no delivery channel is configured.
+type LogNotifier struct {
+	log logger.Logger
+}
+
+func NewLogNotifier(log logger.Logger) *LogNotifier {
+	return &LogNotifier{
+		log: log,
+	}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	n.log.Info("Notification",
+		"kind", notification.Kind,
+		"user_id", notification.UserID,
+		"subscription_id", notification.SubsID,
+		"subject", notification.Subject,
+	)
+	return nil
+}
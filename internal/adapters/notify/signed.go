@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// Headers of a signed webhook delivery. The signature covers the timestamp and the body,
+// so receivers can reject replayed deliveries by their age.
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// SignedSender posts webhook deliveries signed with HMAC-SHA256 of their endpoint secret.
+// Any response other than 2xx is a failed delivery.
+type SignedSender struct {
+	client *http.Client
+}
+
+func NewSignedSender() *SignedSender {
+	return &SignedSender{
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (s *SignedSender) Send(ctx context.Context, delivery domain.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("webhook delivery: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, delivery.EventID)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook delivery: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook delivery: endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value of the body sent at the unix timestamp:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
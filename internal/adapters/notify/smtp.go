@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// DefaultSMTPTimeout bounds a single email delivery if SMTPConfig.Timeout is not set.
+const DefaultSMTPTimeout = 30 * time.Second
+
+// SMTPConfig describes the mail server notifications are sent through.
+// Users are only known by their IDs, so every notification goes to the single operator Inbox
+// and names the user in the X-Submanager-User header.
+// Authentication is skipped if Username is empty, e.g. for a local SMTP stand-in.
+// Timeout bounds the whole SMTP session of a single notification.
+type SMTPConfig struct {
+	Addr     string
+	From     string
+	Inbox    string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+// SMTPNotifier emails notifications to the operator inbox.
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultSMTPTimeout
+	}
+	return &SMTPNotifier{
+		cfg: cfg,
+	}
+}
+
+// Notify sends the notification in a single SMTP session. The session ends by the configured timeout
+// or the context deadline, whichever comes first, so a hung server can not hold the sender.
+func (n *SMTPNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	if err := n.send(ctx, n.message(notification)); err != nil {
+		return fmt.Errorf("email notification: %w", err)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) send(ctx context.Context, message []byte) error {
+	host, _, err := net.SplitHostPort(n.cfg.Addr)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(n.cfg.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Like smtp.SendMail, upgrade to TLS when the server offers it
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if len(n.cfg.Username) != 0 {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(n.cfg.Inbox); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message builds a plain text email, header values are stripped of line breaks.
+func (n *SMTPNotifier) message(notification domain.Notification) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", header.Replace(n.cfg.From))
+	fmt.Fprintf(&b, "To: %s\r\n", header.Replace(n.cfg.Inbox))
+	fmt.Fprintf(&b, "Subject: %s\r\n", header.Replace(notification.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "X-Submanager-User: %s\r\n", header.Replace(notification.UserID))
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(notification.Text)
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
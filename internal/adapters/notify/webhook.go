@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// webhookTimeout bounds a single delivery so a hanging endpoint does not stall the worker.
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts notifications as JSON to a single URL.
+// Any response other than 2xx is a failed delivery.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("webhook notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook notification: endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
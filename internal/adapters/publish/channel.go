@@ -0,0 +1,32 @@
+package publish
+
+import (
+	"context"
+	"submanager/internal/core/domain"
+)
+
+// ChannelPublisher hands events to in-process consumers reading from Events.
+// Publish blocks while the buffer is full, until the event is taken or the context is done.
+type ChannelPublisher struct {
+	events chan domain.Event
+}
+
+func NewChannelPublisher(buffer int) *ChannelPublisher {
+	return &ChannelPublisher{
+		events: make(chan domain.Event, buffer),
+	}
+}
+
+// Events returns the channel published events are delivered to.
+func (p *ChannelPublisher) Events() <-chan domain.Event {
+	return p.events
+}
+
+func (p *ChannelPublisher) Publish(ctx context.Context, event domain.Event) error {
+	select {
+	case p.events <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
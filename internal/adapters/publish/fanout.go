@@ -0,0 +1,22 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+)
+
+// Fanout publishes every event to all of its publishers. If any of them fails, the event
+// is published again to all of them later, so delivery is at least once: each publisher and
+// its consumers have to tolerate repeated events and deduplicate them by event ID.
+type Fanout []domain.EventPublisher
+
+func (f Fanout) Publish(ctx context.Context, event domain.Event) error {
+	var errs []error
+	for _, publisher := range f {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
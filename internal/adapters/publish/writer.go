@@ -0,0 +1,57 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"submanager/internal/core/domain"
+	"sync"
+)
+
+// WriterPublisher writes events as JSON lines, one event per line.
+type WriterPublisher struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{
+		w:   w,
+		enc: json.NewEncoder(w),
+	}
+}
+
+// NewStdoutPublisher writes events to the standard output.
+func NewStdoutPublisher() *WriterPublisher {
+	return NewWriterPublisher(os.Stdout)
+}
+
+// NewFilePublisher appends events to the file at path, creating it if needed.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open event file: %w", err)
+	}
+	return NewWriterPublisher(f), nil
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.enc.Encode(event); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying writer if it is closable, the standard output is left open.
+func (p *WriterPublisher) Close() error {
+	if c, ok := p.w.(io.Closer); ok && p.w != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
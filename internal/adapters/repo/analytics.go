@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// analyticsWindow selects live subscriptions of all users running at some moment of [$1, $2),
+// optionally of the service $3.
+const analyticsWindow = `Deleted_at IS NULL AND Start_date < $2 AND (Exp_date IS NULL OR Exp_date > $1)
+			AND ($3 = '' OR Service_name = $3)`
+
+// AnalyticsRepo aggregates subscriptions across all users.
+// Users are counted by the subscription owners, groups with fewer distinct users
+// than the minimum group size are left out in the queries themselves.
+type AnalyticsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewAnalyticsRepo(db *pgxpool.Pool) *AnalyticsRepo {
+	return &AnalyticsRepo{
+		db: db,
+	}
+}
+
+// ServiceStats returns user and subscription counts and the total spend within the window per service,
+// the services with the most users first.
+func (repo *AnalyticsRepo) ServiceStats(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.ServiceStats, error) {
+	const op = "AnalyticsRepo.ServiceStats"
+	query := `
+		SELECT Service_name, COUNT(DISTINCT User_ID), COUNT(*), SUM(subscription_cost(s, $1, $2))::BIGINT
+		FROM Subscriptions s
+		WHERE ` + analyticsWindow + `
+		GROUP BY Service_name
+		HAVING COUNT(DISTINCT User_ID) >= $4
+		ORDER BY 2 DESC, 1
+		LIMIT $5;`
+
+	rows, err := repo.db.Query(ctx, query, analyticsArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.ServiceStats, error) {
+		var s domain.ServiceStats
+		err := row.Scan(&s.ServiceName, &s.Users, &s.Subscriptions, &s.TotalSpend)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return stats, nil
+}
+
+// PriceStats returns the average and the median of the current list prices per service,
+// the services with the most subscriptions first.
+func (repo *AnalyticsRepo) PriceStats(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.PriceStats, error) {
+	const op = "AnalyticsRepo.PriceStats"
+	query := `
+		SELECT Service_name, COUNT(DISTINCT User_ID), COUNT(*),
+			round(AVG(Price), 2)::FLOAT8,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY Price)
+		FROM Subscriptions s
+		WHERE ` + analyticsWindow + `
+		GROUP BY Service_name
+		HAVING COUNT(DISTINCT User_ID) >= $4
+		ORDER BY 3 DESC, 1
+		LIMIT $5;`
+
+	rows, err := repo.db.Query(ctx, query, analyticsArgs(filter)...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.PriceStats, error) {
+		var s domain.PriceStats
+		err := row.Scan(&s.ServiceName, &s.Users, &s.Subscriptions, &s.AvgPrice, &s.MedianPrice)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return stats, nil
+}
+
+// ServiceUsers returns distinct user counts per service and bucket of the window for the services
+// with the most users within the whole window. Buckets are generated in UTC like the spend series,
+// buckets of a service with fewer users than the minimum group size are left out.
+func (repo *AnalyticsRepo) ServiceUsers(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.ServiceUsers, error) {
+	const op = "AnalyticsRepo.ServiceUsers"
+	query := `
+		WITH top AS (
+			SELECT Service_name
+			FROM Subscriptions s
+			WHERE ` + analyticsWindow + `
+			GROUP BY Service_name
+			HAVING COUNT(DISTINCT User_ID) >= $4
+			ORDER BY COUNT(DISTINCT User_ID) DESC, Service_name
+			LIMIT $5
+		), buckets AS (
+			SELECT GREATEST(g.Start, $1) AS Bucket_start,
+				LEAST(date_add(g.Start, ('1 ' || $6)::INTERVAL, 'UTC'), $2) AS Bucket_end
+			FROM generate_series(date_trunc($6, $1::TIMESTAMPTZ, 'UTC'),
+				$2::TIMESTAMPTZ - INTERVAL '1 microsecond', ('1 ' || $6)::INTERVAL, 'UTC') AS g(Start)
+		)
+		SELECT s.Service_name, b.Bucket_start, b.Bucket_end, COUNT(DISTINCT s.User_ID)
+		FROM buckets b
+		JOIN Subscriptions s ON s.Start_date < b.Bucket_end
+			AND (s.Exp_date IS NULL OR s.Exp_date > b.Bucket_start)
+		WHERE s.Deleted_at IS NULL AND s.Service_name IN (SELECT Service_name FROM top)
+		GROUP BY 1, 2, 3
+		HAVING COUNT(DISTINCT s.User_ID) >= $4
+		ORDER BY 1, 2;`
+
+	rows, err := repo.db.Query(ctx, query, append(analyticsArgs(filter), string(filter.Bucket))...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	points, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.ServiceUsers, error) {
+		var p domain.ServiceUsers
+		err := row.Scan(&p.ServiceName, &p.Start, &p.End, &p.Users)
+		return p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return points, nil
+}
+
+// analyticsArgs returns the arguments of analyticsWindow followed by the minimum group size and the limit,
+// the inclusive end date is passed as the start of the next day.
+func analyticsArgs(filter domain.AnalyticsFilter) []any {
+	return []any{filter.Start, filter.End.AddDate(0, 0, 1), filter.ServiceName, filter.MinGroupSize, filter.Limit}
+}
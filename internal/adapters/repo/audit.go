@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"submanager/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// recordAudit writes the audit event of a mutation inside the transaction making it.
+// Actor and request ID are taken from the request context, before or after are nil
+// if the subscription did not exist on that side of the mutation.
+func recordAudit(ctx context.Context, tx pgx.Tx, op domain.AuditOperation, before, after *domain.Subscription) error {
+	query := `
+		INSERT INTO Audit_events(Subscription_ID, User_ID, Actor, Operation, Before, After, Request_ID)
+		VALUES($1, $2, $3, $4, $5, $6, NULLIF($7, ''));`
+
+	subs := after
+	if subs == nil {
+		subs = before
+	}
+
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+
+	meta := domain.AuditMetaFromContext(ctx)
+	if len(meta.Actor) == 0 {
+		meta.Actor = "system"
+	}
+
+	_, err = tx.Exec(ctx, query, subs.ID, subs.UserID, meta.Actor, op, beforeJSON, afterJSON, meta.RequestID)
+	if err != nil {
+		return fmt.Errorf("record audit: %w", err)
+	}
+	return nil
+}
+
+func snapshot(subs *domain.Subscription) ([]byte, error) {
+	if subs == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(subs)
+	if err != nil {
+		return nil, fmt.Errorf("audit snapshot: %w", err)
+	}
+	return raw, nil
+}
+
+// AuditEvents returns the requested page of user audit events, newest first.
+func (repo *SubsRepo) AuditEvents(ctx context.Context, filter domain.AuditFilter) (domain.AuditPage, error) {
+	const op = "SubsRepo.AuditEvents"
+	query := `
+		SELECT ID, Subscription_ID, User_ID, Actor, Operation, Before, After, COALESCE(Request_ID, ''), Created_at
+		FROM Audit_events
+		WHERE User_ID = $1 `
+	args := []any{filter.UserID}
+
+	if !filter.Start.IsZero() {
+		args = append(args, filter.Start)
+		query += fmt.Sprintf(`AND Created_at >= $%d `, len(args))
+	}
+	if !filter.End.IsZero() {
+		// The end date is inclusive
+		args = append(args, filter.End.AddDate(0, 0, 1))
+		query += fmt.Sprintf(`AND Created_at < $%d `, len(args))
+	}
+
+	page := filter.Pagination
+	order := `Created_at DESC, ID DESC`
+	switch {
+	case page.After != nil:
+		id, err := page.After.SeqID()
+		if err != nil {
+			return domain.AuditPage{}, err
+		}
+		args = append(args, page.After.StartDate, id)
+		query += fmt.Sprintf(`AND (Created_at, ID) < ($%d, $%d) `, len(args)-1, len(args))
+	case page.Before != nil:
+		id, err := page.Before.SeqID()
+		if err != nil {
+			return domain.AuditPage{}, err
+		}
+		args = append(args, page.Before.StartDate, id)
+		query += fmt.Sprintf(`AND (Created_at, ID) > ($%d, $%d) `, len(args)-1, len(args))
+		order = `Created_at ASC, ID ASC`
+	}
+
+	query += fmt.Sprintf(`
+		ORDER BY %s
+		LIMIT %d`, order, page.PageSize+1)
+	if !page.IsKeyset() {
+		query += fmt.Sprintf(` OFFSET %d`, (page.PageNumber-1)*page.PageSize)
+	}
+
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return domain.AuditPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var auditPage domain.AuditPage
+	auditPage.Events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.AuditEvent, error) {
+		var event domain.AuditEvent
+		err := row.Scan(&event.ID, &event.SubsID, &event.UserID, &event.Actor, &event.Operation,
+			&event.Before, &event.After, &event.RequestID, &event.CreatedAt)
+		return event, err
+	})
+	if err != nil {
+		return domain.AuditPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(auditPage.Events) > page.PageSize {
+		auditPage.HasMore = true
+		auditPage.Events = auditPage.Events[:page.PageSize]
+	}
+
+	if page.Before != nil {
+		slices.Reverse(auditPage.Events)
+	}
+	return auditPage, nil
+}
@@ -0,0 +1,166 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// budgetColumns is the column list shared by every budget SELECT, in scanBudget order.
+const budgetColumns = `ID, User_ID, Amount, Category, Service_name, Thresholds`
+
+type BudgetRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewBudgetRepo(db *pgxpool.Pool) *BudgetRepo {
+	return &BudgetRepo{
+		db: db,
+	}
+}
+
+// CreateBudget stores the budget and returns its ID.
+func (repo *BudgetRepo) CreateBudget(ctx context.Context, budget domain.Budget) (string, error) {
+	const op = "BudgetRepo.CreateBudget"
+	query := `
+		INSERT INTO Budgets(User_ID, Amount, Category, Service_name, Thresholds)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING ID;`
+
+	var id string
+	err := repo.db.QueryRow(ctx, query, budget.UserID, budget.Limit, budget.Category,
+		budget.ServiceName, budget.Thresholds).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+func (repo *BudgetRepo) GetBudget(ctx context.Context, id string) (domain.Budget, error) {
+	const op = "BudgetRepo.GetBudget"
+	query := `
+		SELECT ` + budgetColumns + `
+		FROM Budgets
+		WHERE ID = $1;`
+
+	budget, err := scanBudget(repo.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Budget{}, domain.ErrBudgetNotFound
+		}
+		return domain.Budget{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return budget, nil
+}
+
+// ListBudgets returns budgets of the user, oldest first.
+func (repo *BudgetRepo) ListBudgets(ctx context.Context, userID string) ([]domain.Budget, error) {
+	const op = "BudgetRepo.ListBudgets"
+	query := `
+		SELECT ` + budgetColumns + `
+		FROM Budgets
+		WHERE User_ID = $1
+		ORDER BY Created_at, ID;`
+
+	rows, err := repo.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	budgets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Budget, error) {
+		return scanBudget(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return budgets, nil
+}
+
+// UpdateBudget overwrites the limit, scope and thresholds of the budget, the owner is kept.
+func (repo *BudgetRepo) UpdateBudget(ctx context.Context, budget domain.Budget) error {
+	const op = "BudgetRepo.UpdateBudget"
+	query := `
+		UPDATE Budgets
+		SET Amount = $1, Category = $2, Service_name = $3, Thresholds = $4
+		WHERE ID = $5;`
+
+	res, err := repo.db.Exec(ctx, query, budget.Limit, budget.Category, budget.ServiceName,
+		budget.Thresholds, budget.ID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrBudgetNotFound
+	}
+	return nil
+}
+
+// DeleteBudget removes the budget together with its alerts.
+func (repo *BudgetRepo) DeleteBudget(ctx context.Context, id string) error {
+	const op = "BudgetRepo.DeleteBudget"
+	query := `
+		DELETE FROM Budgets
+		WHERE ID = $1;`
+
+	res, err := repo.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrBudgetNotFound
+	}
+	return nil
+}
+
+// AddAlert records the alert unless the threshold has already been alerted in that month.
+func (repo *BudgetRepo) AddAlert(ctx context.Context, alert domain.BudgetAlert) (bool, error) {
+	const op = "BudgetRepo.AddAlert"
+	query := `
+		INSERT INTO Budget_alerts(Budget_ID, User_ID, Threshold, Month, Spend, Amount)
+		VALUES($1, $2, $3, to_date($4, 'YYYY-MM'), $5, $6)
+		ON CONFLICT (Budget_ID, Threshold, Month) DO NOTHING;`
+
+	res, err := repo.db.Exec(ctx, query, alert.BudgetID, alert.UserID, alert.Threshold, alert.Month,
+		alert.Spend, alert.Limit)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.RowsAffected() != 0, nil
+}
+
+// ListAlerts returns alerts of the user, newest first.
+func (repo *BudgetRepo) ListAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.BudgetAlert, error) {
+	const op = "BudgetRepo.ListAlerts"
+	query := `
+		SELECT ID, Budget_ID, User_ID, Threshold, to_char(Month, 'YYYY-MM'), Spend, Amount, Created_at
+		FROM Budget_alerts
+		WHERE User_ID = $1 AND ($2::TEXT = '' OR Month = to_date($2, 'YYYY-MM'))
+		ORDER BY Created_at DESC, ID DESC;`
+
+	rows, err := repo.db.Query(ctx, query, filter.UserID, filter.Month)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	alerts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.BudgetAlert, error) {
+		var alert domain.BudgetAlert
+		err := row.Scan(&alert.ID, &alert.BudgetID, &alert.UserID, &alert.Threshold, &alert.Month,
+			&alert.Spend, &alert.Limit, &alert.CreatedAt)
+		return alert, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return alerts, nil
+}
+
+func scanBudget(row pgx.Row) (domain.Budget, error) {
+	var budget domain.Budget
+	err := row.Scan(&budget.ID, &budget.UserID, &budget.Limit, &budget.Category, &budget.ServiceName,
+		&budget.Thresholds)
+	return budget, err
+}
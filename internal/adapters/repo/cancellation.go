@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// recordCancellation stores why the subscription has been cancelled together with its service and term,
+// so the churn stays reportable after the subscription is deleted.
+// Cancellations made without a reason are recorded with the unspecified one.
+func recordCancellation(ctx context.Context, tx pgx.Tx, change domain.StatusChange) error {
+	query := `
+		INSERT INTO Subscription_cancellations(Subscription_ID, User_ID, Service_name, Reason, Comment, Started_at,
+			Cancelled_at, Paused)
+		SELECT s.ID, s.User_ID, s.Service_name, $2, $3, COALESCE(s.Start_date, $4), $4, (
+			SELECT COALESCE(SUM(GREATEST(LEAST(COALESCE(p.Resumed_at, $4), $4)
+				- GREATEST(p.Paused_at, COALESCE(s.Start_date, $4)), INTERVAL '0')), INTERVAL '0')
+			FROM Subscription_pauses p
+			WHERE p.Subscription_ID = s.ID AND p.Paused_at < $4)
+		FROM Subscriptions s
+		WHERE s.ID = $1;`
+
+	cancellation := domain.Cancellation{Reason: domain.CancelUnspecified}
+	if change.Cancellation != nil {
+		cancellation = *change.Cancellation
+	}
+
+	_, err := tx.Exec(ctx, query, change.SubsID, cancellation.Reason, cancellation.Comment, change.ChangedAt)
+	return err
+}
+
+// ChurnReport returns the cancellations recorded within the window per service and bucket.
+// Buckets are generated in UTC with date_trunc like the spend series, only buckets with cancellations are reported.
+// The lifetime of a subscription runs from its start date to the cancellation, leaving out the time it was paused.
+func (repo *SubsRepo) ChurnReport(ctx context.Context, filter domain.ChurnFilter) ([]domain.Churn, error) {
+	const op = "SubsRepo.ChurnReport"
+	query := `
+		SELECT c.Service_name, b.Bucket_start, b.Bucket_end, c.Reason, COUNT(*),
+			SUM(GREATEST(EXTRACT(EPOCH FROM c.Cancelled_at - c.Started_at - c.Paused), 0) / 86400)::FLOAT8
+		FROM (
+			SELECT GREATEST(g.Start, $1) AS Bucket_start,
+				LEAST(date_add(g.Start, ('1 ' || $4)::INTERVAL, 'UTC'), $2) AS Bucket_end
+			FROM generate_series(date_trunc($4, $1::TIMESTAMPTZ, 'UTC'),
+				$2::TIMESTAMPTZ - INTERVAL '1 microsecond', ('1 ' || $4)::INTERVAL, 'UTC') AS g(Start)
+		) b
+		JOIN Subscription_cancellations c
+			ON c.Cancelled_at >= b.Bucket_start AND c.Cancelled_at < b.Bucket_end
+		WHERE $3 = '' OR c.Service_name = $3
+		GROUP BY 1, 2, 3, 4
+		ORDER BY 1, 2, 4;`
+
+	// The end of the window is inclusive
+	rows, err := repo.db.Query(ctx, query, filter.Start, filter.End.AddDate(0, 0, 1), filter.ServiceName, string(filter.Bucket))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		report       []domain.Churn
+		lifetimes    []float64
+		serviceName  string
+		start, end   time.Time
+		reason       domain.CancelReason
+		count        int
+		lifetimeDays float64
+	)
+	_, err = pgx.ForEachRow(rows, []any{&serviceName, &start, &end, &reason, &count, &lifetimeDays}, func() error {
+		// Rows of a bucket come one per reason in a row
+		last := len(report) - 1
+		if last < 0 || report[last].ServiceName != serviceName || !report[last].Start.Equal(start) {
+			report = append(report, domain.Churn{
+				ServiceName: serviceName,
+				Start:       start,
+				End:         end,
+				Reasons:     map[domain.CancelReason]int{},
+			})
+			lifetimes = append(lifetimes, 0)
+			last++
+		}
+		report[last].Cancellations += count
+		report[last].Reasons[reason] += count
+		lifetimes[last] += lifetimeDays
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range report {
+		report[i].AvgLifetimeDays = lifetimes[i] / float64(report[i].Cancellations)
+	}
+	return report, nil
+}
@@ -0,0 +1,289 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// serviceColumns is the column list shared by every catalog service SELECT, in scanService order.
+const serviceColumns = `ID, Name, Category, Vendor, Default_price, Status`
+
+// uniqueViolation is the Postgres error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
+type CatalogRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCatalogRepo(db *pgxpool.Pool) *CatalogRepo {
+	return &CatalogRepo{
+		db: db,
+	}
+}
+
+// CreateService adds the service with its aliases to the catalog and returns its ID.
+func (repo *CatalogRepo) CreateService(ctx context.Context, service domain.Service) (string, error) {
+	const op = "CatalogRepo.CreateService"
+	query := `
+		INSERT INTO Services(Name, Category, Vendor, Default_price, Status)
+		VALUES($1, $2, $3, $4, $5)
+		RETURNING ID;`
+
+	var id string
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, service.Name, service.Category, service.Vendor,
+			service.DefaultPrice, service.Status).Scan(&id)
+		if err != nil {
+			return err
+		}
+		return setAliases(ctx, tx, id, service)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrAliasTaken) {
+			return "", err
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+func (repo *CatalogRepo) GetService(ctx context.Context, id string) (domain.Service, error) {
+	const op = "CatalogRepo.GetService"
+	query := `
+		SELECT ` + serviceColumns + `
+		FROM Services
+		WHERE ID = $1;`
+
+	return repo.getService(ctx, op, query, id)
+}
+
+// ListServices returns catalog services ordered by name, all of them if status is empty.
+func (repo *CatalogRepo) ListServices(ctx context.Context, status domain.ServiceStatus) ([]domain.Service, error) {
+	const op = "CatalogRepo.ListServices"
+	query := `
+		SELECT ` + serviceColumns + `
+		FROM Services
+		WHERE $1 = '' OR Status = $1
+		ORDER BY Name, ID;`
+
+	rows, err := repo.db.Query(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	services, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Service, error) {
+		return scanService(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := repo.attachAliases(ctx, services); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return services, nil
+}
+
+// UpdateService overwrites the service and replaces its aliases.
+func (repo *CatalogRepo) UpdateService(ctx context.Context, service domain.Service) error {
+	const op = "CatalogRepo.UpdateService"
+	updateQuery := `
+		UPDATE Services
+		SET Name = $1, Category = $2, Vendor = $3, Default_price = $4, Status = $5
+		WHERE ID = $6;`
+	deleteAliasesQuery := `
+		DELETE FROM Service_aliases
+		WHERE Service_ID = $1;`
+
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		res, err := tx.Exec(ctx, updateQuery, service.Name, service.Category, service.Vendor,
+			service.DefaultPrice, service.Status, service.ID)
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return domain.ErrServiceNotFound
+		}
+
+		if _, err := tx.Exec(ctx, deleteAliasesQuery, service.ID); err != nil {
+			return err
+		}
+		return setAliases(ctx, tx, service.ID, service)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrServiceNotFound) || errors.Is(err, domain.ErrAliasTaken) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// DeleteService removes the service from the catalog, linked subscriptions keep their names.
+func (repo *CatalogRepo) DeleteService(ctx context.Context, id string) error {
+	const op = "CatalogRepo.DeleteService"
+	query := `
+		DELETE FROM Services
+		WHERE ID = $1;`
+
+	res, err := repo.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrServiceNotFound
+	}
+	return nil
+}
+
+// FindService finds the service by its normalized name or alias.
+func (repo *CatalogRepo) FindService(ctx context.Context, name string) (domain.Service, error) {
+	const op = "CatalogRepo.FindService"
+	return repo.getService(ctx, op, findServiceQuery, domain.NormalizeAlias(name))
+}
+
+// findServiceQuery selects the service the normalized name in $1 resolves to.
+const findServiceQuery = `
+	SELECT ` + serviceColumns + `
+	FROM Services
+	WHERE ID = (SELECT Service_ID FROM Service_aliases WHERE Normalized = $1);`
+
+// registerService returns the catalog service of the name, an unknown name is added as a pending review service.
+// A service added by a concurrent transaction in the meantime is picked up instead of failing the caller's transaction.
+func registerService(ctx context.Context, tx pgx.Tx, name string) (domain.Service, error) {
+	insertQuery := `
+		INSERT INTO Services(Name, Status)
+		VALUES($1, $2)
+		RETURNING ID;`
+
+	normalized := domain.NormalizeAlias(name)
+	service, err := scanService(tx.QueryRow(ctx, findServiceQuery, normalized))
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return service, err
+	}
+
+	// The insert runs in a savepoint, so a name taken concurrently only rolls back the insert
+	service = domain.Service{Name: name, Status: domain.ServicePendingReview}
+	err = pgx.BeginFunc(ctx, tx, func(sp pgx.Tx) error {
+		if err := sp.QueryRow(ctx, insertQuery, service.Name, service.Status).Scan(&service.ID); err != nil {
+			return err
+		}
+		return setAliases(ctx, sp, service.ID, service)
+	})
+	if errors.Is(err, domain.ErrAliasTaken) {
+		return scanService(tx.QueryRow(ctx, findServiceQuery, normalized))
+	}
+	return service, err
+}
+
+// getService selects a single service with the query and loads its aliases.
+func (repo *CatalogRepo) getService(ctx context.Context, op, query string, args ...any) (domain.Service, error) {
+	service, err := scanService(repo.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Service{}, domain.ErrServiceNotFound
+		}
+		return domain.Service{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	services := []domain.Service{service}
+	if err := repo.attachAliases(ctx, services); err != nil {
+		return domain.Service{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return services[0], nil
+}
+
+// attachAliases loads aliases of the given services, the canonical name is not listed as an alias.
+func (repo *CatalogRepo) attachAliases(ctx context.Context, services []domain.Service) error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT a.Service_ID, a.Alias
+		FROM Service_aliases a
+		JOIN Services sv ON sv.ID = a.Service_ID
+		WHERE a.Service_ID = ANY($1::UUID[]) AND a.Alias <> sv.Name
+		ORDER BY a.Alias;`
+
+	ids := make([]string, 0, len(services))
+	for _, service := range services {
+		ids = append(ids, service.ID)
+	}
+
+	rows, err := repo.db.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("attach aliases: %w", err)
+	}
+
+	aliases := make(map[string][]string)
+	var serviceID, alias string
+	_, err = pgx.ForEachRow(rows, []any{&serviceID, &alias}, func() error {
+		aliases[serviceID] = append(aliases[serviceID], alias)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attach aliases: %w", err)
+	}
+
+	for i := range services {
+		services[i].Aliases = aliases[services[i].ID]
+		if services[i].Aliases == nil {
+			services[i].Aliases = []string{}
+		}
+	}
+	return nil
+}
+
+// setAliases registers the canonical name and aliases of the service.
+// It returns ErrAliasTaken if one of them already resolves to another service.
+func setAliases(ctx context.Context, tx pgx.Tx, serviceID string, service domain.Service) error {
+	query := `
+		INSERT INTO Service_aliases(Normalized, Alias, Service_ID)
+		VALUES($1, $2, $3)
+		ON CONFLICT (Normalized) DO UPDATE SET Alias = Service_aliases.Alias
+		WHERE Service_aliases.Service_ID = EXCLUDED.Service_ID;`
+
+	for _, alias := range append([]string{service.Name}, service.Aliases...) {
+		res, err := tx.Exec(ctx, query, domain.NormalizeAlias(alias), alias, serviceID)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+				return domain.ErrAliasTaken
+			}
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return domain.ErrAliasTaken
+		}
+	}
+	return nil
+}
+
+// serviceCondition matches subscriptions of the service named by the placeholder, either by the name
+// they were stored with or through the catalog aliases of the service they are linked to,
+// so "yandex plus" finds subscriptions stored as "Yandex Plus". Columns are qualified with the prefix.
+func serviceCondition(prefix, placeholder string) string {
+	return fmt.Sprintf(`(%[1]sService_name = %[2]s OR %[1]sService_ID = (
+		SELECT Service_ID FROM Service_aliases
+		WHERE Normalized = %[3]s))`, prefix, placeholder, normalizedSQL(placeholder))
+}
+
+// normalizedSQL normalizes the name expression in SQL the same way domain.NormalizeAlias does it.
+func normalizedSQL(name string) string {
+	return fmt.Sprintf(`COALESCE(NULLIF(lower(regexp_replace(%[1]s, '[^[:alnum:]]', '', 'g')), ''), lower(trim(%[1]s)))`, name)
+}
+
+func scanService(row pgx.Row) (domain.Service, error) {
+	var service domain.Service
+	err := row.Scan(&service.ID, &service.Name, &service.Category, &service.Vendor,
+		&service.DefaultPrice, &service.Status)
+	return service, err
+}
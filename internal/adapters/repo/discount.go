@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// setDiscounts replaces discount phases of the subscription, keeping their order.
+func setDiscounts(ctx context.Context, tx pgx.Tx, subsID string, discounts []domain.Discount) error {
+	deleteQuery := `
+		DELETE FROM Subscription_discounts
+		WHERE Subscription_ID = $1;`
+	insertQuery := `
+		INSERT INTO Subscription_discounts(Subscription_ID, Position, Kind, Value, Cycles)
+		SELECT $1, d.Position - 1, d.Kind, d.Value, d.Cycles
+		FROM unnest($2::TEXT[], $3::INT[], $4::INT[]) WITH ORDINALITY AS d(Kind, Value, Cycles, Position);`
+
+	if _, err := tx.Exec(ctx, deleteQuery, subsID); err != nil {
+		return err
+	}
+	if len(discounts) == 0 {
+		return nil
+	}
+
+	kinds := make([]string, 0, len(discounts))
+	values := make([]int, 0, len(discounts))
+	cycles := make([]int, 0, len(discounts))
+	for _, d := range discounts {
+		kinds = append(kinds, string(d.Kind))
+		values = append(values, d.Value)
+		cycles = append(cycles, d.Cycles)
+	}
+
+	_, err := tx.Exec(ctx, insertQuery, subsID, kinds, values, cycles)
+	return err
+}
+
+// SetDiscounts replaces discount phases of the subscription with the given ID.
+func (repo *SubsRepo) SetDiscounts(ctx context.Context, id string, discounts []domain.Discount) error {
+	const op = "SubsRepo.SetDiscounts"
+	query := `
+		SELECT ` + subsColumns + ` FROM Subscriptions
+		WHERE ID = $1 AND Deleted_at IS NULL
+		FOR UPDATE;`
+	discountsQuery := `
+		SELECT Kind, Value, Cycles
+		FROM Subscription_discounts
+		WHERE Subscription_ID = $1
+		ORDER BY Position;`
+
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		before, err := scanSubs(tx.QueryRow(ctx, query, id))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrSubsNotFound
+			}
+			return err
+		}
+
+		rows, err := tx.Query(ctx, discountsQuery, id)
+		if err != nil {
+			return err
+		}
+		before.Discounts, err = pgx.CollectRows(rows, scanDiscount)
+		if err != nil {
+			return err
+		}
+
+		if err := setDiscounts(ctx, tx, id, discounts); err != nil {
+			return err
+		}
+
+		after := before
+		after.Discounts = discounts
+		if err := recordAudit(ctx, tx, domain.AuditUpdate, &before, &after); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, domain.EventSubsUpdated, after)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSubsNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// attachDiscounts loads discount phases of the given subscriptions in their order.
+func (repo *SubsRepo) attachDiscounts(ctx context.Context, subsList []domain.Subscription) error {
+	query := `
+		SELECT Subscription_ID, Kind, Value, Cycles
+		FROM Subscription_discounts
+		WHERE Subscription_ID = ANY($1::UUID[])
+		ORDER BY Subscription_ID, Position;`
+
+	rows, err := repo.db.Query(ctx, query, subsIDs(subsList))
+	if err != nil {
+		return fmt.Errorf("attach discounts: %w", err)
+	}
+
+	discounts := make(map[string][]domain.Discount)
+	var (
+		subsID   string
+		discount domain.Discount
+	)
+	_, err = pgx.ForEachRow(rows, []any{&subsID, &discount.Kind, &discount.Value, &discount.Cycles}, func() error {
+		discounts[subsID] = append(discounts[subsID], discount)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attach discounts: %w", err)
+	}
+
+	for i := range subsList {
+		subsList[i].Discounts = discounts[subsList[i].ID]
+	}
+	return nil
+}
+
+func scanDiscount(row pgx.CollectableRow) (domain.Discount, error) {
+	var d domain.Discount
+	err := row.Scan(&d.Kind, &d.Value, &d.Cycles)
+	return d, err
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListBillable returns live trialing and active subscriptions owned or shared by the user
+// which are running at some moment of [from, until), with their billing details.
+// Auto-renewing subscriptions run past their end date.
+func (repo *SubsRepo) ListBillable(ctx context.Context, userID string, from, until time.Time) ([]domain.Subscription, error) {
+	const op = "SubsRepo.ListBillable"
+	query := `
+		SELECT ` + subsColumns + `
+		FROM Subscriptions s
+		WHERE ` + memberCondition(`$1`) + `
+			AND Deleted_at IS NULL
+			AND Status IN ('trialing', 'active')
+			AND Start_date < $3
+			AND (Auto_renew OR Exp_date IS NULL OR Exp_date > $2)
+		ORDER BY Start_date, ID;`
+
+	rows, err := repo.db.Query(ctx, query, userID, from, until)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	subsList, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+		return scanSubs(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := repo.attachDetails(ctx, subsList); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return subsList, nil
+}
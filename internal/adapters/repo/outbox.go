@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// recordEvent writes the event of a subscription change to the outbox inside the transaction making it,
+// so the event is relayed if and only if the change is committed.
+func recordEvent(ctx context.Context, tx pgx.Tx, eventType domain.EventType, subs domain.Subscription) error {
+	query := `
+		INSERT INTO Outbox(Event_ID, Event_type, Payload, Subscription_ID)
+		VALUES($1, $2, $3, NULLIF($4, '')::UUID);`
+
+	event, err := domain.NewEvent(eventType, subs)
+	if err != nil {
+		return fmt.Errorf("record event: %w", err)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("record event: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, event.ID, event.Type, payload, subs.ID); err != nil {
+		return fmt.Errorf("record event: %w", err)
+	}
+	return nil
+}
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{
+		db: db,
+	}
+}
+
+// ClaimEvents leases due unpublished events by moving their next attempt to the end of the lease.
+// Rows locked by another relay are skipped, an event of a crashed relay is picked up after its lease.
+// An event is held back while an earlier event of the same subscription waits for a retry
+// or is leased by another relay, so events of a subscription are published in order.
+func (repo *OutboxRepo) ClaimEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	const op = "OutboxRepo.ClaimEvents"
+	query := `
+		UPDATE Outbox
+		SET Next_attempt_at = $2
+		WHERE ID IN (
+			SELECT ID FROM Outbox o
+			WHERE Published_at IS NULL AND Next_attempt_at <= $1
+				AND NOT EXISTS (
+					SELECT 1 FROM Outbox earlier
+					WHERE earlier.Subscription_ID = o.Subscription_ID AND earlier.ID < o.ID
+						AND earlier.Published_at IS NULL AND earlier.Next_attempt_at > $1
+				)
+			ORDER BY ID
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ID, Payload, Attempts;`
+
+	rows, err := repo.db.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.OutboxEvent, error) {
+		var e domain.OutboxEvent
+		err := row.Scan(&e.ID, &e.Event, &e.Attempts)
+		return e, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The update does not keep the order of the subquery
+	slices.SortFunc(events, func(a, b domain.OutboxEvent) int { return cmp.Compare(a.ID, b.ID) })
+	return events, nil
+}
+
+func (repo *OutboxRepo) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	const op = "OutboxRepo.MarkPublished"
+	query := `
+		UPDATE Outbox
+		SET Attempts = Attempts + 1, Last_error = NULL, Published_at = $2
+		WHERE ID = $1;`
+
+	if _, err := repo.db.Exec(ctx, query, id, publishedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+func (repo *OutboxRepo) MarkPublishFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	const op = "OutboxRepo.MarkPublishFailed"
+	query := `
+		UPDATE Outbox
+		SET Attempts = Attempts + 1, Last_error = $2, Next_attempt_at = $3
+		WHERE ID = $1;`
+
+	if _, err := repo.db.Exec(ctx, query, id, reason, nextAttempt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// selectPage reads one page from the set of subscriptions selected by the filtered query.
+// The filtered query must select all subscription columns followed by the cost of the row.
+// Count and cost of the whole set are read by a separate query, so they do not depend
+// on the requested page and are reported even when the page is past the end of the set.
+// Both queries run in a single snapshot, so the totals always describe the set the page is read from.
+//
+// Rows are ordered by start date and ID, newest first. One extra row is read
+// to tell whether another page follows in the reading direction.
+func (repo *SubsRepo) selectPage(ctx context.Context, filtered string, args []any, page domain.Pagination) (domain.SubsPage, error) {
+	totalsQuery := `
+		SELECT COUNT(*), COALESCE(SUM(cost), 0)::BIGINT
+		FROM (` + filtered + `) filtered`
+	query := `
+		SELECT ` + subsColumns + `
+		FROM (` + filtered + `) filtered `
+
+	totalsArgs := len(args)
+	order := `Start_date DESC, ID DESC`
+	switch {
+	case page.After != nil:
+		args = append(args, page.After.StartDate, page.After.ID)
+		query += fmt.Sprintf(`WHERE (Start_date, ID) < ($%d, $%d::UUID) `, len(args)-1, len(args))
+	case page.Before != nil:
+		args = append(args, page.Before.StartDate, page.Before.ID)
+		query += fmt.Sprintf(`WHERE (Start_date, ID) > ($%d, $%d::UUID) `, len(args)-1, len(args))
+		order = `Start_date ASC, ID ASC`
+	}
+
+	query += fmt.Sprintf(`
+		ORDER BY %s
+		LIMIT %d`, order, page.PageSize+1)
+	if !page.IsKeyset() {
+		query += fmt.Sprintf(` OFFSET %d`, (page.PageNumber-1)*page.PageSize)
+	}
+
+	var subsPage domain.SubsPage
+	err := postgres.WithSnapshot(ctx, repo.db, func(tx pgx.Tx) error {
+		// The keyset arguments are not used by the totals query
+		err := tx.QueryRow(ctx, totalsQuery, args[:totalsArgs]...).Scan(&subsPage.TotalItems, &subsPage.TotalPrice)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		subsPage.Subscriptions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+			return scanSubs(row)
+		})
+		return err
+	})
+	if err != nil {
+		return domain.SubsPage{}, err
+	}
+
+	if len(subsPage.Subscriptions) > page.PageSize {
+		subsPage.HasMore = true
+		subsPage.Subscriptions = subsPage.Subscriptions[:page.PageSize]
+	}
+
+	if page.Before != nil {
+		slices.Reverse(subsPage.Subscriptions)
+	}
+
+	if err := repo.attachDetails(ctx, subsPage.Subscriptions); err != nil {
+		return domain.SubsPage{}, err
+	}
+	return subsPage, nil
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func openPause(ctx context.Context, tx pgx.Tx, change domain.StatusChange) error {
+	query := `
+		INSERT INTO Subscription_pauses(Subscription_ID, Paused_at)
+		VALUES($1, $2);`
+
+	_, err := tx.Exec(ctx, query, change.SubsID, change.ChangedAt)
+	return err
+}
+
+// closePause resumes the open pause and optionally pushes the end date back by its length.
+func closePause(ctx context.Context, tx pgx.Tx, change domain.StatusChange) error {
+	closeQuery := `
+		UPDATE Subscription_pauses
+		SET Resumed_at = $2
+		WHERE Subscription_ID = $1 AND Resumed_at IS NULL
+		RETURNING Paused_at;`
+	extendQuery := `
+		UPDATE Subscriptions
+		SET Exp_date = Exp_date + $2::INTERVAL
+		WHERE ID = $1;`
+
+	var pausedAt time.Time
+	if err := tx.QueryRow(ctx, closeQuery, change.SubsID, change.ChangedAt).Scan(&pausedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Subscription was paused without a recorded interval, nothing to close
+			return nil
+		}
+		return err
+	}
+
+	if !change.ExtendEndDate {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, extendQuery, change.SubsID, change.ChangedAt.Sub(pausedAt))
+	return err
+}
+
+// attachPauses loads pause intervals of the given subscriptions.
+func (repo *SubsRepo) attachPauses(ctx context.Context, subsList []domain.Subscription) error {
+	query := `
+		SELECT Subscription_ID, Paused_at, Resumed_at
+		FROM Subscription_pauses
+		WHERE Subscription_ID = ANY($1::UUID[])
+		ORDER BY Paused_at;`
+
+	rows, err := repo.db.Query(ctx, query, subsIDs(subsList))
+	if err != nil {
+		return fmt.Errorf("attach pauses: %w", err)
+	}
+
+	pauses := make(map[string][]domain.Pause)
+	var (
+		subsID string
+		pause  domain.Pause
+	)
+	_, err = pgx.ForEachRow(rows, []any{&subsID, &pause.PausedAt, &pause.ResumedAt}, func() error {
+		if pause.ResumedAt != nil {
+			resumedAt := *pause.ResumedAt
+			pause.ResumedAt = &resumedAt
+		}
+		pauses[subsID] = append(pauses[subsID], pause)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attach pauses: %w", err)
+	}
+
+	for i := range subsList {
+		subsList[i].Pauses = pauses[subsList[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func addPrice(ctx context.Context, tx pgx.Tx, subsID string, point domain.PricePoint) error {
+	query := `
+		INSERT INTO Subscription_prices(Subscription_ID, Price, Effective_from)
+		VALUES($1, $2, $3);`
+
+	_, err := tx.Exec(ctx, query, subsID, point.Price, point.EffectiveFrom)
+	return err
+}
+
+// attachPrices loads price history of the given subscriptions, oldest price first.
+func (repo *SubsRepo) attachPrices(ctx context.Context, subsList []domain.Subscription) error {
+	query := `
+		SELECT Subscription_ID, Price, Effective_from
+		FROM Subscription_prices
+		WHERE Subscription_ID = ANY($1::UUID[])
+		ORDER BY Effective_from, ID;`
+
+	rows, err := repo.db.Query(ctx, query, subsIDs(subsList))
+	if err != nil {
+		return fmt.Errorf("attach prices: %w", err)
+	}
+
+	prices := make(map[string][]domain.PricePoint)
+	var (
+		subsID string
+		point  domain.PricePoint
+	)
+	_, err = pgx.ForEachRow(rows, []any{&subsID, &point.Price, &point.EffectiveFrom}, func() error {
+		prices[subsID] = append(prices[subsID], point)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attach prices: %w", err)
+	}
+
+	for i := range subsList {
+		subsList[i].PriceHistory = prices[subsList[i].ID]
+	}
+	return nil
+}
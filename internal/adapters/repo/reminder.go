@@ -0,0 +1,117 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReminderRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewReminderRepo(db *pgxpool.Pool) *ReminderRepo {
+	return &ReminderRepo{
+		db: db,
+	}
+}
+
+// EnqueueReminders schedules reminders of active and trialing subscriptions ending within (from, until].
+// End dates that already have a reminder are skipped, whatever its status.
+func (repo *ReminderRepo) EnqueueReminders(ctx context.Context, from, until time.Time) (int64, error) {
+	const op = "ReminderRepo.EnqueueReminders"
+	query := `
+		INSERT INTO Reminders(Subscription_ID, User_ID, Service_name, End_date)
+		SELECT ID, User_ID, Service_name, Exp_date
+		FROM Subscriptions
+		WHERE Deleted_at IS NULL AND Status IN ('active', 'trialing') AND Exp_date > $1 AND Exp_date <= $2
+		ON CONFLICT (Subscription_ID, End_date) DO NOTHING;`
+
+	res, err := repo.db.Exec(ctx, query, from, until)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// ClaimReminders leases due pending reminders by moving their next attempt to the end of the lease.
+// Rows locked by another worker are skipped, a reminder of a crashed worker is picked up after its lease.
+// Due reminders whose subscription has been deleted, is no longer active or trialing,
+// or no longer ends on the reminded date are closed as skipped instead of claimed.
+func (repo *ReminderRepo) ClaimReminders(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Reminder, error) {
+	const op = "ReminderRepo.ClaimReminders"
+	query := `
+		WITH due AS (
+			SELECT r.ID, s.Deleted_at IS NULL AND s.Status IN ('active', 'trialing')
+				AND s.Exp_date IS NOT DISTINCT FROM r.End_date AS live
+			FROM Reminders r
+			JOIN Subscriptions s ON s.ID = r.Subscription_ID
+			WHERE r.Status = 'pending' AND r.Next_attempt_at <= $1
+			ORDER BY r.Next_attempt_at, r.ID
+			LIMIT $3
+			FOR UPDATE OF r SKIP LOCKED
+		), skipped AS (
+			UPDATE Reminders
+			SET Status = 'skipped'
+			WHERE ID IN (SELECT ID FROM due WHERE NOT live)
+		)
+		UPDATE Reminders
+		SET Next_attempt_at = $2
+		WHERE ID IN (SELECT ID FROM due WHERE live)
+		RETURNING ID, Subscription_ID, User_ID, Service_name, End_date, Status, Attempts,
+			COALESCE(Last_error, ''), Next_attempt_at, Sent_at;`
+
+	rows, err := repo.db.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reminders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Reminder, error) {
+		var r domain.Reminder
+		err := row.Scan(&r.ID, &r.SubsID, &r.UserID, &r.ServiceName, &r.EndDate, &r.Status, &r.Attempts,
+			&r.LastError, &r.NextAttemptAt, &r.SentAt)
+		return r, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return reminders, nil
+}
+
+func (repo *ReminderRepo) MarkReminderSent(ctx context.Context, id int64, sentAt time.Time) error {
+	const op = "ReminderRepo.MarkReminderSent"
+	query := `
+		UPDATE Reminders
+		SET Status = 'sent', Attempts = Attempts + 1, Sent_at = $2
+		WHERE ID = $1;`
+
+	if _, err := repo.db.Exec(ctx, query, id, sentAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MarkReminderFailed records the failed attempt, a zero nextAttempt gives the reminder up.
+func (repo *ReminderRepo) MarkReminderFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	const op = "ReminderRepo.MarkReminderFailed"
+	query := `
+		UPDATE Reminders
+		SET Attempts = Attempts + 1, Last_error = $2,
+			Status = CASE WHEN $3::TIMESTAMPTZ IS NULL THEN 'failed' ELSE Status END,
+			Next_attempt_at = COALESCE($3, Next_attempt_at)
+		WHERE ID = $1;`
+
+	var next *time.Time
+	if !nextAttempt.IsZero() {
+		next = &nextAttempt
+	}
+
+	if _, err := repo.db.Exec(ctx, query, id, reason, next); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
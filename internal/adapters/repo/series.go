@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SpendSeries returns the cost and the count of the filtered subscriptions per bucket of the window.
+// Buckets are generated in UTC with date_trunc, so weeks start on Mondays, and the first and the last one
+// are cut by the window. Every subscription is charged for the part of the bucket it runs in,
+// so cycles crossing bucket boundaries are prorated between the buckets.
+func (repo *SubsRepo) SpendSeries(ctx context.Context, filter domain.SpendSeriesFilter) ([]domain.SpendPoint, error) {
+	const op = "SubsRepo.SpendSeries"
+	where, args := filterConditions(domain.SummaryFilter{
+		Start:       filter.Start,
+		End:         filter.End,
+		Mode:        domain.FilterOverlap,
+		ServiceName: filter.ServiceName,
+		UserID:      filter.UserID,
+		Tags:        filter.Tags,
+	})
+	args = append(args, string(filter.Bucket))
+	step := fmt.Sprintf(`('1 ' || $%d)::INTERVAL`, len(args))
+
+	// Subscriptions are joined to the buckets, so buckets without spend are kept with zero totals
+	query := `
+		SELECT b.Bucket_start, b.Bucket_end,
+			COALESCE(SUM(CASE WHEN s.ID IS NULL THEN 0
+				ELSE subscription_cost(s, b.Bucket_start, b.Bucket_end, $3::UUID) END), 0)::BIGINT,
+			COUNT(s.ID) FILTER (WHERE s.Start_date < b.Bucket_end
+				AND (s.Exp_date IS NULL OR s.Exp_date > b.Bucket_start))
+		FROM (
+			SELECT GREATEST(g.Start, $1) AS Bucket_start,
+				LEAST(date_add(g.Start, ` + step + `, 'UTC'), $2) AS Bucket_end
+			FROM generate_series(date_trunc($` + fmt.Sprint(len(args)) + `, $1::TIMESTAMPTZ, 'UTC'),
+				$2::TIMESTAMPTZ - INTERVAL '1 microsecond', ` + step + `, 'UTC') AS g(Start)
+		) b
+		LEFT JOIN Subscriptions s ON ` + where + `
+		GROUP BY 1, 2
+		ORDER BY 1;`
+
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	points, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.SpendPoint, error) {
+		var point domain.SpendPoint
+		err := row.Scan(&point.Start, &point.End, &point.TotalPrice, &point.TotalItems)
+		return point, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return points, nil
+}
@@ -0,0 +1,210 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// memberCondition matches subscriptions aliased as s which are owned by the user in the placeholder
+// or shared with the user by an accepted invitation.
+func memberCondition(placeholder string) string {
+	return fmt.Sprintf(`(s.User_ID = %[1]s OR EXISTS (
+			SELECT 1 FROM Subscription_participants sp
+			WHERE sp.Subscription_ID = s.ID AND sp.User_ID = %[1]s AND sp.Status = 'accepted'))`, placeholder)
+}
+
+// SetParticipants replaces participants of the subscription with the given ID.
+// Participants who stay keep their answer with the new terms, new and declined ones are invited.
+func (repo *SubsRepo) SetParticipants(ctx context.Context, id string, participants []domain.Participant) error {
+	const op = "SubsRepo.SetParticipants"
+	deleteQuery := `
+		DELETE FROM Subscription_participants
+		WHERE Subscription_ID = $1 AND User_ID <> ALL($2::UUID[]);`
+	upsertQuery := `
+		INSERT INTO Subscription_participants(Subscription_ID, User_ID, Weight, Amount)
+		SELECT $1, p.User_ID, p.Weight, p.Amount
+		FROM unnest($2::UUID[], $3::INT[], $4::INT[]) AS p(User_ID, Weight, Amount)
+		ON CONFLICT (Subscription_ID, User_ID) DO UPDATE
+		SET Weight = EXCLUDED.Weight, Amount = EXCLUDED.Amount,
+			Status = CASE WHEN Subscription_participants.Status = 'declined'
+				THEN 'invited' ELSE Subscription_participants.Status END,
+			Invited_at = CASE WHEN Subscription_participants.Status = 'declined'
+				THEN NOW() ELSE Subscription_participants.Invited_at END,
+			Responded_at = CASE WHEN Subscription_participants.Status = 'declined'
+				THEN NULL ELSE Subscription_participants.Responded_at END;`
+
+	users := make([]string, 0, len(participants))
+	weights := make([]int, 0, len(participants))
+	amounts := make([]int, 0, len(participants))
+	for _, p := range participants {
+		users = append(users, p.UserID)
+		weights = append(weights, p.Weight)
+		amounts = append(amounts, p.Amount)
+	}
+
+	err := repo.changeShares(ctx, id, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, deleteQuery, id, users); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, upsertQuery, id, users, weights, amounts)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSubsNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// SetShareStatus records the answer of the participant, provided it still has the expected status.
+func (repo *SubsRepo) SetShareStatus(ctx context.Context, id, userID string, from, to domain.ShareStatus, at time.Time) error {
+	const op = "SubsRepo.SetShareStatus"
+	query := `
+		UPDATE Subscription_participants
+		SET Status = $4, Responded_at = $5
+		WHERE Subscription_ID = $1 AND User_ID = $2 AND Status = $3;`
+
+	err := repo.changeShares(ctx, id, func(tx pgx.Tx) error {
+		res, err := tx.Exec(ctx, query, id, userID, from, to, at)
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return domain.ErrStatusConflict
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSubsNotFound) || errors.Is(err, domain.ErrStatusConflict) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// changeShares locks the live subscription, applies the change to its participants
+// and records the change in the audit log and the outbox.
+func (repo *SubsRepo) changeShares(ctx context.Context, id string, change func(tx pgx.Tx) error) error {
+	query := `
+		SELECT ` + subsColumns + ` FROM Subscriptions
+		WHERE ID = $1 AND Deleted_at IS NULL
+		FOR UPDATE;`
+
+	return postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		before, err := scanSubs(tx.QueryRow(ctx, query, id))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrSubsNotFound
+			}
+			return err
+		}
+
+		if before.Participants, err = participantsOf(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		after := before
+		if after.Participants, err = participantsOf(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, domain.AuditUpdate, &before, &after); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, domain.EventSubsUpdated, after)
+	})
+}
+
+func participantsOf(ctx context.Context, tx pgx.Tx, id string) ([]domain.Participant, error) {
+	query := `
+		SELECT User_ID, Weight, Amount, Status, Invited_at, Responded_at
+		FROM Subscription_participants
+		WHERE Subscription_ID = $1
+		ORDER BY Invited_at, User_ID;`
+
+	rows, err := tx.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Participant, error) {
+		var p domain.Participant
+		err := row.Scan(&p.UserID, &p.Weight, &p.Amount, &p.Status, &p.InvitedAt, &p.RespondedAt)
+		return p, err
+	})
+}
+
+// SharedWith returns live subscriptions the user has been invited to share, whatever the answer.
+func (repo *SubsRepo) SharedWith(ctx context.Context, userID string) ([]domain.Subscription, error) {
+	const op = "SubsRepo.SharedWith"
+	query := `
+		SELECT ` + subsColumns + `
+		FROM Subscriptions
+		WHERE Deleted_at IS NULL AND ID IN (
+			SELECT Subscription_ID FROM Subscription_participants WHERE User_ID = $1)
+		ORDER BY Start_date DESC, ID DESC;`
+
+	rows, err := repo.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	subsList, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+		return scanSubs(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := repo.attachDetails(ctx, subsList); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return subsList, nil
+}
+
+// attachParticipants loads participants of the given subscriptions in the order they were invited.
+func (repo *SubsRepo) attachParticipants(ctx context.Context, subsList []domain.Subscription) error {
+	query := `
+		SELECT Subscription_ID, User_ID, Weight, Amount, Status, Invited_at, Responded_at
+		FROM Subscription_participants
+		WHERE Subscription_ID = ANY($1::UUID[])
+		ORDER BY Invited_at, User_ID;`
+
+	rows, err := repo.db.Query(ctx, query, subsIDs(subsList))
+	if err != nil {
+		return fmt.Errorf("attach participants: %w", err)
+	}
+
+	participants := make(map[string][]domain.Participant)
+	var (
+		subsID string
+		p      domain.Participant
+	)
+	_, err = pgx.ForEachRow(rows, []any{&subsID, &p.UserID, &p.Weight, &p.Amount, &p.Status, &p.InvitedAt, &p.RespondedAt}, func() error {
+		participant := p
+		if p.RespondedAt != nil {
+			respondedAt := *p.RespondedAt
+			participant.RespondedAt = &respondedAt
+		}
+		participants[subsID] = append(participants[subsID], participant)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attach participants: %w", err)
+	}
+
+	for i := range subsList {
+		subsList[i].Participants = participants[subsList[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ChangeStatus moves the subscription to the new status and records the transition.
+// The update only succeeds if the subscription still has the expected previous status.
+// Pausing opens a pause interval and leaving the paused status closes it.
+// Cancelling records the cancellation with its reason.
+// The event carries the subscription after the change, including an extended end date.
+func (repo *SubsRepo) ChangeStatus(ctx context.Context, change domain.StatusChange) error {
+	const op = "SubsRepo.ChangeStatus"
+	updateQuery := `
+		UPDATE Subscriptions
+		SET Status = $1, Status_changed_at = $2
+		WHERE ID = $3 AND Status = $4;`
+	historyQuery := `
+		INSERT INTO Subscription_status_history(Subscription_ID, From_status, To_status, Changed_at)
+		VALUES($1, $2, $3, $4);`
+	selectQuery := `
+		SELECT ` + subsColumns + `
+		FROM Subscriptions
+		WHERE ID = $1;`
+
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		res, err := tx.Exec(ctx, updateQuery, change.To, change.ChangedAt, change.SubsID, change.From)
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() == 0 {
+			return domain.ErrStatusConflict
+		}
+
+		if _, err := tx.Exec(ctx, historyQuery, change.SubsID, change.From, change.To, change.ChangedAt); err != nil {
+			return err
+		}
+
+		switch {
+		case change.To == domain.StatusPaused:
+			err = openPause(ctx, tx, change)
+		case change.From == domain.StatusPaused:
+			err = closePause(ctx, tx, change)
+		}
+		if err != nil {
+			return err
+		}
+		if change.To == domain.StatusCancelled {
+			if err := recordCancellation(ctx, tx, change); err != nil {
+				return err
+			}
+		}
+
+		changed, err := scanSubs(tx.QueryRow(ctx, selectQuery, change.SubsID))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, domain.EventSubsStatusChanged, changed)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// StatusHistory returns all recorded status transitions of the subscription, oldest first.
+func (repo *SubsRepo) StatusHistory(ctx context.Context, subsID string) ([]domain.StatusChange, error) {
+	const op = "SubsRepo.StatusHistory"
+	query := `
+		SELECT Subscription_ID, From_status, To_status, Changed_at
+		FROM Subscription_status_history
+		WHERE Subscription_ID = $1
+		ORDER BY Changed_at, ID;`
+
+	rows, err := repo.db.Query(ctx, query, subsID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.StatusChange, error) {
+		var change domain.StatusChange
+		err := row.Scan(&change.SubsID, &change.From, &change.To, &change.ChangedAt)
+		return change, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return history, nil
+}
+
+// RenewEnded moves the end date of live auto-renewing subscriptions whose term ended by now
+// and which are neither expired nor cancelled to the end of the running term,
+// publishes the renewals and returns how many subscriptions were renewed.
+// Subscriptions locked by a concurrent change are left for the next run.
+func (repo *SubsRepo) RenewEnded(ctx context.Context, now time.Time) (int64, error) {
+	const op = "SubsRepo.RenewEnded"
+	selectQuery := `
+		SELECT ` + subsColumns + `
+		FROM Subscriptions
+		WHERE Auto_renew AND Exp_date <= $1 AND Status NOT IN ($2, $3) AND Deleted_at IS NULL
+		ORDER BY Exp_date, ID
+		FOR UPDATE SKIP LOCKED;`
+	updateQuery := `
+		UPDATE Subscriptions
+		SET Exp_date = $1
+		WHERE ID = $2;`
+
+	var count int64
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, selectQuery, now, domain.StatusExpired, domain.StatusCancelled)
+		if err != nil {
+			return err
+		}
+
+		locked, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+			return scanSubs(row)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, subs := range locked {
+			subs.EndDate = subs.RenewedEndDate(now)
+			if _, err := tx.Exec(ctx, updateQuery, subs.EndDate, subs.ID); err != nil {
+				return err
+			}
+			if err := recordEvent(ctx, tx, domain.EventSubsRenewed, subs); err != nil {
+				return err
+			}
+		}
+		count = int64(len(locked))
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count, nil
+}
+
+// ExpireEnded moves live subscriptions that ended by now, do not renew and are neither expired nor cancelled
+// to the expired status, records the transitions with their events and returns how many were expired.
+// Subscriptions locked by a concurrent status change are left for the next run.
+func (repo *SubsRepo) ExpireEnded(ctx context.Context, now time.Time) (int64, error) {
+	const op = "SubsRepo.ExpireEnded"
+	selectQuery := `
+		SELECT ` + subsColumns + `
+		FROM Subscriptions
+		WHERE NOT Auto_renew AND Exp_date <= $1 AND Status NOT IN ($2, $3) AND Deleted_at IS NULL
+		ORDER BY Exp_date, ID
+		FOR UPDATE SKIP LOCKED;`
+
+	count, err := repo.moveLocked(ctx, selectQuery, []any{now, domain.StatusExpired, domain.StatusCancelled},
+		domain.StatusExpired, domain.EventSubsExpired, func(domain.Subscription) time.Time { return now })
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count, nil
+}
+
+// moveLocked moves the subscriptions locked by selectQuery to the given status in a single transaction.
+// Each transition is recorded in the status history at changedAt and published as an event of eventType,
+// an open pause of a paused subscription is closed.
+func (repo *SubsRepo) moveLocked(ctx context.Context, selectQuery string, args []any, to domain.Status,
+	eventType domain.EventType, changedAt func(domain.Subscription) time.Time) (int64, error) {
+	updateQuery := `
+		UPDATE Subscriptions
+		SET Status = $1, Status_changed_at = $2
+		WHERE ID = $3;`
+	historyQuery := `
+		INSERT INTO Subscription_status_history(Subscription_ID, From_status, To_status, Changed_at)
+		VALUES($1, $2, $3, $4);`
+
+	var count int64
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, selectQuery, args...)
+		if err != nil {
+			return err
+		}
+
+		locked, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+			return scanSubs(row)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, subs := range locked {
+			change := domain.StatusChange{SubsID: subs.ID, From: subs.Status, To: to, ChangedAt: changedAt(subs)}
+			if _, err := tx.Exec(ctx, updateQuery, change.To, change.ChangedAt, change.SubsID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, historyQuery, change.SubsID, change.From, change.To, change.ChangedAt); err != nil {
+				return err
+			}
+			if change.From == domain.StatusPaused {
+				if err := closePause(ctx, tx, change); err != nil {
+					return err
+				}
+			}
+
+			subs.Status, subs.StatusChanged = change.To, change.ChangedAt
+			if err := recordEvent(ctx, tx, eventType, subs); err != nil {
+				return err
+			}
+		}
+		count = int64(len(locked))
+		return nil
+	})
+	return count, err
+}
@@ -5,12 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// subsColumns is the column list shared by every subscription SELECT, in scanSubs order.
+const subsColumns = `ID, Service_name, COALESCE(Service_ID::TEXT, ''), Price, User_ID, Start_date, Exp_date,
+	Period_unit, Period_interval, Status, Status_changed_at, Trial_end, Trial_price, Auto_renew`
+
 type SubsRepo struct {
 	db *pgxpool.Pool
 }
@@ -25,7 +30,7 @@ func (repo *SubsRepo) IsUnique(ctx context.Context, serviceName, userID string)
 	const op = "SubsRepo.IsUnique"
 	query := `
 		SELECT COUNT(*) = 0 FROM Subscriptions
-		WHERE Service_name = $1 AND User_ID = $2`
+		WHERE ` + serviceCondition("", "$1") + ` AND User_ID = $2 AND Deleted_at IS NULL`
 
 	var unique bool
 	if err := repo.db.QueryRow(ctx, query, serviceName, userID).Scan(&unique); err != nil {
@@ -35,163 +40,481 @@ func (repo *SubsRepo) IsUnique(ctx context.Context, serviceName, userID string)
 	return unique, nil
 }
 
-// Creates a new subscription in the database
-func (repo *SubsRepo) Create(ctx context.Context, subs domain.Subscription) error {
+// Creates a new subscription in the database and returns its ID
+func (repo *SubsRepo) Create(ctx context.Context, subs domain.Subscription) (string, error) {
 	const op = "SubsRepo.Create"
+
+	var id string
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		created, err := createSubs(ctx, tx, subs)
+		id = created.ID
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// CreateBatch creates all the subscriptions in a single transaction, so either all of them are stored or none.
+func (repo *SubsRepo) CreateBatch(ctx context.Context, subsList []domain.Subscription) ([]string, error) {
+	const op = "SubsRepo.CreateBatch"
+
+	ids := make([]string, 0, len(subsList))
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		for _, subs := range subsList {
+			created, err := createSubs(ctx, tx, subs)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, created.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+// createSubs inserts the subscription linked to its catalog service with its initial price, tags and discounts
+// and records the creation in the audit log and the outbox.
+// A subscription created active with a trial that has already ended records the conversion at the end of the trial.
+func createSubs(ctx context.Context, tx pgx.Tx, subs domain.Subscription) (domain.Subscription, error) {
+	if subs.PendingService {
+		service, err := registerService(ctx, tx, subs.ServiceName)
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+		subs.ServiceID, subs.ServiceName = service.ID, service.Name
+	}
+
 	query := `
-		INSERT INTO Subscriptions(Service_name, Price, User_ID, Start_date, Exp_date)
-		VALUES($1, $2, $3, $4, $5)
-		RETURNING User_ID;
+		INSERT INTO Subscriptions(Service_name, Price, User_ID, Start_date, Exp_date, Period_unit, Period_interval, Status,
+			Service_ID, Trial_end, Trial_price, Auto_renew)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '')::UUID, $10, $11, $12)
+		RETURNING ` + subsColumns + `;
 	`
 
-	_, err := repo.db.Exec(ctx, query, subs.ServiceName, subs.Price, subs.UserID, subs.StartDate, subs.EndDate)
+	created, err := scanSubs(tx.QueryRow(ctx, query, subs.ServiceName, subs.Price, subs.UserID, subs.StartDate,
+		subs.EndDate, subs.BillingPeriod.Unit, subs.BillingPeriod.Interval, subs.Status, subs.ServiceID,
+		subs.TrialEndDate, subs.TrialPrice, subs.AutoRenew))
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return domain.Subscription{}, err
 	}
 
-	return nil
+	if created.Status == domain.StatusActive && created.TrialEndDate != nil {
+		historyQuery := `
+			INSERT INTO Subscription_status_history(Subscription_ID, From_status, To_status, Changed_at)
+			VALUES($1, $2, $3, $4);`
+		_, err := tx.Exec(ctx, historyQuery, created.ID, domain.StatusTrialing, domain.StatusActive, *created.TrialEndDate)
+		if err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+
+	// The initial price is in effect from the start of the subscription
+	err = addPrice(ctx, tx, created.ID, domain.PricePoint{Price: subs.Price, EffectiveFrom: subs.StartDate})
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	if err := setTags(ctx, tx, created.ID, subs.Tags); err != nil {
+		return domain.Subscription{}, err
+	}
+	if err := setDiscounts(ctx, tx, created.ID, subs.Discounts); err != nil {
+		return domain.Subscription{}, err
+	}
+	created.Tags, created.Discounts = subs.Tags, subs.Discounts
+
+	if err := recordAudit(ctx, tx, domain.AuditCreate, nil, &created); err != nil {
+		return domain.Subscription{}, err
+	}
+	return created, recordEvent(ctx, tx, domain.EventSubsCreated, created)
 }
 
+// Get returns the subscription of the service, or ErrSubsAmbiguous if the user holds several of them.
 func (repo *SubsRepo) Get(ctx context.Context, serviceName, userID string) (domain.Subscription, error) {
 	const op = "SubsRepo.Get"
+	return repo.getOne(ctx, op, serviceCondition("", "$1")+` AND User_ID = $2`, serviceName, userID)
+}
+
+func (repo *SubsRepo) GetByID(ctx context.Context, id string) (domain.Subscription, error) {
+	const op = "SubsRepo.GetByID"
+	return repo.getOne(ctx, op, `ID = $1`, id)
+}
+
+// getOne selects the single live subscription matching the condition with its details.
+func (repo *SubsRepo) getOne(ctx context.Context, op, where string, args ...any) (domain.Subscription, error) {
 	query := `
-		SELECT Service_name, Price, User_ID, Start_date, Exp_date 
+		SELECT ` + subsColumns + `
 		FROM Subscriptions
-		WHERE Service_name = $1 AND User_ID = $2;
+		WHERE ` + where + ` AND Deleted_at IS NULL
+		LIMIT 2;
 	`
-	var subs domain.Subscription
-	if err := repo.db.QueryRow(ctx, query, serviceName, userID).Scan(&subs.ServiceName, &subs.Price, &subs.UserID, &subs.StartDate, &subs.EndDate); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.Subscription{}, domain.ErrSubsNotFound
-		}
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return domain.Subscription{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	subsList, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+		return scanSubs(row)
+	})
+	if err != nil {
+		return domain.Subscription{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	switch len(subsList) {
+	case 0:
+		return domain.Subscription{}, domain.ErrSubsNotFound
+	case 1:
+	default:
+		return domain.Subscription{}, domain.ErrSubsAmbiguous
+	}
+
+	if err := repo.attachDetails(ctx, subsList); err != nil {
 		return domain.Subscription{}, fmt.Errorf("%s: %w", op, err)
 	}
-	return subs, nil
+	return subsList[0], nil
 }
 
-func (repo *SubsRepo) List(ctx context.Context, userID string) ([]domain.Subscription, error) {
+// List returns the requested page of subscriptions owned or shared by the user,
+// tagged with any of filter.Tags, or all of them.
+func (repo *SubsRepo) List(ctx context.Context, filter domain.ListFilter) (domain.SubsPage, error) {
 	const op = "SubsRepo.List"
 	query := `
-		SELECT Service_name, Price, User_ID, Start_date, Exp_date 
-		FROM Subscriptions
-		WHERE User_ID = $1;
-	`
-	rows, err := repo.db.Query(ctx, query, userID)
+		SELECT s.*, 0::BIGINT AS cost
+		FROM Subscriptions s
+		WHERE ` + memberCondition(`$1`) + ` AND Deleted_at IS NULL `
+	args := []any{filter.UserID}
+
+	if len(filter.Tags) != 0 {
+		var condition string
+		condition, args = tagCondition(filter.Tags, args)
+		query += condition
+	}
+
+	subsPage, err := repo.selectPage(ctx, query, args, filter.Pagination)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrSubsNotFound
-		}
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return domain.SubsPage{}, fmt.Errorf("%s: %w", op, err)
 	}
+	return subsPage, nil
+}
 
-	subsList, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
-		var subs domain.Subscription
-		if err := row.Scan(&subs.ServiceName, &subs.Price, &subs.UserID, &subs.StartDate, &subs.EndDate); err != nil {
-			return domain.Subscription{}, fmt.Errorf("%s: %w", op, err)
+// Update overwrites the subscription of the service and records a new price point if the price has changed.
+// It returns the updated subscription without its details.
+func (repo *SubsRepo) Update(ctx context.Context, subs domain.Subscription) (domain.Subscription, error) {
+	const op = "SubsRepo.Update"
+	return repo.update(ctx, op, subs, serviceCondition("", "$1")+` AND User_ID = $2`, subs.ServiceName, subs.UserID)
+}
+
+// UpdateByID overwrites the subscription with subs.ID.
+func (repo *SubsRepo) UpdateByID(ctx context.Context, subs domain.Subscription) (domain.Subscription, error) {
+	const op = "SubsRepo.UpdateByID"
+	return repo.update(ctx, op, subs, `ID = $1`, subs.ID)
+}
+
+// update locks the single live subscription matching the condition and overwrites its terms.
+// A subscription not linked to the catalog yet is linked to the service its name resolves to.
+func (repo *SubsRepo) update(ctx context.Context, op string, subs domain.Subscription, where string, args ...any) (domain.Subscription, error) {
+	selectQuery := `
+		SELECT ` + subsColumns + ` FROM Subscriptions
+		WHERE ` + where + ` AND Deleted_at IS NULL
+		LIMIT 2
+		FOR UPDATE;`
+	updateQuery := `
+		UPDATE Subscriptions
+		SET Price = $1, Start_date = $2, Exp_date = $3, Period_unit = $4, Period_interval = $5,
+			Trial_end = $6, Trial_price = $7, Auto_renew = $8, Service_ID = COALESCE(Service_ID, (
+				SELECT a.Service_ID FROM Service_aliases a
+				WHERE a.Normalized = ` + normalizedSQL("Service_name") + `))
+		WHERE ID = $9
+		RETURNING ` + subsColumns + `;`
+
+	var updated domain.Subscription
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, selectQuery, args...)
+		if err != nil {
+			return err
 		}
-		return subs, nil
-	})
 
+		locked, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+			return scanSubs(row)
+		})
+		if err != nil {
+			return err
+		}
+
+		switch len(locked) {
+		case 0:
+			return domain.ErrSubsNotFound
+		case 1:
+		default:
+			return domain.ErrSubsAmbiguous
+		}
+		before := locked[0]
+
+		after, err := scanSubs(tx.QueryRow(ctx, updateQuery, subs.Price, subs.StartDate, subs.EndDate,
+			subs.BillingPeriod.Unit, subs.BillingPeriod.Interval, subs.TrialEndDate, subs.TrialPrice, subs.AutoRenew, before.ID))
+		if err != nil {
+			return err
+		}
+
+		if subs.Price != before.Price {
+			err = addPrice(ctx, tx, before.ID, domain.PricePoint{Price: subs.Price, EffectiveFrom: time.Now()})
+			if err != nil {
+				return err
+			}
+		}
+		updated = after
+		if err := recordAudit(ctx, tx, domain.AuditUpdate, &before, &after); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, domain.EventSubsUpdated, after)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, rows.Err())
+		if isLookupErr(err) {
+			return domain.Subscription{}, err
+		}
+		return domain.Subscription{}, fmt.Errorf("%s: %w", op, err)
 	}
+	return updated, nil
+}
+
+// Delete soft-deletes the subscription, it is kept until purged by PurgeDeleted.
+func (repo *SubsRepo) Delete(ctx context.Context, serviceName, userID string) error {
+	const op = "SubsRepo.Delete"
+	query := `
+		UPDATE Subscriptions
+		SET Deleted_at = NOW()
+		WHERE ` + serviceCondition("", "$1") + ` AND User_ID = $2 AND Deleted_at IS NULL
+		RETURNING ` + subsColumns + `;`
 
-	return subsList, nil
+	return repo.deleteAudited(ctx, op, domain.AuditDelete, query, serviceName, userID)
 }
 
-func (repo *SubsRepo) Update(ctx context.Context, subs domain.Subscription) error {
-	const op = "SubsRepo.Update"
+// DeleteByID soft-deletes the subscription with the given ID.
+func (repo *SubsRepo) DeleteByID(ctx context.Context, id string) error {
+	const op = "SubsRepo.DeleteByID"
+	query := `
+		UPDATE Subscriptions
+		SET Deleted_at = NOW()
+		WHERE ID = $1 AND Deleted_at IS NULL
+		RETURNING ` + subsColumns + `;`
+
+	return repo.deleteAudited(ctx, op, domain.AuditDelete, query, id)
+}
+
+// DeleteList soft-deletes all subscriptions of the user.
+func (repo *SubsRepo) DeleteList(ctx context.Context, userID string) error {
+	const op = "SubsRepo.DeleteList"
 	query := `
 		UPDATE Subscriptions
-		SET Price = $1, Start_date = $2, Exp_date = $3
-		WHERE Service_name = $4 AND User_ID = $5;`
+		SET Deleted_at = NOW()
+		WHERE User_ID = $1 AND Deleted_at IS NULL
+		RETURNING ` + subsColumns + `;`
 
-	res, err := repo.db.Exec(ctx, query, subs.Price, subs.StartDate, subs.EndDate, subs.ServiceName, subs.UserID)
+	return repo.deleteAudited(ctx, op, domain.AuditDeleteList, query, userID)
+}
+
+// deleteAudited runs the soft-delete query and records an audit event and a deleted event
+// for every deleted subscription. A single delete matching several subscriptions is rolled back with ErrSubsAmbiguous.
+func (repo *SubsRepo) deleteAudited(ctx context.Context, op string, auditOp domain.AuditOperation, query string, args ...any) error {
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
+			return scanSubs(row)
+		})
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return domain.ErrSubsNotFound
+		}
+		if auditOp == domain.AuditDelete && len(deleted) > 1 {
+			return domain.ErrSubsAmbiguous
+		}
+
+		for i := range deleted {
+			if err := recordAudit(ctx, tx, auditOp, &deleted[i], nil); err != nil {
+				return err
+			}
+			if err := recordEvent(ctx, tx, domain.EventSubsDeleted, deleted[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
+		if isLookupErr(err) {
+			return err
+		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	if res.RowsAffected() == 0 {
-		return domain.ErrSubsNotFound
-	}
 	return nil
 }
 
-func (repo *SubsRepo) Delete(ctx context.Context, serviceName, userID string) error {
-	const op = "SubsRepo.Delete"
+// isLookupErr reports whether err tells that the addressed subscription could not be picked,
+// such errors are returned to the caller as they are.
+func isLookupErr(err error) bool {
+	return errors.Is(err, domain.ErrSubsNotFound) || errors.Is(err, domain.ErrSubsAmbiguous)
+}
+
+// Restore brings back the most recently soft-deleted subscription of the service and returns its ID.
+func (repo *SubsRepo) Restore(ctx context.Context, serviceName, userID string) (string, error) {
+	const op = "SubsRepo.Restore"
 	query := `
-		DELETE FROM Subscriptions
-		WHERE Service_name = $1 AND User_ID = $2;`
+		UPDATE Subscriptions
+		SET Deleted_at = NULL
+		WHERE ID = (
+			SELECT ID FROM Subscriptions
+			WHERE ` + serviceCondition("", "$1") + ` AND User_ID = $2 AND Deleted_at IS NOT NULL
+			ORDER BY Deleted_at DESC
+			LIMIT 1
+		)
+		RETURNING ` + subsColumns + `;`
 
-	res, err := repo.db.Exec(ctx, query, serviceName, userID)
+	var id string
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		restored, err := scanSubs(tx.QueryRow(ctx, query, serviceName, userID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrSubsNotFound
+			}
+			return err
+		}
+		id = restored.ID
+		if err := recordAudit(ctx, tx, domain.AuditRestore, nil, &restored); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, domain.EventSubsRestored, restored)
+	})
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	if res.RowsAffected() == 0 {
-		return domain.ErrSubsNotFound
+		if errors.Is(err, domain.ErrSubsNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
 	}
-	return nil
+	return id, nil
 }
 
-func (repo *SubsRepo) DeleteList(ctx context.Context, userID string) error {
-	const op = "SubsRepo.DeleteList"
+// PurgeDeleted hard-deletes subscriptions soft-deleted before the given moment
+// and returns how many of them were removed. Their history is removed by cascade.
+func (repo *SubsRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	const op = "SubsRepo.PurgeDeleted"
 	query := `
 		DELETE FROM Subscriptions
-		WHERE User_ID = $1;`
+		WHERE Deleted_at < $1;`
 
-	res, err := repo.db.Exec(ctx, query, userID)
+	res, err := repo.db.Exec(ctx, query, before)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-	if res.RowsAffected() == 0 {
-		return domain.ErrSubsNotFound
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
-	return nil
+	return res.RowsAffected(), nil
 }
 
-func (repo *SubsRepo) SubsListByFilter(ctx context.Context, start, end time.Time, serviceName, userID string, pageNum, pageSize int) ([]domain.Subscription, error) {
+// SubsListByFilter returns the requested page of filtered subscriptions.
+// Count and cost of the whole filtered set are calculated regardless of the requested page.
+func (repo *SubsRepo) SubsListByFilter(ctx context.Context, filter domain.SummaryFilter) (domain.SubsPage, error) {
 	const op = "SubsRepo.SubsListByFilter"
+	where, args := filterConditions(filter)
 	query := `
-		SELECT Service_name, Price, User_ID, Start_date, Exp_date FROM Subscriptions
-		WHERE Start_date BETWEEN $1 and $2 `
+		SELECT s.*, subscription_cost(s, $1, $2, $3::UUID) AS cost
+		FROM Subscriptions s
+		WHERE ` + where
 
-	// Add filters and args dynamically
-	args := []any{start, end}
-	switch {
-	case len(serviceName) != 0:
-		query += `AND Service_name = $3 `
-		args = append(args, serviceName)
-		if len(userID) != 0 {
-			query += `AND User_ID = $4 `
-			args = append(args, userID)
-		}
-	case len(userID) != 0:
-		query += `AND User_ID = $3 `
-		args = append(args, userID)
+	subsPage, err := repo.selectPage(ctx, query, args, filter.Pagination)
+	if err != nil {
+		return domain.SubsPage{}, fmt.Errorf("%s: %w", op, err)
 	}
+	return subsPage, nil
+}
 
-	offset := (pageNum - 1) * pageSize
-	query += fmt.Sprintf(`
-	ORDER BY start_date DESC
-    LIMIT %d OFFSET %d;`, pageSize, offset)
+// attachDetails loads pause intervals, price history, discounts, participants and tags of the given subscriptions.
+func (repo *SubsRepo) attachDetails(ctx context.Context, subsList []domain.Subscription) error {
+	if len(subsList) == 0 {
+		return nil
+	}
 
-	rows, err := repo.db.Query(ctx, query, args...)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrSubsNotFound
-		}
-		return nil, fmt.Errorf("%s: %w", op, err)
+	if err := repo.attachPauses(ctx, subsList); err != nil {
+		return err
+	}
+	if err := repo.attachPrices(ctx, subsList); err != nil {
+		return err
+	}
+	if err := repo.attachDiscounts(ctx, subsList); err != nil {
+		return err
+	}
+	if err := repo.attachParticipants(ctx, subsList); err != nil {
+		return err
+	}
+	return repo.attachTags(ctx, subsList)
+}
+
+func subsIDs(subsList []domain.Subscription) []string {
+	ids := make([]string, 0, len(subsList))
+	for _, subs := range subsList {
+		ids = append(ids, subs.ID)
 	}
+	return ids
+}
 
-	subsList, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Subscription, error) {
-		var subs domain.Subscription
-		if err := row.Scan(&subs.ServiceName, &subs.Price, &subs.UserID, &subs.StartDate, &subs.EndDate); err != nil {
-			return domain.Subscription{}, fmt.Errorf("%s: %w", op, err)
-		}
-		return subs, nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+// filterConditions builds the WHERE clause and its arguments for the summary filter
+// over subscriptions aliased as s.
+// The first two arguments are always the window bounds, the inclusive end date
+// is passed as the start of the next day. The third one is the filtered user, or NULL,
+// whose share of shared subscriptions is counted by subscription_cost.
+func filterConditions(filter domain.SummaryFilter) (string, []any) {
+	var viewer *string
+	if len(filter.UserID) != 0 {
+		viewer = &filter.UserID
 	}
 
-	return subsList, nil
+	var (
+		where = `Deleted_at IS NULL `
+		args  = []any{filter.Start, filter.End.AddDate(0, 0, 1), viewer}
+	)
+
+	switch filter.Mode {
+	case domain.FilterStarted:
+		where += `AND Start_date >= $1 AND Start_date < $2 `
+	case domain.FilterEnded:
+		where += `AND Exp_date >= $1 AND Exp_date < $2 `
+	case domain.FilterActive:
+		where += `AND Start_date <= $1 AND (Exp_date IS NULL OR Exp_date > $1) `
+	default:
+		where += `AND Start_date < $2 AND (Exp_date IS NULL OR Exp_date > $1) `
+	}
+
+	// Add filters and args dynamically
+	if len(filter.ServiceName) != 0 {
+		args = append(args, filter.ServiceName)
+		where += `AND ` + serviceCondition("", fmt.Sprintf("$%d", len(args))) + ` `
+	}
+	if viewer != nil {
+		where += `AND ` + memberCondition(`$3`) + ` `
+	}
+	if len(filter.Tags) != 0 {
+		var condition string
+		condition, args = tagCondition(filter.Tags, args)
+		where += condition
+	}
+
+	return where, args
+}
+
+// scanSubs scans a single row selected with subsColumns followed by the extra columns.
+func scanSubs(row pgx.Row, extra ...any) (domain.Subscription, error) {
+	var subs domain.Subscription
+	dest := []any{&subs.ID, &subs.ServiceName, &subs.ServiceID, &subs.Price, &subs.UserID, &subs.StartDate, &subs.EndDate,
+		&subs.BillingPeriod.Unit, &subs.BillingPeriod.Interval, &subs.Status, &subs.StatusChanged, &subs.TrialEndDate,
+		&subs.TrialPrice, &subs.AutoRenew}
+	err := row.Scan(append(dest, extra...)...)
+	return subs, err
 }
@@ -0,0 +1,202 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// setTags replaces tags of the subscription, unknown tag names are added to the Tags table.
+// Tag names are expected to be normalized with domain.NormalizeTags.
+func setTags(ctx context.Context, tx pgx.Tx, subsID string, tags []string) error {
+	deleteQuery := `
+		DELETE FROM Subscription_tags
+		WHERE Subscription_ID = $1;`
+	insertQuery := `
+		WITH tag_ids AS (
+			INSERT INTO Tags(Name)
+			SELECT unnest($2::TEXT[])
+			ON CONFLICT (Name) DO UPDATE SET Name = EXCLUDED.Name
+			RETURNING ID
+		)
+		INSERT INTO Subscription_tags(Subscription_ID, Tag_ID)
+		SELECT $1, ID FROM tag_ids;`
+
+	if _, err := tx.Exec(ctx, deleteQuery, subsID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, insertQuery, subsID, tags)
+	return err
+}
+
+// SetTags replaces tags of the subscription with the given ID.
+func (repo *SubsRepo) SetTags(ctx context.Context, id string, tags []string) error {
+	const op = "SubsRepo.SetTags"
+	query := `
+		SELECT ` + subsColumns + ` FROM Subscriptions
+		WHERE ID = $1 AND Deleted_at IS NULL
+		FOR UPDATE;`
+	tagsQuery := `
+		SELECT t.Name
+		FROM Subscription_tags st
+		JOIN Tags t ON t.ID = st.Tag_ID
+		WHERE st.Subscription_ID = $1
+		ORDER BY t.Name;`
+
+	err := postgres.WithTx(ctx, repo.db, func(tx pgx.Tx) error {
+		before, err := scanSubs(tx.QueryRow(ctx, query, id))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return domain.ErrSubsNotFound
+			}
+			return err
+		}
+
+		rows, err := tx.Query(ctx, tagsQuery, id)
+		if err != nil {
+			return err
+		}
+		before.Tags, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+
+		if err := setTags(ctx, tx, id, tags); err != nil {
+			return err
+		}
+
+		after := before
+		after.Tags = tags
+		if err := recordAudit(ctx, tx, domain.AuditUpdate, &before, &after); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, domain.EventSubsUpdated, after)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrSubsNotFound) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// attachTags loads tags of the given subscriptions, sorted by name.
+func (repo *SubsRepo) attachTags(ctx context.Context, subsList []domain.Subscription) error {
+	query := `
+		SELECT st.Subscription_ID, t.Name
+		FROM Subscription_tags st
+		JOIN Tags t ON t.ID = st.Tag_ID
+		WHERE st.Subscription_ID = ANY($1::UUID[])
+		ORDER BY t.Name;`
+
+	rows, err := repo.db.Query(ctx, query, subsIDs(subsList))
+	if err != nil {
+		return fmt.Errorf("attach tags: %w", err)
+	}
+
+	tags := make(map[string][]string)
+	var subsID, tag string
+	_, err = pgx.ForEachRow(rows, []any{&subsID, &tag}, func() error {
+		tags[subsID] = append(tags[subsID], tag)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("attach tags: %w", err)
+	}
+
+	for i := range subsList {
+		subsList[i].Tags = tags[subsList[i].ID]
+	}
+	return nil
+}
+
+// tagCondition appends the tags argument and returns the condition matching
+// subscriptions aliased as s that are tagged with any of them.
+func tagCondition(tags []string, args []any) (string, []any) {
+	args = append(args, tags)
+	return fmt.Sprintf(`AND EXISTS (
+			SELECT 1 FROM Subscription_tags st
+			JOIN Tags t ON t.ID = st.Tag_ID
+			WHERE st.Subscription_ID = s.ID AND t.Name = ANY($%d::TEXT[])) `, len(args)), args
+}
+
+// SummaryGroups returns subtotals of the filtered subscriptions grouped by filter.GroupBy, ordered by key.
+//
+// Subscriptions without a tag or a catalog category are grouped under "untagged" and "uncategorized".
+// Month groups are keyed as YYYY-MM in UTC and charge every subscription for the part of the window
+// falling into the month, so cycles crossing month boundaries are prorated between the months.
+// With a user filter, subscriptions shared with the user only count the user's share.
+func (repo *SubsRepo) SummaryGroups(ctx context.Context, filter domain.SummaryFilter) ([]domain.SummaryGroup, error) {
+	const op = "SubsRepo.SummaryGroups"
+	where, args := filterConditions(filter)
+
+	// The cost is calculated over the filtered rows first, so the joins below
+	// do not clash with the unqualified columns of the filter conditions
+	filtered := `
+		SELECT s.ID, s.Service_name, s.Service_ID, subscription_cost(s, $1, $2, $3::UUID) AS Cost
+		FROM Subscriptions s
+		WHERE ` + where
+
+	var query string
+	switch filter.GroupBy {
+	case domain.GroupByService:
+		query = `
+		SELECT f.Service_name, SUM(f.Cost)::BIGINT, COUNT(*)
+		FROM (` + filtered + `) f
+		GROUP BY 1
+		ORDER BY 1;`
+	case domain.GroupByCategory:
+		query = `
+		SELECT COALESCE(NULLIF(sv.Category, ''), 'uncategorized'), SUM(f.Cost)::BIGINT, COUNT(*)
+		FROM (` + filtered + `) f
+		LEFT JOIN Services sv ON sv.ID = f.Service_ID
+		GROUP BY 1
+		ORDER BY 1;`
+	case domain.GroupByTag:
+		query = `
+		SELECT COALESCE(t.Name, 'untagged'), SUM(f.Cost)::BIGINT, COUNT(*)
+		FROM (` + filtered + `) f
+		LEFT JOIN Subscription_tags st ON st.Subscription_ID = f.ID
+		LEFT JOIN Tags t ON t.ID = st.Tag_ID
+		GROUP BY 1
+		ORDER BY 1;`
+	case domain.GroupByMonth:
+		query = `
+		SELECT to_char(m.Month AT TIME ZONE 'UTC', 'YYYY-MM'),
+			SUM(subscription_cost(s, GREATEST(m.Month, $1), LEAST(date_add(m.Month, INTERVAL '1 month', 'UTC'), $2), $3::UUID))::BIGINT,
+			COUNT(*) FILTER (WHERE s.Start_date < LEAST(date_add(m.Month, INTERVAL '1 month', 'UTC'), $2)
+				AND (s.Exp_date IS NULL OR s.Exp_date > GREATEST(m.Month, $1)))
+		FROM Subscriptions s
+		CROSS JOIN generate_series(date_trunc('month', $1::TIMESTAMPTZ, 'UTC'),
+			$2::TIMESTAMPTZ - INTERVAL '1 microsecond', INTERVAL '1 month', 'UTC') AS m(Month)
+		WHERE ` + where + `
+		GROUP BY 1
+		ORDER BY 1;`
+	default:
+		return nil, domain.ErrInvalidGroupBy
+	}
+
+	rows, err := repo.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	groups, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.SummaryGroup, error) {
+		var group domain.SummaryGroup
+		err := row.Scan(&group.Key, &group.TotalPrice, &group.TotalItems)
+		return group, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return groups, nil
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ConvertEndedTrials moves trialing subscriptions whose trial ended by now to the active status
+// and returns how many were converted. The transition is recorded at the end of the trial,
+// or at the start if the trial has been removed by an update.
+// Subscriptions locked by a concurrent status change are left for the next run.
+func (repo *SubsRepo) ConvertEndedTrials(ctx context.Context, now time.Time) (int64, error) {
+	const op = "SubsRepo.ConvertEndedTrials"
+	selectQuery := `
+		SELECT ` + subsColumns + `
+		FROM Subscriptions
+		WHERE Status = $1 AND COALESCE(Trial_end, Start_date) <= $2 AND Deleted_at IS NULL
+		ORDER BY COALESCE(Trial_end, Start_date), ID
+		FOR UPDATE SKIP LOCKED;`
+
+	count, err := repo.moveLocked(ctx, selectQuery, []any{domain.StatusTrialing, now},
+		domain.StatusActive, domain.EventSubsStatusChanged, domain.Subscription.BillingStart)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return count, nil
+}
+
+// TrialReport counts the trials of live subscriptions per service by how they ended.
+// A trial ends with the first transition out of trialing, so a subscription converted to paid
+// and cancelled later still counts as converted. Subscriptions that have a trial end date
+// but have never been trialing, such as a trial added to an active subscription, are not counted.
+func (repo *SubsRepo) TrialReport(ctx context.Context, filter domain.TrialReportFilter) ([]domain.TrialConversion, error) {
+	const op = "SubsRepo.TrialReport"
+	query := `
+		SELECT s.Service_name,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE h.To_status = 'active'),
+			COUNT(*) FILTER (WHERE h.To_status = 'cancelled'),
+			COUNT(*) FILTER (WHERE h.To_status = 'expired'),
+			COUNT(*) FILTER (WHERE h.To_status IS NULL AND s.Status = 'trialing')
+		FROM Subscriptions s
+		LEFT JOIN LATERAL (
+			SELECT To_status FROM Subscription_status_history
+			WHERE Subscription_ID = s.ID AND From_status = 'trialing'
+			ORDER BY Changed_at, ID
+			LIMIT 1
+		) h ON TRUE
+		WHERE s.Trial_end IS NOT NULL AND s.Deleted_at IS NULL
+			AND (h.To_status IS NOT NULL OR s.Status = 'trialing')
+			AND ($1 = '' OR ` + serviceCondition("s.", "$1") + `)
+			AND ($2::TIMESTAMPTZ IS NULL OR s.Start_date >= $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR s.Start_date < $3)
+		GROUP BY s.Service_name
+		ORDER BY s.Service_name;`
+
+	// The end of the window is inclusive
+	var start, until *time.Time
+	if !filter.Start.IsZero() {
+		start = &filter.Start
+	}
+	if !filter.End.IsZero() {
+		end := filter.End.AddDate(0, 0, 1)
+		until = &end
+	}
+
+	rows, err := repo.db.Query(ctx, query, filter.ServiceName, start, until)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	report, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.TrialConversion, error) {
+		var c domain.TrialConversion
+		err := row.Scan(&c.ServiceName, &c.Trials, &c.Converted, &c.Cancelled, &c.Expired, &c.Trialing)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return report, nil
+}
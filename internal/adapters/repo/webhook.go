@@ -0,0 +1,268 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"submanager/internal/core/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// endpointColumns is the column list shared by every webhook endpoint SELECT, in scanEndpoint order.
+const endpointColumns = `ID, URL, Secret, Event_types, Created_at`
+
+// deliveryColumns is the column list shared by every webhook delivery SELECT, in scanDelivery order.
+const deliveryColumns = `d.ID, d.Endpoint_ID, d.Event_ID, d.Event_type, d.Payload, d.Status, d.Attempts,
+	COALESCE(d.Last_error, ''), d.Next_attempt_at, d.Delivered_at, d.Created_at`
+
+type WebhookRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepo(db *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{
+		db: db,
+	}
+}
+
+// CreateEndpoint stores the endpoint and returns its ID.
+func (repo *WebhookRepo) CreateEndpoint(ctx context.Context, endpoint domain.WebhookEndpoint) (string, error) {
+	const op = "WebhookRepo.CreateEndpoint"
+	query := `
+		INSERT INTO Webhook_endpoints(URL, Secret, Event_types)
+		VALUES($1, $2, $3)
+		RETURNING ID;`
+
+	var id string
+	err := repo.db.QueryRow(ctx, query, endpoint.URL, endpoint.Secret, eventTypes(endpoint.EventTypes)).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	return id, nil
+}
+
+func (repo *WebhookRepo) GetEndpoint(ctx context.Context, id string) (domain.WebhookEndpoint, error) {
+	const op = "WebhookRepo.GetEndpoint"
+	query := `
+		SELECT ` + endpointColumns + `
+		FROM Webhook_endpoints
+		WHERE ID = $1;`
+
+	endpoint, err := scanEndpoint(repo.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WebhookEndpoint{}, domain.ErrWebhookNotFound
+		}
+		return domain.WebhookEndpoint{}, fmt.Errorf("%s: %w", op, err)
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints returns all endpoints, oldest first.
+func (repo *WebhookRepo) ListEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	const op = "WebhookRepo.ListEndpoints"
+	query := `
+		SELECT ` + endpointColumns + `
+		FROM Webhook_endpoints
+		ORDER BY Created_at, ID;`
+
+	rows, err := repo.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	endpoints, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.WebhookEndpoint, error) {
+		return scanEndpoint(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes the endpoint together with its deliveries.
+func (repo *WebhookRepo) DeleteEndpoint(ctx context.Context, id string) error {
+	const op = "WebhookRepo.DeleteEndpoint"
+	query := `
+		DELETE FROM Webhook_endpoints
+		WHERE ID = $1;`
+
+	res, err := repo.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// EnqueueDeliveries schedules the payload for every endpoint subscribed to the event type.
+// An event already scheduled for an endpoint is not scheduled again.
+func (repo *WebhookRepo) EnqueueDeliveries(ctx context.Context, event domain.Event, payload []byte) (int64, error) {
+	const op = "WebhookRepo.EnqueueDeliveries"
+	query := `
+		INSERT INTO Webhook_deliveries(Endpoint_ID, Event_ID, Event_type, Payload)
+		SELECT ID, $1, $2, $3
+		FROM Webhook_endpoints
+		WHERE cardinality(Event_types) = 0 OR $2 = ANY(Event_types)
+		ON CONFLICT (Endpoint_ID, Event_ID) DO NOTHING;`
+
+	res, err := repo.db.Exec(ctx, query, event.ID, event.Type, payload)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// ClaimDeliveries leases due pending deliveries by moving their next attempt to the end of the lease.
+// Rows locked by another worker are skipped, a delivery of a crashed worker is picked up after its lease.
+func (repo *WebhookRepo) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	const op = "WebhookRepo.ClaimDeliveries"
+	query := `
+		UPDATE Webhook_deliveries d
+		SET Next_attempt_at = $2
+		FROM Webhook_endpoints e
+		WHERE e.ID = d.Endpoint_ID AND d.ID IN (
+			SELECT ID FROM Webhook_deliveries
+			WHERE Status = 'pending' AND Next_attempt_at <= $1
+			ORDER BY Next_attempt_at, ID
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `, e.URL, e.Secret;`
+
+	rows, err := repo.db.Query(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.WebhookDelivery, error) {
+		var url, secret string
+		delivery, err := scanDelivery(row, &url, &secret)
+		delivery.URL, delivery.Secret = url, secret
+		return delivery, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+func (repo *WebhookRepo) MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	const op = "WebhookRepo.MarkDelivered"
+	query := `
+		UPDATE Webhook_deliveries
+		SET Status = 'delivered', Attempts = Attempts + 1, Last_error = NULL, Delivered_at = $2
+		WHERE ID = $1;`
+
+	if _, err := repo.db.Exec(ctx, query, id, deliveredAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// MarkDeliveryFailed records the failed attempt, a zero nextAttempt moves the delivery to the dead letters.
+func (repo *WebhookRepo) MarkDeliveryFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	const op = "WebhookRepo.MarkDeliveryFailed"
+	query := `
+		UPDATE Webhook_deliveries
+		SET Attempts = Attempts + 1, Last_error = $2,
+			Status = CASE WHEN $3::TIMESTAMPTZ IS NULL THEN 'dead' ELSE Status END,
+			Next_attempt_at = COALESCE($3, Next_attempt_at)
+		WHERE ID = $1;`
+
+	var next *time.Time
+	if !nextAttempt.IsZero() {
+		next = &nextAttempt
+	}
+
+	if _, err := repo.db.Exec(ctx, query, id, reason, next); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	return nil
+}
+
+// ListDeliveries returns deliveries with the status, newest first.
+func (repo *WebhookRepo) ListDeliveries(ctx context.Context, status domain.DeliveryStatus) ([]domain.WebhookDelivery, error) {
+	const op = "WebhookRepo.ListDeliveries"
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM Webhook_deliveries d
+		WHERE d.Status = $1
+		ORDER BY d.Created_at DESC, d.ID DESC;`
+
+	rows, err := repo.db.Query(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.WebhookDelivery, error) {
+		return scanDelivery(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return deliveries, nil
+}
+
+// Redeliver resets the attempts of a dead or delivered delivery and makes it due now.
+// A delivery that is still pending is already going to be retried and is left as it is.
+func (repo *WebhookRepo) Redeliver(ctx context.Context, id int64) error {
+	const op = "WebhookRepo.Redeliver"
+	query := `
+		UPDATE Webhook_deliveries
+		SET Status = 'pending', Attempts = 0, Next_attempt_at = NOW(), Delivered_at = NULL
+		WHERE ID = $1 AND Status <> 'pending';`
+	existsQuery := `
+		SELECT EXISTS(SELECT 1 FROM Webhook_deliveries WHERE ID = $1);`
+
+	res, err := repo.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if res.RowsAffected() != 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := repo.db.QueryRow(ctx, existsQuery, id).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !exists {
+		return domain.ErrDeliveryNotFound
+	}
+	return nil
+}
+
+// eventTypes converts the filter to strings, a nil filter is stored as an empty array.
+func eventTypes(types []domain.EventType) []string {
+	result := make([]string, 0, len(types))
+	for _, eventType := range types {
+		result = append(result, string(eventType))
+	}
+	return result
+}
+
+func scanEndpoint(row pgx.Row) (domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	var types []string
+	err := row.Scan(&endpoint.ID, &endpoint.URL, &endpoint.Secret, &types, &endpoint.CreatedAt)
+
+	endpoint.EventTypes = make([]domain.EventType, 0, len(types))
+	for _, eventType := range types {
+		endpoint.EventTypes = append(endpoint.EventTypes, domain.EventType(eventType))
+	}
+	return endpoint, err
+}
+
+func scanDelivery(row pgx.Row, extra ...any) (domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	dest := []any{&d.ID, &d.EndpointID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.LastError, &d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt}
+	err := row.Scan(append(dest, extra...)...)
+	return d, err
+}
@@ -1,10 +1,12 @@
 package app
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
 	"submanager/internal/pkg/envzilla"
 	"submanager/internal/pkg/postgres"
+	"time"
 )
 
 type (
@@ -14,6 +16,75 @@ type (
 		LogLevel    string `env:"LOG_LEVEL" default:"dev"`
 		DB          postgres.DBConfig
 		LogFilePath string `env:"LOG_FILE_PATH" default:"docs/"`
+		Uniqueness  string `env:"SUBS_UNIQUENESS" default:"service"`
+		Purge       PurgeConfig
+		Reminder    ReminderConfig
+		Webhook     WebhookConfig
+		Expiry      ExpiryConfig
+		Outbox      OutboxConfig
+		Admin       AdminConfig
+	}
+
+	// PurgeConfig controls how long soft-deleted subscriptions are kept before they are removed for good.
+	PurgeConfig struct {
+		Retention time.Duration `env:"PURGE_RETENTION" default:"720h"`
+		Interval  time.Duration `env:"PURGE_INTERVAL" default:"1h"`
+	}
+
+	// ReminderConfig controls renewal reminders sent before subscriptions end.
+	// Channel is one of log, webhook and email.
+	ReminderConfig struct {
+		DaysBefore  int           `env:"REMINDER_DAYS_BEFORE" default:"3"`
+		Interval    time.Duration `env:"REMINDER_INTERVAL" default:"10m"`
+		MaxAttempts int           `env:"REMINDER_MAX_ATTEMPTS" default:"5"`
+		RetryDelay  time.Duration `env:"REMINDER_RETRY_DELAY" default:"1m"`
+		Channel     string        `env:"REMINDER_CHANNEL" default:"log"`
+		WebhookURL  string        `env:"REMINDER_WEBHOOK_URL" default:""`
+		SMTP        SMTPConfig
+	}
+
+	// WebhookConfig controls how often due webhook deliveries are sent and how failed ones are retried.
+	WebhookConfig struct {
+		Interval    time.Duration `env:"WEBHOOK_INTERVAL" default:"30s"`
+		MaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+		RetryDelay  time.Duration `env:"WEBHOOK_RETRY_DELAY" default:"30s"`
+	}
+
+	// ExpiryConfig controls how often ended subscriptions are moved to the expired status
+	// and subscriptions with an ended trial are converted to active.
+	ExpiryConfig struct {
+		Interval time.Duration `env:"EXPIRY_INTERVAL" default:"1h"`
+	}
+
+	// OutboxConfig controls the relay of subscription events from the outbox.
+	// Events always go to webhooks, Publisher is one of none, stdout, file and channel for an additional copy.
+	// Delivery is at least once, a publisher may see an event again after any publisher failed it.
+	// The channel publisher buffers ChannelBuffer events for the in-process consumer.
+	OutboxConfig struct {
+		Interval      time.Duration `env:"OUTBOX_INTERVAL" default:"5s"`
+		RetryDelay    time.Duration `env:"OUTBOX_RETRY_DELAY" default:"30s"`
+		Publisher     string        `env:"OUTBOX_PUBLISHER" default:"none"`
+		File          string        `env:"OUTBOX_FILE" default:"events.jsonl"`
+		ChannelBuffer int           `env:"OUTBOX_CHANNEL_BUFFER" default:"100"`
+	}
+
+	// AdminConfig controls the cross-user analytics endpoints, which are disabled without a token.
+	// Groups with fewer distinct users than MinGroupSize are never reported.
+	AdminConfig struct {
+		Token        string `env:"ADMIN_TOKEN" default:""`
+		MinGroupSize int    `env:"ADMIN_MIN_GROUP_SIZE" default:"5"`
+	}
+
+	// SMTPConfig describes the mail server email reminders are sent through.
+	// Users have no email addresses here, so all reminders go to the single operator Inbox.
+	// Timeout bounds a single delivery and must stay well below the reminder lease.
+	SMTPConfig struct {
+		Addr     string        `env:"SMTP_ADDR" default:"localhost:1025"`
+		From     string        `env:"SMTP_FROM" default:"submanager@localhost"`
+		Inbox    string        `env:"SMTP_OPERATOR_INBOX" default:""`
+		Username string        `env:"SMTP_USERNAME" default:""`
+		Password string        `env:"SMTP_PASSWORD" default:""`
+		Timeout  time.Duration `env:"SMTP_TIMEOUT" default:"30s"`
 	}
 )
 
@@ -30,5 +101,50 @@ func MustParseConfig() Config {
 		os.Exit(1)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
 	return cfg
 }
+
+// Validate checks that the intervals of the background workers, the purge retention,
+// the retry settings of reminders and webhooks and the reminder lead time are positive.
+// A non-positive retention would purge every soft-deleted subscription on the next run.
+func (cfg Config) Validate() error {
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"PURGE_INTERVAL", cfg.Purge.Interval},
+		{"PURGE_RETENTION", cfg.Purge.Retention},
+		{"REMINDER_INTERVAL", cfg.Reminder.Interval},
+		{"REMINDER_RETRY_DELAY", cfg.Reminder.RetryDelay},
+		{"WEBHOOK_INTERVAL", cfg.Webhook.Interval},
+		{"WEBHOOK_RETRY_DELAY", cfg.Webhook.RetryDelay},
+		{"EXPIRY_INTERVAL", cfg.Expiry.Interval},
+		{"OUTBOX_INTERVAL", cfg.Outbox.Interval},
+		{"OUTBOX_RETRY_DELAY", cfg.Outbox.RetryDelay},
+	}
+	for _, d := range durations {
+		if d.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", d.name, d.value)
+		}
+	}
+
+	counts := []struct {
+		name  string
+		value int
+	}{
+		{"REMINDER_DAYS_BEFORE", cfg.Reminder.DaysBefore},
+		{"REMINDER_MAX_ATTEMPTS", cfg.Reminder.MaxAttempts},
+		{"WEBHOOK_MAX_ATTEMPTS", cfg.Webhook.MaxAttempts},
+	}
+	for _, c := range counts {
+		if c.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %d", c.name, c.value)
+		}
+	}
+	return nil
+}
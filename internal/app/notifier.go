@@ -0,0 +1,35 @@
+package app
+
+import (
+	"errors"
+	"submanager/internal/adapters/notify"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+)
+
+// newNotifier builds the notifier of the configured reminder channel.
+func newNotifier(cfg ReminderConfig, log logger.Logger) (domain.Notifier, error) {
+	switch domain.NotificationChannel(cfg.Channel) {
+	case domain.ChannelLog:
+		return notify.NewLogNotifier(log), nil
+	case domain.ChannelWebhook:
+		if len(cfg.WebhookURL) == 0 {
+			return nil, errors.New("REMINDER_WEBHOOK_URL is required for the webhook channel")
+		}
+		return notify.NewWebhookNotifier(cfg.WebhookURL), nil
+	case domain.ChannelEmail:
+		if len(cfg.SMTP.Inbox) == 0 {
+			return nil, errors.New("SMTP_OPERATOR_INBOX is required for the email channel")
+		}
+		return notify.NewSMTPNotifier(notify.SMTPConfig{
+			Addr:     cfg.SMTP.Addr,
+			From:     cfg.SMTP.From,
+			Inbox:    cfg.SMTP.Inbox,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			Timeout:  cfg.SMTP.Timeout,
+		}), nil
+	default:
+		return nil, domain.ErrInvalidChannel
+	}
+}
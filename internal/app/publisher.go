@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/adapters/publish"
+	"submanager/internal/core/domain"
+)
+
+// newPublisher builds the configured additional event publisher, nil if there is none.
+func newPublisher(cfg OutboxConfig) (domain.EventPublisher, error) {
+	switch domain.PublisherKind(cfg.Publisher) {
+	case domain.PublisherNone:
+		return nil, nil
+	case domain.PublisherStdout:
+		return publish.NewStdoutPublisher(), nil
+	case domain.PublisherFile:
+		return publish.NewFilePublisher(cfg.File)
+	case domain.PublisherChannel:
+		if cfg.ChannelBuffer < 0 {
+			return nil, domain.ErrInvalidPublisher
+		}
+		return publish.NewChannelPublisher(cfg.ChannelBuffer), nil
+	default:
+		return nil, domain.ErrInvalidPublisher
+	}
+}
+
+// consumeEvents logs the events handed over by the channel publisher until ctx is cancelled
+func (a *App) consumeEvents(ctx context.Context, events <-chan domain.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			a.log.Info("Subscription event has been received", slog.String("event_id", event.ID),
+				slog.String("type", string(event.Type)), slog.String("subscription_id", event.Subscription.ID))
+		}
+	}
+}
@@ -3,10 +3,14 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	httpserver "submanager/internal/adapters/http"
+	"submanager/internal/adapters/notify"
+	"submanager/internal/adapters/publish"
 	"submanager/internal/adapters/repo"
+	"submanager/internal/core/domain"
 	"submanager/internal/core/service"
 	"submanager/internal/pkg/logger"
 	"submanager/internal/pkg/postgres"
@@ -14,8 +18,25 @@ import (
 )
 
 type App struct {
-	httpServer *httpserver.API
-	postgresDB *postgres.API
+	httpServer  *httpserver.API
+	postgresDB  *postgres.API
+	subsService *service.SubsService
+	purgeCfg    PurgeConfig
+
+	reminderService *service.ReminderService
+	reminderCfg     ReminderConfig
+
+	webhookService *service.WebhookService
+	webhookCfg     WebhookConfig
+	expiryCfg      ExpiryConfig
+
+	outboxRelay *service.OutboxRelay
+	outboxCfg   OutboxConfig
+	// publisher is the additional event publisher, closed on shutdown
+	publisher domain.EventPublisher
+
+	// stopWorkers cancels background workers on shutdown
+	stopWorkers context.CancelFunc
 
 	log logger.Logger
 }
@@ -33,13 +54,77 @@ func New(cfg Config, log logger.Logger) *App {
 	log.Info("Database connection estabilished...")
 
 	subsRepo := repo.NewSubsRepo(postgresDB.Pool)
-	subsService := service.NewSubsService(subsRepo, log)
-	server := httpserver.New(cfg.Host, cfg.Port, subsService, log)
+	catalogRepo := repo.NewCatalogRepo(postgresDB.Pool)
+	budgetRepo := repo.NewBudgetRepo(postgresDB.Pool)
+	uniqueness := domain.UniquenessPolicy(cfg.Uniqueness)
+	if !uniqueness.IsValid() {
+		log.Error("Invalid configuration", "error", domain.ErrInvalidUniqueness)
+		os.Exit(1)
+	}
+
+	budgetService := service.NewBudgetService(budgetRepo, subsRepo, log, service.WithBudgetCatalog(catalogRepo))
+	webhookService := service.NewWebhookService(repo.NewWebhookRepo(postgresDB.Pool), notify.NewSignedSender(),
+		service.WebhookPolicy{
+			MaxAttempts: cfg.Webhook.MaxAttempts,
+			RetryDelay:  cfg.Webhook.RetryDelay,
+		}, log)
+	subsService := service.NewSubsService(subsRepo, log,
+		service.WithUniquenessPolicy(uniqueness),
+		service.WithCatalog(catalogRepo),
+		service.WithBudgets(budgetService),
+	)
+	catalogService := service.NewCatalogService(catalogRepo, log)
+
+	notifier, err := newNotifier(cfg.Reminder, log)
+	if err != nil {
+		log.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	reminderService := service.NewReminderService(repo.NewReminderRepo(postgresDB.Pool), notifier,
+		service.ReminderPolicy{
+			DaysBefore:  cfg.Reminder.DaysBefore,
+			MaxAttempts: cfg.Reminder.MaxAttempts,
+			RetryDelay:  cfg.Reminder.RetryDelay,
+		}, log)
+
+	// Events written to the outbox always reach webhooks, optionally also the configured publisher
+	publisher, err := newPublisher(cfg.Outbox)
+	if err != nil {
+		log.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	fanout := publish.Fanout{webhookService}
+	if publisher != nil {
+		fanout = append(fanout, publisher)
+	}
+	outboxRelay := service.NewOutboxRelay(repo.NewOutboxRepo(postgresDB.Pool), fanout, cfg.Outbox.RetryDelay, log)
+
+	analyticsService := service.NewAnalyticsService(repo.NewAnalyticsRepo(postgresDB.Pool), cfg.Admin.MinGroupSize, log)
+	if len(cfg.Admin.Token) == 0 {
+		log.Info("Admin token is not set, admin analytics endpoints are disabled")
+	}
+
+	server := httpserver.New(cfg.Host, cfg.Port, subsService, catalogService, budgetService, webhookService,
+		analyticsService, cfg.Admin.Token, log)
 
 	return &App{
-		httpServer: server,
-		postgresDB: postgresDB,
-		log:        log,
+		httpServer:  server,
+		postgresDB:  postgresDB,
+		subsService: subsService,
+		purgeCfg:    cfg.Purge,
+
+		reminderService: reminderService,
+		reminderCfg:     cfg.Reminder,
+
+		webhookService: webhookService,
+		webhookCfg:     cfg.Webhook,
+		expiryCfg:      cfg.Expiry,
+
+		outboxRelay: outboxRelay,
+		outboxCfg:   cfg.Outbox,
+		publisher:   publisher,
+
+		log: log,
 	}
 }
 
@@ -48,6 +133,18 @@ func (a *App) Start() {
 	go a.httpServer.StartServer()
 	defer a.CleanUp()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopWorkers = cancel
+	go runPeriodic(ctx, a.purgeCfg.Interval, a.purgeDeleted)
+	go runPeriodic(ctx, a.reminderCfg.Interval, a.sendReminders)
+	go runPeriodic(ctx, a.webhookCfg.Interval, a.deliverWebhooks)
+	go runPeriodic(ctx, a.expiryCfg.Interval, a.expireEnded)
+	go runPeriodic(ctx, a.expiryCfg.Interval, a.convertTrials)
+	go runPeriodic(ctx, a.outboxCfg.Interval, a.relayEvents)
+	if channel, ok := a.publisher.(*publish.ChannelPublisher); ok {
+		go a.consumeEvents(ctx, channel.Events())
+	}
+
 	// Graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -57,11 +154,63 @@ func (a *App) Start() {
 	a.log.Info("Shutting down server")
 }
 
-// CleanUp closes the HTTP server and database connection gracefully
+// purgeDeleted removes soft-deleted subscriptions older than the configured retention
+func (a *App) purgeDeleted(ctx context.Context) {
+	if _, err := a.subsService.PurgeDeletedSubscriptions(ctx, a.purgeCfg.Retention); err != nil {
+		a.log.Error("Failed to purge deleted subscriptions", "error", err)
+	}
+}
+
+// sendReminders sends renewal reminders of subscriptions ending soon
+func (a *App) sendReminders(ctx context.Context) {
+	if _, err := a.reminderService.SendReminders(ctx); err != nil {
+		a.log.Error("Failed to send renewal reminders", "error", err)
+	}
+}
+
+// deliverWebhooks sends due webhook deliveries of subscription events
+func (a *App) deliverWebhooks(ctx context.Context) {
+	if _, err := a.webhookService.DeliverWebhooks(ctx); err != nil {
+		a.log.Error("Failed to deliver webhooks", "error", err)
+	}
+}
+
+// expireEnded moves ended subscriptions to the expired status
+func (a *App) expireEnded(ctx context.Context) {
+	if _, err := a.subsService.ExpireSubscriptions(ctx); err != nil {
+		a.log.Error("Failed to expire ended subscriptions", "error", err)
+	}
+}
+
+// convertTrials moves subscriptions with an ended trial to the active status
+func (a *App) convertTrials(ctx context.Context) {
+	if _, err := a.subsService.ConvertTrials(ctx); err != nil {
+		a.log.Error("Failed to convert ended trials", "error", err)
+	}
+}
+
+// relayEvents publishes subscription events written to the outbox
+func (a *App) relayEvents(ctx context.Context) {
+	if _, err := a.outboxRelay.RelayEvents(ctx); err != nil {
+		a.log.Error("Failed to relay outbox events", "error", err)
+	}
+}
+
+// CleanUp stops background workers, closes the HTTP server and database connection gracefully
 func (a *App) CleanUp() {
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+	}
+
 	if err := a.httpServer.Close(); err != nil {
 		a.log.Error("Failed to close server...")
 	}
 
+	if closer, ok := a.publisher.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			a.log.Error("Failed to close event publisher", "error", err)
+		}
+	}
+
 	a.postgresDB.Close()
 }
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// runPeriodic calls job every interval until ctx is cancelled.
+// The first call happens right away so a restart does not delay the job by a whole interval.
+func runPeriodic(ctx context.Context, interval time.Duration, job func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
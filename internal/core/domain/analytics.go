@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	// DefaultMinGroupSize is the smallest number of distinct users an analytics group
+	// must have to be reported, unless the configuration raises it.
+	DefaultMinGroupSize   = 5
+	DefaultAnalyticsLimit = 10
+	MaxAnalyticsLimit     = 100
+)
+
+// AnalyticsFilter selects the subscriptions of all users running at some moment of the window.
+// The window end date is inclusive. Groups with fewer than MinGroupSize distinct users are left out
+// of the results, so single users can not be identified. Limit caps the number of services reported.
+type AnalyticsFilter struct {
+	Start        time.Time
+	End          time.Time
+	ServiceName  string
+	Bucket       PeriodUnit
+	Limit        int
+	MinGroupSize int
+}
+
+// Validate checks the window, the limits and, if set, the bucket size of the filter.
+func (f AnalyticsFilter) Validate() error {
+	if f.End.Before(f.Start) {
+		return ErrInvalidDate
+	}
+	if f.Limit < 1 || f.Limit > MaxAnalyticsLimit || f.MinGroupSize < 1 {
+		return ErrInvalidAnalytics
+	}
+	if f.Bucket != "" {
+		return validateBuckets(f.Start, f.End, f.Bucket)
+	}
+	return nil
+}
+
+// ServiceStats is the popularity and the spend of a service across all users within the window.
+type ServiceStats struct {
+	ServiceName   string `json:"service_name"`
+	Users         int    `json:"users"`
+	Subscriptions int    `json:"subscriptions"`
+	TotalSpend    int    `json:"total_spend"`
+}
+
+// PriceStats is the distribution of the current list prices of a service across its subscriptions.
+// Minimum, maximum and quartiles are not reported, as in small groups they are prices of single subscriptions.
+type PriceStats struct {
+	ServiceName   string  `json:"service_name"`
+	Users         int     `json:"users"`
+	Subscriptions int     `json:"subscriptions"`
+	AvgPrice      float64 `json:"avg_price"`
+	MedianPrice   float64 `json:"median_price"`
+}
+
+// ServiceUsers is the number of distinct users of a service running a subscription
+// at some moment of the bucket [Start, End).
+type ServiceUsers struct {
+	ServiceName string    `json:"service_name"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Users       int       `json:"users"`
+}
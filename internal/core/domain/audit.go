@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type AuditOperation string
+
+const (
+	AuditCreate     AuditOperation = "create"
+	AuditUpdate     AuditOperation = "update"
+	AuditDelete     AuditOperation = "delete"
+	AuditDeleteList AuditOperation = "delete_list"
+	AuditRestore    AuditOperation = "restore"
+)
+
+// AuditEvent records a single subscription mutation with the subscription
+// state before and after it. Before is empty for creations.
+type AuditEvent struct {
+	ID        int64           `json:"id"`
+	SubsID    string          `json:"subscription_id"`
+	UserID    string          `json:"user_id"`
+	Actor     string          `json:"actor"`
+	Operation AuditOperation  `json:"operation"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter selects audit events of the user, optionally limited to the [Start, End] dates.
+type AuditFilter struct {
+	UserID string
+	Start  time.Time
+	End    time.Time
+	Pagination
+}
+
+// AuditPage is a page of audit events read by the repository.
+type AuditPage struct {
+	Events  []AuditEvent
+	HasMore bool
+}
+
+// AuditLog is a page of audit events with cursors of the neighbouring pages.
+// Cursors point at the event creation time and ID.
+type AuditLog struct {
+	Events     []AuditEvent `json:"events"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	PrevCursor string       `json:"prev_cursor,omitempty"`
+}
+
+// AuditMeta describes who made the request that mutates subscriptions.
+type AuditMeta struct {
+	Actor     string
+	RequestID string
+}
+
+type auditMetaKey struct{}
+
+// WithAuditMeta returns a copy of ctx carrying the audit metadata of the request.
+func WithAuditMeta(ctx context.Context, meta AuditMeta) context.Context {
+	return context.WithValue(ctx, auditMetaKey{}, meta)
+}
+
+// AuditMetaFromContext returns the audit metadata stored in ctx, or empty metadata if there is none.
+func AuditMetaFromContext(ctx context.Context) AuditMeta {
+	meta, _ := ctx.Value(auditMetaKey{}).(AuditMeta)
+	return meta
+}
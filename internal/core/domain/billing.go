@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"time"
+)
+
+type PeriodUnit string
+
+const (
+	PeriodDay   PeriodUnit = "day"
+	PeriodWeek  PeriodUnit = "week"
+	PeriodMonth PeriodUnit = "month"
+	PeriodYear  PeriodUnit = "year"
+)
+
+// BillingPeriod describes how often a subscription is charged,
+// e.g. {month, 1} for monthly, {month, 3} for quarterly or {day, 45}.
+type BillingPeriod struct {
+	Unit     PeriodUnit `json:"unit"`
+	Interval int        `json:"interval"`
+}
+
+// DefaultBillingPeriod is used when the client does not provide a period.
+var DefaultBillingPeriod = BillingPeriod{Unit: PeriodMonth, Interval: 1}
+
+// IsZero reports whether the period was left empty.
+func (p BillingPeriod) IsZero() bool {
+	return p.Unit == "" && p.Interval == 0
+}
+
+// Validate checks that the period unit is supported and the interval is positive.
+func (p BillingPeriod) Validate() error {
+	switch p.Unit {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+	default:
+		return ErrInvalidBillingPeriod
+	}
+
+	if p.Interval < 1 {
+		return ErrInvalidBillingPeriod
+	}
+	return nil
+}
+
+// Shift returns the date n periods after anchor in UTC.
+// Month and year periods are counted from the anchor and clamped to the end of
+// the month, so a subscription started on Jan 31 renews on Feb 28 and then on Mar 31.
+// Periods are counted in UTC whatever the location of the anchor, like billing_shift in the database,
+// so the costs calculated here and in SQL agree.
+func (p BillingPeriod) Shift(anchor time.Time, n int) time.Time {
+	anchor = anchor.UTC()
+	switch p.Unit {
+	case PeriodDay:
+		return anchor.AddDate(0, 0, n*p.Interval)
+	case PeriodWeek:
+		return anchor.AddDate(0, 0, 7*n*p.Interval)
+	case PeriodMonth:
+		return addMonthsClamped(anchor, n*p.Interval)
+	case PeriodYear:
+		return addMonthsClamped(anchor, 12*n*p.Interval)
+	default:
+		return anchor
+	}
+}
+
+// NextRenewal returns the first billing date strictly after the given moment.
+// Billing cycles start at the end of the trial, which is the first renewal of a trial subscription.
+// It returns zero time if the subscription ends before it renews again, an auto-renewing one never ends.
+func (s Subscription) NextRenewal(after time.Time) time.Time {
+	period := s.BillingPeriod
+	if period.Validate() != nil {
+		return time.Time{}
+	}
+
+	// Without a trial the start date is the first billing date, not a renewal
+	first := 1
+	if s.TrialEndDate != nil {
+		first = 0
+	}
+
+	var renewal time.Time
+	for n := first; ; n++ {
+		renewal = period.Shift(s.BillingStart(), n)
+		if renewal.After(after) {
+			break
+		}
+	}
+
+	if !s.AutoRenew && !s.EndDate.IsZero() && renewal.After(s.EndDate) {
+		return time.Time{}
+	}
+	return renewal
+}
+
+// RenewedEndDate returns the end of the term of an auto-renewing subscription running at the given moment,
+// the first billing date after the moment. Terms are counted from the billing start like the billing cycles,
+// so a subscription started on Jan 31 renews to Feb 28 and then to Mar 31 rather than staying on the 28th.
+// The end date is returned as it is if it is already after the moment or the period is invalid.
+func (s Subscription) RenewedEndDate(now time.Time) time.Time {
+	if s.BillingPeriod.Validate() != nil || s.EndDate.After(now) {
+		return s.EndDate
+	}
+
+	anchor := s.BillingStart()
+	end := anchor
+	for n := 1; !end.After(now); n++ {
+		end = s.BillingPeriod.Shift(anchor, n)
+	}
+	return end
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultBudgetThresholds are the percentages of the limit alerted on if a budget sets none.
+var DefaultBudgetThresholds = []int{80, 100}
+
+// maxBudgetThreshold caps thresholds so a typo does not create an alert that never fires.
+const maxBudgetThreshold = 1000
+
+// Budget limits the monthly spend of the user on subscriptions. A budget scoped
+// to a catalog category or a service only counts subscriptions in that scope.
+type Budget struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	Limit       int    `json:"limit"`
+	Category    string `json:"category,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	// Thresholds are percentages of the limit, an alert is recorded when the spend reaches each of them.
+	Thresholds []int `json:"thresholds"`
+}
+
+// Validate checks the budget fields, it is expected to be normalized with Normalize first.
+func (b Budget) Validate() error {
+	if b.Limit <= 0 || (len(b.Category) != 0 && len(b.ServiceName) != 0) || len(b.Thresholds) == 0 {
+		return ErrInvalidBudget
+	}
+
+	for _, threshold := range b.Thresholds {
+		if threshold <= 0 || threshold > maxBudgetThreshold {
+			return ErrInvalidBudget
+		}
+	}
+	return nil
+}
+
+// Normalize trims the scope and sorts the thresholds, falling back to the default ones.
+func (b Budget) Normalize() Budget {
+	b.Category = strings.TrimSpace(b.Category)
+	b.ServiceName = strings.TrimSpace(b.ServiceName)
+
+	if len(b.Thresholds) == 0 {
+		b.Thresholds = DefaultBudgetThresholds
+	}
+	b.Thresholds = slices.Clone(b.Thresholds)
+	slices.Sort(b.Thresholds)
+	b.Thresholds = slices.Compact(b.Thresholds)
+	return b
+}
+
+// Reached returns the thresholds the spend has reached.
+func (b Budget) Reached(spend int) []int {
+	var reached []int
+	for _, threshold := range b.Thresholds {
+		if spend*100 >= b.Limit*threshold {
+			reached = append(reached, threshold)
+		}
+	}
+	return reached
+}
+
+// BudgetAlert records that the projected spend of a month has reached a budget threshold.
+// Each threshold is alerted at most once per budget and month.
+type BudgetAlert struct {
+	ID        int64  `json:"id"`
+	BudgetID  string `json:"budget_id"`
+	UserID    string `json:"user_id"`
+	Threshold int    `json:"threshold"`
+	// Month is formatted as YYYY-MM.
+	Month     string    `json:"month"`
+	Spend     int       `json:"spend"`
+	Limit     int       `json:"limit"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AlertFilter selects budget alerts of the user, optionally of a single YYYY-MM month.
+type AlertFilter struct {
+	UserID string
+	Month  string
+}
+
+// BudgetMonth returns the first moment of the UTC month containing t.
+func BudgetMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
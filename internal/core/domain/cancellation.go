@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// CancelReason is the reason code recorded when a subscription is cancelled.
+type CancelReason string
+
+const (
+	CancelTooExpensive    CancelReason = "too_expensive"
+	CancelNotUsing        CancelReason = "not_using"
+	CancelSwitchedService CancelReason = "switched_service"
+	CancelMissingFeatures CancelReason = "missing_features"
+	CancelTechnicalIssues CancelReason = "technical_issues"
+	CancelOther           CancelReason = "other"
+	// CancelUnspecified is recorded for cancellations made without a reason, e.g. through a plain status change.
+	CancelUnspecified CancelReason = "unspecified"
+)
+
+// MaxCancelComment limits the length of the cancellation comment in characters.
+const MaxCancelComment = 1000
+
+// IsValid reports whether the reason can be given by the user, unspecified is only recorded by the service.
+func (r CancelReason) IsValid() bool {
+	switch r {
+	case CancelTooExpensive, CancelNotUsing, CancelSwitchedService, CancelMissingFeatures, CancelTechnicalIssues, CancelOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// Cancellation is why the user cancelled the subscription.
+type Cancellation struct {
+	Reason  CancelReason `json:"reason"`
+	Comment string       `json:"comment,omitempty"`
+}
+
+// Validate checks the reason code and the comment length.
+func (c Cancellation) Validate() error {
+	if !c.Reason.IsValid() || utf8.RuneCountInString(c.Comment) > MaxCancelComment {
+		return ErrInvalidCancellation
+	}
+	return nil
+}
+
+// ChurnFilter selects cancellations made within the window, optionally of a single service,
+// and the size of the report periods. The window end date is inclusive.
+type ChurnFilter struct {
+	ServiceName string
+	Start       time.Time
+	End         time.Time
+	Bucket      PeriodUnit
+}
+
+// Validate checks the window and the period size.
+func (f ChurnFilter) Validate() error {
+	if f.End.Before(f.Start) {
+		return ErrInvalidDate
+	}
+	return validateBuckets(f.Start, f.End, f.Bucket)
+}
+
+// Churn is the cancellations of a service within the period [Start, End): how many there were,
+// how many days the cancelled subscriptions lasted on average and how many were cancelled for each reason.
+type Churn struct {
+	ServiceName     string               `json:"service_name"`
+	Start           time.Time            `json:"start"`
+	End             time.Time            `json:"end"`
+	Cancellations   int                  `json:"cancellations"`
+	AvgLifetimeDays float64              `json:"avg_lifetime_days"`
+	Reasons         map[CancelReason]int `json:"reasons"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ServiceStatus tells whether a catalog service has been checked by a maintainer.
+type ServiceStatus string
+
+const (
+	ServiceApproved ServiceStatus = "approved"
+	// ServicePendingReview marks services created from unknown subscription names.
+	ServicePendingReview ServiceStatus = "pending_review"
+)
+
+func (s ServiceStatus) IsValid() bool {
+	return s == ServiceApproved || s == ServicePendingReview
+}
+
+// Service is a catalog entry subscriptions are linked to. Subscription names are matched
+// against the canonical name and aliases after normalization.
+type Service struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Aliases      []string      `json:"aliases"`
+	Category     string        `json:"category,omitempty"`
+	Vendor       string        `json:"vendor,omitempty"`
+	DefaultPrice int           `json:"default_price"`
+	Status       ServiceStatus `json:"status"`
+}
+
+// Validate checks the fields a maintainer provides for the catalog service.
+func (s Service) Validate() error {
+	if len(strings.TrimSpace(s.Name)) == 0 || s.DefaultPrice < 0 {
+		return ErrInvalidService
+	}
+
+	if !s.Status.IsValid() {
+		return ErrInvalidServiceStatus
+	}
+	return nil
+}
+
+// NormalizeAlias returns the key aliases are stored and looked up by. Names without letters or digits
+// fall back to the trimmed lower case name, so they do not all collapse into the empty key.
+func NormalizeAlias(name string) string {
+	if normalized := NormalizeServiceName(name); len(normalized) != 0 {
+		return normalized
+	}
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NormalizeServiceName reduces a service name to the form used for alias matching,
+// so "Yandex Plus", "yandex plus" and "YandexPlus" are the same service.
+func NormalizeServiceName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type DiscountKind string
+
+const (
+	// DiscountPercent takes Value percent off the list price.
+	DiscountPercent DiscountKind = "percent"
+	// DiscountAmount takes Value off the list price.
+	DiscountAmount DiscountKind = "amount"
+	// DiscountPrice charges Value instead of the list price.
+	DiscountPrice DiscountKind = "price"
+)
+
+// Discount is a pricing phase of the subscription lasting Cycles billing cycles.
+// Phases follow each other in order from the first paid cycle, a phase of zero cycles
+// lasts until the end of the subscription. Cycles after the last phase are charged the list price,
+// so "3 months for 99, then 299" is a single {price, 99, 3} phase of a subscription priced 299.
+type Discount struct {
+	Kind   DiscountKind `json:"kind"`
+	Value  int          `json:"value"`
+	Cycles int          `json:"cycles"`
+}
+
+// Validate checks the discount kind and that the value and the duration are in range.
+func (d Discount) Validate() error {
+	switch d.Kind {
+	case DiscountPercent:
+		if d.Value < 1 || d.Value > 100 {
+			return ErrInvalidDiscount
+		}
+	case DiscountAmount:
+		if d.Value < 1 {
+			return ErrInvalidDiscount
+		}
+	case DiscountPrice:
+		if d.Value < 0 {
+			return ErrInvalidDiscount
+		}
+	default:
+		return ErrInvalidDiscount
+	}
+
+	if d.Cycles < 0 {
+		return ErrInvalidDiscount
+	}
+	return nil
+}
+
+// Apply returns the price of a cycle charged with the discount, it never goes below zero.
+func (d Discount) Apply(price int) int {
+	switch d.Kind {
+	case DiscountPercent:
+		price = int(math.Round(float64(price*(100-d.Value)) / 100))
+	case DiscountAmount:
+		price -= d.Value
+	case DiscountPrice:
+		price = d.Value
+	}
+	return max(price, 0)
+}
+
+// ValidateDiscounts checks every phase, only the last one may last until the end of the subscription.
+func ValidateDiscounts(discounts []Discount) error {
+	for i, d := range discounts {
+		if err := d.Validate(); err != nil {
+			return err
+		}
+		if d.Cycles == 0 && i != len(discounts)-1 {
+			return ErrInvalidDiscount
+		}
+	}
+	return nil
+}
+
+// DiscountAt returns the discount phase covering the billing cycle, cycles are counted from 0 at BillingStart.
+func (s Subscription) DiscountAt(cycle int) (Discount, bool) {
+	var phaseEnd int
+	for _, d := range s.Discounts {
+		phaseEnd += d.Cycles
+		if d.Cycles == 0 || cycle < phaseEnd {
+			return d, true
+		}
+	}
+	return Discount{}, false
+}
+
+// CyclePrice returns the effective price of the billing cycle starting at billedAt:
+// the list price in effect on the billing date with the discount phase of the cycle applied.
+func (s Subscription) CyclePrice(cycle int, billedAt time.Time) int {
+	price := s.PriceAt(billedAt)
+	if d, ok := s.DiscountAt(cycle); ok {
+		return d.Apply(price)
+	}
+	return price
+}
+
+// CycleAt returns the index and the billing date of the billing cycle running at the given moment,
+// moments before the billing start belong to the first cycle.
+func (s Subscription) CycleAt(t time.Time) (int, time.Time) {
+	period := s.BillingPeriod
+	if period.Validate() != nil {
+		period = DefaultBillingPeriod
+	}
+
+	anchor := s.BillingStart()
+	var cycle int
+	for !period.Shift(anchor, cycle+1).After(t) {
+		cycle++
+	}
+	return cycle, period.Shift(anchor, cycle)
+}
+
+// EffectivePriceAt returns the effective price of the billing cycle running at the given moment.
+func (s Subscription) EffectivePriceAt(t time.Time) int {
+	return s.CyclePrice(s.CycleAt(t))
+}
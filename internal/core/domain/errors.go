@@ -11,4 +11,51 @@ var (
 	ErrInvalidUserID = errors.New("user_ID is not UUID format")
 	ErrInvalidDate   = errors.New("start_date must be before end_date")
 	ErrPriceField    = errors.New("price field must be more than 0")
+	ErrInvalidTrial  = errors.New("trial_end_date must be between start_date and end_date and trial_price must not be negative")
+
+	ErrInvalidImport       = errors.New("import file must be CSV with a header row holding the mapped service_name, start_date, user_id columns and at most 1000 rows")
+	ErrInvalidCancellation = errors.New("cancellation reason must be one of too_expensive, not_using, switched_service, missing_features, technical_issues, other and the comment at most 1000 characters")
+
+	ErrInvalidDiscount = errors.New("discount kind must be one of percent, amount, price with a value in range and cycles not negative, only the last phase may last until the end")
+
+	ErrInvalidShare       = errors.New("participant must have either a weight or a fixed amount more than 0 and be listed once, apart from the owner")
+	ErrShareNotFound      = errors.New("user is not a participant of the subscription")
+	ErrInvalidShareStatus = errors.New("share response must be one of accepted, declined")
+	ErrShareAnswered      = errors.New("share can not be answered again, a declined participant needs to be invited again")
+
+	ErrInvalidBillingPeriod = errors.New("billing period unit must be one of day, week, month, year and interval must be more than 0")
+	ErrInvalidFilterMode    = errors.New("filter mode must be one of overlap, started, ended, active")
+	ErrInvalidCursor        = errors.New("pagination cursor is invalid")
+	ErrInvalidStatus        = errors.New("status must be one of trialing, active, paused, cancelled, expired")
+	ErrInvalidTransition    = errors.New("subscription status transition is not allowed")
+	ErrStatusConflict       = errors.New("subscription status has been changed by another request")
+	ErrInvalidSubsID        = errors.New("subscription ID is not UUID format")
+	ErrSubsAmbiguous        = errors.New("user has several subscriptions of the service, address the subscription by ID")
+	ErrInvalidUniqueness    = errors.New("uniqueness policy must be one of service, none")
+
+	ErrServiceNotFound      = errors.New("service is not found in the catalog")
+	ErrInvalidService       = errors.New("service name is required and default price must not be negative")
+	ErrInvalidServiceStatus = errors.New("service status must be one of approved, pending_review")
+	ErrAliasTaken           = errors.New("service name or alias already belongs to another service")
+	ErrInvalidServiceID     = errors.New("service ID is not UUID format")
+	ErrInvalidGroupBy       = errors.New("group_by must be one of tag, service, category, month")
+	ErrInvalidForecast      = errors.New("forecast months must be between 1 and 60")
+	ErrInvalidBucket        = errors.New("bucket must be one of day, week, month, year and the range must not exceed 1000 buckets")
+
+	ErrInvalidAnalytics = errors.New("limit must be between 1 and 100 and min_group_size must be more than 0")
+	ErrAdminToken       = errors.New("admin token is missing or invalid")
+
+	ErrBudgetNotFound  = errors.New("budget is not found")
+	ErrInvalidBudget   = errors.New("budget limit must be more than 0, thresholds between 1 and 1000 percent, scope either category or service")
+	ErrInvalidBudgetID = errors.New("budget ID is not UUID format")
+	ErrInvalidMonth    = errors.New("month must be in YYYY-MM format")
+
+	ErrInvalidChannel   = errors.New("notification channel must be one of log, webhook, email")
+	ErrInvalidPublisher = errors.New("event publisher must be one of none, stdout, file, channel")
+
+	ErrWebhookNotFound   = errors.New("webhook endpoint is not found")
+	ErrInvalidWebhook    = errors.New("webhook URL must be an absolute http(s) URL and event types must be known")
+	ErrInvalidWebhookID  = errors.New("webhook ID is not UUID format")
+	ErrDeliveryNotFound  = errors.New("webhook delivery is not found")
+	ErrInvalidDeliveryID = errors.New("webhook delivery ID must be a positive integer")
 )
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// EventType names a subscription change consumers can react to.
+type EventType string
+
+const (
+	EventSubsCreated       EventType = "subscription.created"
+	EventSubsUpdated       EventType = "subscription.updated"
+	EventSubsDeleted       EventType = "subscription.deleted"
+	EventSubsRestored      EventType = "subscription.restored"
+	EventSubsStatusChanged EventType = "subscription.status_changed"
+	EventSubsExpired       EventType = "subscription.expired"
+	EventSubsRenewed       EventType = "subscription.renewed"
+)
+
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventSubsCreated, EventSubsUpdated, EventSubsDeleted, EventSubsRestored, EventSubsStatusChanged,
+		EventSubsExpired, EventSubsRenewed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event is a subscription change with the subscription state right after it.
+type Event struct {
+	ID           string       `json:"id"`
+	Type         EventType    `json:"type"`
+	OccurredAt   time.Time    `json:"occurred_at"`
+	Subscription Subscription `json:"subscription"`
+}
+
+// NewEvent creates an event of the subscription change with a random ID.
+func NewEvent(eventType EventType, subs Subscription) (Event, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:           hex.EncodeToString(b),
+		Type:         eventType,
+		OccurredAt:   time.Now().UTC(),
+		Subscription: subs,
+	}, nil
+}
+
+// EventPublisher hands subscription events to their consumers.
+// Events are relayed from the outbox at least once, so publishers may see an event again
+// after a failed or interrupted publish.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// OutboxEvent is an event written to the outbox together with the change it describes,
+// waiting to be published by the relay.
+type OutboxEvent struct {
+	ID       int64
+	Event    Event
+	Attempts int
+}
+
+// PublisherKind selects where relayed events are published besides webhooks.
+type PublisherKind string
+
+const (
+	PublisherNone   PublisherKind = "none"
+	PublisherStdout PublisherKind = "stdout"
+	PublisherFile   PublisherKind = "file"
+	// PublisherChannel hands events to in-process consumers.
+	PublisherChannel PublisherKind = "channel"
+)
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// FilterMode defines how the summary date window is matched against subscription terms.
+type FilterMode string
+
+const (
+	// FilterOverlap matches subscriptions running at any moment inside the window.
+	FilterOverlap FilterMode = "overlap"
+	// FilterStarted matches subscriptions started inside the window.
+	FilterStarted FilterMode = "started"
+	// FilterEnded matches subscriptions ended inside the window.
+	FilterEnded FilterMode = "ended"
+	// FilterActive matches subscriptions running at the Start instant.
+	FilterActive FilterMode = "active"
+)
+
+// IsValid reports whether the mode is supported.
+func (m FilterMode) IsValid() bool {
+	switch m {
+	case FilterOverlap, FilterStarted, FilterEnded, FilterActive:
+		return true
+	default:
+		return false
+	}
+}
+
+// SummaryFilter describes which subscriptions are included into the summary.
+// Both Start and End dates are inclusive. Subscriptions tagged with any of Tags match.
+type SummaryFilter struct {
+	Start       time.Time
+	End         time.Time
+	Mode        FilterMode
+	ServiceName string
+	UserID      string
+	Tags        []string
+	GroupBy     GroupBy
+	Pagination
+}
+
+// ListFilter selects a page of user subscriptions, optionally only those tagged with any of Tags.
+type ListFilter struct {
+	UserID string
+	Tags   []string
+	Pagination
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	DefaultForecastMonths = 12
+	MaxForecastMonths     = 60
+)
+
+// Forecast projects upcoming charges of the user month by month.
+// The first month starts at From, which is usually the current moment, and every following one
+// covers a whole calendar month up to Until. Cumulative totals add up the months so far.
+type Forecast struct {
+	UserID string          `json:"user_id"`
+	From   time.Time       `json:"from"`
+	Until  time.Time       `json:"until"`
+	Total  int             `json:"total"`
+	Months []ForecastMonth `json:"months"`
+}
+
+type ForecastMonth struct {
+	Month      string         `json:"month"`
+	Total      int            `json:"total"`
+	Cumulative int            `json:"cumulative"`
+	Items      []ForecastItem `json:"items"`
+}
+
+// ForecastItem sums up the charges of all subscriptions of a single service within the month.
+// For shared subscriptions only the user's share of every charge is counted.
+type ForecastItem struct {
+	ServiceName string `json:"service_name"`
+	Charges     int    `json:"charges"`
+	Amount      int    `json:"amount"`
+}
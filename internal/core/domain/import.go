@@ -0,0 +1,64 @@
+package domain
+
+// MaxImportRows limits the number of subscriptions imported by a single request.
+const MaxImportRows = 1000
+
+// ImportRowStatus is the outcome of a single imported row.
+type ImportRowStatus string
+
+const (
+	// ImportCreated rows have been stored as new subscriptions.
+	ImportCreated ImportRowStatus = "created"
+	// ImportValid rows passed the validation but were not stored,
+	// either because of a dry run or because another row failed an all-or-nothing import.
+	ImportValid ImportRowStatus = "valid"
+	// ImportFailed rows were rejected, the reason is in the row error.
+	ImportFailed ImportRowStatus = "failed"
+)
+
+// ImportOptions control how the rows are stored. A dry run only validates the rows,
+// an atomic import stores either all of them in a single transaction or none.
+type ImportOptions struct {
+	DryRun bool
+	Atomic bool
+}
+
+// ImportRow is a subscription parsed from the line of the import file.
+// Err is set if the line could not be parsed or validated, such rows are reported as failed.
+type ImportRow struct {
+	Line         int
+	Subscription Subscription
+	Err          error
+}
+
+// ImportResult is the outcome of a single imported row, ID is set for created subscriptions.
+type ImportResult struct {
+	Line        int             `json:"line"`
+	Status      ImportRowStatus `json:"status"`
+	ID          string          `json:"id,omitempty"`
+	ServiceName string          `json:"service_name,omitempty"`
+	UserID      string          `json:"user_id,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// ImportReport is the per-row outcome of an import together with the totals.
+type ImportReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Atomic  bool           `json:"atomic"`
+	Total   int            `json:"total"`
+	Created int            `json:"created"`
+	Failed  int            `json:"failed"`
+	Rows    []ImportResult `json:"rows"`
+}
+
+// MarkCreated marks the row at index i as stored under the given ID.
+func (r *ImportReport) MarkCreated(i int, id string) {
+	r.Rows[i].Status, r.Rows[i].ID = ImportCreated, id
+	r.Created++
+}
+
+// MarkFailed marks the row at index i as rejected with the error.
+func (r *ImportReport) MarkFailed(i int, err error) {
+	r.Rows[i].Status, r.Rows[i].Error = ImportFailed, err.Error()
+	r.Failed++
+}
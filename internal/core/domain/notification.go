@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NotificationChannel selects how notifications reach users.
+type NotificationChannel string
+
+const (
+	ChannelLog     NotificationChannel = "log"
+	ChannelWebhook NotificationChannel = "webhook"
+	ChannelEmail   NotificationChannel = "email"
+)
+
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case ChannelLog, ChannelWebhook, ChannelEmail:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationRenewalReminder is the kind of notifications warning that a subscription ends soon.
+const NotificationRenewalReminder = "renewal_reminder"
+
+// Notification is a message about a subscription sent to its user through a Notifier.
+type Notification struct {
+	Kind        string    `json:"kind"`
+	UserID      string    `json:"user_id"`
+	SubsID      string    `json:"subscription_id"`
+	ServiceName string    `json:"service_name"`
+	EndDate     time.Time `json:"end_date"`
+	Subject     string    `json:"subject"`
+	Text        string    `json:"text"`
+}
+
+// Notifier delivers notifications through a single channel.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+type ReminderStatus string
+
+const (
+	ReminderPending ReminderStatus = "pending"
+	ReminderSent    ReminderStatus = "sent"
+	// ReminderFailed marks reminders that ran out of delivery attempts.
+	ReminderFailed ReminderStatus = "failed"
+	// ReminderSkipped marks reminders of subscriptions that no longer end on the reminded date
+	// because they were deleted, cancelled, paused, renewed or had their end date moved.
+	ReminderSkipped ReminderStatus = "skipped"
+)
+
+// Reminder is a renewal reminder scheduled for a subscription end date.
+// Every end date of a subscription is reminded of at most once.
+type Reminder struct {
+	ID            int64
+	SubsID        string
+	UserID        string
+	ServiceName   string
+	EndDate       time.Time
+	Status        ReminderStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	SentAt        *time.Time
+}
+
+// Notification builds the renewal reminder message.
+func (r Reminder) Notification() Notification {
+	endDate := r.EndDate.Format(time.DateOnly)
+	return Notification{
+		Kind:        NotificationRenewalReminder,
+		UserID:      r.UserID,
+		SubsID:      r.SubsID,
+		ServiceName: r.ServiceName,
+		EndDate:     r.EndDate,
+		Subject:     fmt.Sprintf("Your %s subscription ends on %s", r.ServiceName, endDate),
+		Text: fmt.Sprintf("Your %s subscription (%s) ends on %s. Renew or cancel it before then to avoid surprises.",
+			r.ServiceName, r.SubsID, endDate),
+	}
+}
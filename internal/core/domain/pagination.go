@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor points at a subscription in the listing order (start date and ID, newest first).
+type Cursor struct {
+	StartDate time.Time
+	ID        string
+}
+
+// Encode returns the opaque token handed out to clients.
+func (c Cursor) Encode() string {
+	raw := c.StartDate.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// SeqID parses the ID of a cursor over rows with sequential integer IDs, such as audit events.
+func (c Cursor) SeqID() (int64, error) {
+	id, err := strconv.ParseInt(c.ID, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
+
+// DecodeCursor parses a token produced by Cursor.Encode.
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	startDate, id, ok := strings.Cut(string(raw), "|")
+	if !ok || len(id) == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	c.StartDate, err = time.Parse(time.RFC3339Nano, startDate)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	c.ID = id
+	return c, nil
+}
+
+// Pagination selects a page either by keyset cursor (After or Before)
+// or by the legacy page number.
+type Pagination struct {
+	PageNumber int
+	PageSize   int
+	After      *Cursor
+	Before     *Cursor
+}
+
+// IsKeyset reports whether the page is addressed by a cursor.
+func (p Pagination) IsKeyset() bool {
+	return p.After != nil || p.Before != nil
+}
+
+// SubsList is a page of user subscriptions with cursors of the neighbouring pages.
+type SubsList struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+	PrevCursor    string         `json:"prev_cursor,omitempty"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+// SubsPatch holds the subscription fields to change, nil fields are left as they are.
+type SubsPatch struct {
+	Price         *int
+	StartDate     *time.Time
+	EndDate       *time.Time
+	BillingPeriod *BillingPeriod
+	AutoRenew     *bool
+}
+
+// Apply returns the subscription with the patched fields replaced.
+func (p SubsPatch) Apply(subs Subscription) Subscription {
+	if p.Price != nil {
+		subs.Price = *p.Price
+	}
+	if p.StartDate != nil {
+		subs.StartDate = *p.StartDate
+	}
+	if p.EndDate != nil {
+		subs.EndDate = *p.EndDate
+	}
+	if p.BillingPeriod != nil {
+		subs.BillingPeriod = *p.BillingPeriod
+	}
+	if p.AutoRenew != nil {
+		subs.AutoRenew = *p.AutoRenew
+	}
+	return subs
+}
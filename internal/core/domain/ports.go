@@ -13,43 +13,267 @@ type SubsRepo interface {
 	SubsDeleter
 	SubsGetter
 	SubsChecker
+	SubsStatusManager
+	SubsAuditor
+	SubsSharer
 }
 
 type SubsCreator interface {
-	Create(ctx context.Context, subs Subscription) error
+	Create(ctx context.Context, subs Subscription) (string, error)
+	// CreateBatch creates all the subscriptions in a single transaction and returns their IDs in order.
+	CreateBatch(ctx context.Context, subsList []Subscription) ([]string, error)
 }
 
 type SubsUpdater interface {
-	Update(ctx context.Context, subs Subscription) error
+	Update(ctx context.Context, subs Subscription) (Subscription, error)
+	UpdateByID(ctx context.Context, subs Subscription) (Subscription, error)
+	SetTags(ctx context.Context, id string, tags []string) error
+	SetDiscounts(ctx context.Context, id string, discounts []Discount) error
 }
 
 type SubsDeleter interface {
 	Delete(ctx context.Context, serviceName string, userID string) error
+	DeleteByID(ctx context.Context, id string) error
 	DeleteList(ctx context.Context, userID string) error
+	Restore(ctx context.Context, serviceName string, userID string) (string, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 type SubsGetter interface {
 	Get(ctx context.Context, serviceName string, userID string) (Subscription, error)
-	List(ctx context.Context, userID string) ([]Subscription, error)
-	SubsListByFilter(ctx context.Context, start time.Time, end time.Time, serviceName, userID string, pageNum, pageSize int) ([]Subscription, error)
+	GetByID(ctx context.Context, id string) (Subscription, error)
+	List(ctx context.Context, filter ListFilter) (SubsPage, error)
+	SubsListByFilter(ctx context.Context, filter SummaryFilter) (SubsPage, error)
+	SummaryGroups(ctx context.Context, filter SummaryFilter) ([]SummaryGroup, error)
+	// ListBillable returns live trialing and active subscriptions owned or shared by the user
+	// which are running at some moment of [from, until).
+	ListBillable(ctx context.Context, userID string, from, until time.Time) ([]Subscription, error)
+	SpendSeries(ctx context.Context, filter SpendSeriesFilter) ([]SpendPoint, error)
 }
 
 type SubsChecker interface {
 	IsUnique(ctx context.Context, serviceName string, userID string) (bool, error)
 }
 
+type SubsStatusManager interface {
+	ChangeStatus(ctx context.Context, change StatusChange) error
+	StatusHistory(ctx context.Context, subsID string) ([]StatusChange, error)
+	// RenewEnded moves the end date of auto-renewing subscriptions whose term ended by now
+	// to the end of the running term and returns how many were renewed.
+	RenewEnded(ctx context.Context, now time.Time) (int64, error)
+	// ExpireEnded moves live subscriptions that ended by now and do not renew to the expired status
+	// and returns how many were expired.
+	ExpireEnded(ctx context.Context, now time.Time) (int64, error)
+	// ConvertEndedTrials moves trialing subscriptions whose trial ended by now to active and returns how many were converted.
+	ConvertEndedTrials(ctx context.Context, now time.Time) (int64, error)
+	TrialReport(ctx context.Context, filter TrialReportFilter) ([]TrialConversion, error)
+	// ChurnReport returns cancellations per service and period of the filter window.
+	ChurnReport(ctx context.Context, filter ChurnFilter) ([]Churn, error)
+}
+
+type SubsAuditor interface {
+	AuditEvents(ctx context.Context, filter AuditFilter) (AuditPage, error)
+}
+
+type SubsSharer interface {
+	SetParticipants(ctx context.Context, id string, participants []Participant) error
+	// SetShareStatus records the answer of the participant and returns ErrStatusConflict
+	// if the participant no longer has the from status.
+	SetShareStatus(ctx context.Context, id, userID string, from, to ShareStatus, at time.Time) error
+	SharedWith(ctx context.Context, userID string) ([]Subscription, error)
+}
+
+// ---------------- Catalog Repository ----------------
+
+type CatalogRepo interface {
+	CreateService(ctx context.Context, service Service) (string, error)
+	GetService(ctx context.Context, id string) (Service, error)
+	ListServices(ctx context.Context, status ServiceStatus) ([]Service, error)
+	UpdateService(ctx context.Context, service Service) error
+	DeleteService(ctx context.Context, id string) error
+	// FindService finds the service by its normalized name or alias, it returns ErrServiceNotFound for unknown names.
+	FindService(ctx context.Context, name string) (Service, error)
+}
+
+// ---------------- Budget Repository ----------------
+
+type BudgetRepo interface {
+	CreateBudget(ctx context.Context, budget Budget) (string, error)
+	GetBudget(ctx context.Context, id string) (Budget, error)
+	ListBudgets(ctx context.Context, userID string) ([]Budget, error)
+	UpdateBudget(ctx context.Context, budget Budget) error
+	DeleteBudget(ctx context.Context, id string) error
+	// AddAlert records the alert and reports false if the threshold
+	// has already been alerted for the budget in that month.
+	AddAlert(ctx context.Context, alert BudgetAlert) (bool, error)
+	ListAlerts(ctx context.Context, filter AlertFilter) ([]BudgetAlert, error)
+}
+
+// ---------------- Reminder Repository ----------------
+
+type ReminderRepo interface {
+	// EnqueueReminders schedules a reminder for every live subscription ending within (from, until]
+	// that has not been reminded of its current end date and returns how many were scheduled.
+	EnqueueReminders(ctx context.Context, from, until time.Time) (int64, error)
+	// ClaimReminders leases up to limit pending reminders due at now until now+lease,
+	// so concurrent workers do not pick the same reminders. Reminders of subscriptions that
+	// no longer end on the reminded date are marked as skipped instead of claimed.
+	ClaimReminders(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Reminder, error)
+	MarkReminderSent(ctx context.Context, id int64, sentAt time.Time) error
+	// MarkReminderFailed records a failed delivery attempt. The reminder is retried at nextAttempt,
+	// or marked as failed for good if nextAttempt is zero.
+	MarkReminderFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error
+}
+
+// ---------------- Webhook Repository ----------------
+
+type WebhookRepo interface {
+	CreateEndpoint(ctx context.Context, endpoint WebhookEndpoint) (string, error)
+	GetEndpoint(ctx context.Context, id string) (WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	// EnqueueDeliveries schedules the event payload for every endpoint subscribed to its type
+	// and returns how many deliveries were scheduled.
+	EnqueueDeliveries(ctx context.Context, event Event, payload []byte) (int64, error)
+	// ClaimDeliveries leases up to limit pending deliveries due at now until now+lease,
+	// so concurrent workers do not pick the same deliveries.
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error
+	// MarkDeliveryFailed records a failed attempt. The delivery is retried at nextAttempt,
+	// or moved to the dead letters if nextAttempt is zero.
+	MarkDeliveryFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error
+	ListDeliveries(ctx context.Context, status DeliveryStatus) ([]WebhookDelivery, error)
+	// Redeliver makes the delivery pending again with a fresh attempt budget.
+	Redeliver(ctx context.Context, id int64) error
+}
+
+// WebhookSender makes a single signed delivery attempt.
+type WebhookSender interface {
+	Send(ctx context.Context, delivery WebhookDelivery) error
+}
+
+// ---------------- Outbox Repository ----------------
+
+type OutboxRepo interface {
+	// ClaimEvents leases up to limit unpublished events due at now until now+lease, oldest first,
+	// so concurrent relays do not pick the same events.
+	ClaimEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error)
+	MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error
+	// MarkPublishFailed records a failed publish, the event is retried at nextAttempt.
+	MarkPublishFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error
+}
+
+// ---------------- Analytics Repository ----------------
+
+// AnalyticsRepo aggregates subscriptions across all users. Every method leaves out
+// the groups with fewer distinct users than filter.MinGroupSize.
+type AnalyticsRepo interface {
+	// ServiceStats returns the services with the most users first, up to filter.Limit.
+	ServiceStats(ctx context.Context, filter AnalyticsFilter) ([]ServiceStats, error)
+	// PriceStats returns the price distribution of the services with the most subscriptions first, up to filter.Limit.
+	PriceStats(ctx context.Context, filter AnalyticsFilter) ([]PriceStats, error)
+	// ServiceUsers returns user counts per service and bucket of the window for the services
+	// with the most users, up to filter.Limit.
+	ServiceUsers(ctx context.Context, filter AnalyticsFilter) ([]ServiceUsers, error)
+}
+
 // ---------------- Subs Service ----------------
 
 type SubsService interface {
-	CreateSubscription(ctx context.Context, subs Subscription) error
+	CreateSubscription(ctx context.Context, subs Subscription) (string, error)
+	ImportSubscriptions(ctx context.Context, rows []ImportRow, opts ImportOptions) (ImportReport, error)
 	DeleteSubscription(ctx context.Context, serviceName string, userID string) error
 	DeleteSubscriptionList(ctx context.Context, userID string) error
+	RestoreSubscription(ctx context.Context, serviceName string, userID string) (Subscription, error)
+	PurgeDeletedSubscriptions(ctx context.Context, retention time.Duration) (int64, error)
+	ExpireSubscriptions(ctx context.Context) (int64, error)
+	ConvertTrials(ctx context.Context) (int64, error)
 	GetSubscription(ctx context.Context, serviceName string, userID string) (Subscription, error)
-	GetSubscriptionList(ctx context.Context, userID string) ([]Subscription, error)
+	GetSubscriptionList(ctx context.Context, filter ListFilter) (SubsList, error)
+	GetPriceHistory(ctx context.Context, serviceName string, userID string) ([]PricePoint, error)
+	GetAuditLog(ctx context.Context, filter AuditFilter) (AuditLog, error)
 	UpdateSubscription(ctx context.Context, subs Subscription) error
+	SubsByIDService
+	SubsStatusService
+	SubsShareService
 	SummaryService
 }
 
+type SubsByIDService interface {
+	GetSubscriptionByID(ctx context.Context, id string) (Subscription, error)
+	UpdateSubscriptionByID(ctx context.Context, subs Subscription) error
+	PatchSubscription(ctx context.Context, id string, patch SubsPatch) (Subscription, error)
+	DeleteSubscriptionByID(ctx context.Context, id string) error
+	SetSubscriptionTags(ctx context.Context, id string, tags []string) (Subscription, error)
+	SetSubscriptionDiscounts(ctx context.Context, id string, discounts []Discount) (Subscription, error)
+}
+
+type SubsStatusService interface {
+	ChangeSubscriptionStatus(ctx context.Context, serviceName string, userID string, to Status) (Subscription, error)
+	PauseSubscription(ctx context.Context, serviceName string, userID string) (Subscription, error)
+	ResumeSubscription(ctx context.Context, serviceName string, userID string, extendEndDate bool) (Subscription, error)
+	CancelSubscription(ctx context.Context, serviceName string, userID string, cancellation Cancellation) (Subscription, error)
+	GetStatusHistory(ctx context.Context, serviceName string, userID string) ([]StatusChange, error)
+}
+
+type SubsShareService interface {
+	SetSubscriptionParticipants(ctx context.Context, id string, participants []Participant) (Subscription, error)
+	RespondToShare(ctx context.Context, id, userID string, to ShareStatus) (Subscription, error)
+	GetSharedSubscriptions(ctx context.Context, userID string) ([]Subscription, error)
+}
+
 type SummaryService interface {
-	GetSummaryByFilter(ctx context.Context, start time.Time, end time.Time, serviceName, userID string, pageNum, pageSize int) (Summary, error)
+	GetSummaryByFilter(ctx context.Context, filter SummaryFilter) (Summary, error)
+	GetTrialReport(ctx context.Context, filter TrialReportFilter) ([]TrialConversion, error)
+	GetForecast(ctx context.Context, userID string, months int) (Forecast, error)
+	GetSpendSeries(ctx context.Context, filter SpendSeriesFilter) (SpendSeries, error)
+	GetChurnReport(ctx context.Context, filter ChurnFilter) ([]Churn, error)
+}
+
+// ---------------- Budget Service ----------------
+
+type BudgetService interface {
+	CreateBudget(ctx context.Context, budget Budget) (string, error)
+	GetBudget(ctx context.Context, id string) (Budget, error)
+	ListBudgets(ctx context.Context, userID string) ([]Budget, error)
+	UpdateBudget(ctx context.Context, budget Budget) error
+	DeleteBudget(ctx context.Context, id string) error
+	GetAlerts(ctx context.Context, filter AlertFilter) ([]BudgetAlert, error)
+	BudgetEvaluator
+}
+
+// BudgetEvaluator records alerts for the budgets of the user whose thresholds
+// the projected spend of the month containing at has reached.
+type BudgetEvaluator interface {
+	EvaluateBudgets(ctx context.Context, userID string, at time.Time) error
+}
+
+// ---------------- Webhook Service ----------------
+
+type WebhookService interface {
+	// CreateEndpoint registers the endpoint and returns it with its secret, which is not shown afterwards.
+	CreateEndpoint(ctx context.Context, endpoint WebhookEndpoint) (WebhookEndpoint, error)
+	GetEndpoint(ctx context.Context, id string) (WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	GetDeadLetters(ctx context.Context) ([]WebhookDelivery, error)
+	Redeliver(ctx context.Context, id int64) error
+}
+
+// ---------------- Analytics Service ----------------
+
+type AnalyticsService interface {
+	GetServiceStats(ctx context.Context, filter AnalyticsFilter) ([]ServiceStats, error)
+	GetPriceStats(ctx context.Context, filter AnalyticsFilter) ([]PriceStats, error)
+	GetServiceUsers(ctx context.Context, filter AnalyticsFilter) ([]ServiceUsers, error)
+}
+
+// ---------------- Catalog Service ----------------
+
+type CatalogService interface {
+	CreateService(ctx context.Context, service Service) (string, error)
+	GetService(ctx context.Context, id string) (Service, error)
+	ListServices(ctx context.Context, status ServiceStatus) ([]Service, error)
+	UpdateService(ctx context.Context, service Service) error
+	DeleteService(ctx context.Context, id string) error
 }
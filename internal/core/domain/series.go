@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+)
+
+// MaxSpendBuckets limits the number of buckets of a single spend series.
+const MaxSpendBuckets = 1000
+
+// SpendSeriesFilter selects the subscriptions and the window of the spend series.
+// The window end date is inclusive, empty UserID, ServiceName and Tags do not filter.
+type SpendSeriesFilter struct {
+	Start       time.Time
+	End         time.Time
+	Bucket      PeriodUnit
+	UserID      string
+	ServiceName string
+	Tags        []string
+}
+
+// Validate checks the window, the bucket size and that the series is not too long.
+func (f SpendSeriesFilter) Validate() error {
+	if f.End.Before(f.Start) {
+		return ErrInvalidDate
+	}
+	return validateBuckets(f.Start, f.End, f.Bucket)
+}
+
+// validateBuckets checks that the bucket size is known and the window does not exceed MaxSpendBuckets of them.
+func validateBuckets(start, end time.Time, bucket PeriodUnit) error {
+	step := BillingPeriod{Unit: bucket, Interval: 1}
+	if step.Validate() != nil {
+		return ErrInvalidBucket
+	}
+
+	for n := 0; !step.Shift(start, n).After(end); n++ {
+		if n == MaxSpendBuckets {
+			return ErrInvalidBucket
+		}
+	}
+	return nil
+}
+
+// SpendSeries is the spend of the filtered subscriptions bucketed by day, week, month or year.
+// Buckets start at calendar boundaries in UTC, weeks on Mondays, while the first and the last bucket
+// are cut by the window. Buckets without spend are kept, so the series has no gaps.
+type SpendSeries struct {
+	Bucket     PeriodUnit   `json:"bucket"`
+	Start      time.Time    `json:"start"`
+	End        time.Time    `json:"end"`
+	TotalPrice int          `json:"total_price"`
+	Points     []SpendPoint `json:"points"`
+}
+
+// SpendPoint is the cost of the subscriptions within the bucket [Start, End)
+// and the number of subscriptions running at some moment of it.
+type SpendPoint struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	TotalPrice int       `json:"total_price"`
+	TotalItems int       `json:"total_items"`
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"time"
+)
+
+// ShareStatus is the answer of a participant to the invitation to share a subscription.
+type ShareStatus string
+
+const (
+	ShareInvited  ShareStatus = "invited"
+	ShareAccepted ShareStatus = "accepted"
+	ShareDeclined ShareStatus = "declined"
+)
+
+// Participant pays a share of a subscription owned by another user.
+// The share is either a fixed Amount of every charge or a Weight of what remains
+// after the fixed amounts, the owner always has weight 1.
+// Until the participant accepts, the share is paid by the owner.
+type Participant struct {
+	UserID      string      `json:"user_id"`
+	Weight      int         `json:"weight,omitempty"`
+	Amount      int         `json:"amount,omitempty"`
+	Status      ShareStatus `json:"status"`
+	InvitedAt   time.Time   `json:"invited_at"`
+	RespondedAt *time.Time  `json:"responded_at,omitempty"`
+}
+
+// Validate checks that the participant has either a positive weight or a positive fixed amount.
+func (p Participant) Validate() error {
+	if p.UserID == "" || p.Weight < 0 || p.Amount < 0 || (p.Weight > 0) == (p.Amount > 0) {
+		return ErrInvalidShare
+	}
+	return nil
+}
+
+// ValidateParticipants checks every participant and that no user, including the owner, is listed twice.
+func ValidateParticipants(ownerID string, participants []Participant) error {
+	seen := map[string]bool{ownerID: true}
+	for _, p := range participants {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if seen[p.UserID] {
+			return ErrInvalidShare
+		}
+		seen[p.UserID] = true
+	}
+	return nil
+}
+
+// CanRespond reports whether a participant with the status can answer with the given one.
+// Invited participants accept or decline, accepted ones can only leave by declining.
+func (s ShareStatus) CanRespond(to ShareStatus) bool {
+	switch s {
+	case ShareInvited:
+		return to == ShareAccepted || to == ShareDeclined
+	case ShareAccepted:
+		return to == ShareDeclined
+	default:
+		return false
+	}
+}
+
+// Participant returns the participant of the subscription with the given user ID.
+func (s Subscription) Participant(userID string) (Participant, bool) {
+	for _, p := range s.Participants {
+		if p.UserID == userID {
+			return p, true
+		}
+	}
+	return Participant{}, false
+}
+
+// IsShared reports whether anybody besides the owner pays for the subscription.
+func (s Subscription) IsShared() bool {
+	for _, p := range s.Participants {
+		if p.Status == ShareAccepted {
+			return true
+		}
+	}
+	return false
+}
+
+// ShareOf returns the part of the price paid by the user.
+// Accepted participants with a fixed amount pay it first, scaled down if the amounts exceed the price,
+// the remainder is split by weight between the owner and the accepted participants with a weight.
+// Users who neither own the subscription nor accepted a share pay nothing.
+func (s Subscription) ShareOf(userID string, price float64) float64 {
+	var fixed, weights float64 = 0, 1
+	for _, p := range s.Participants {
+		if p.Status == ShareAccepted {
+			fixed += float64(p.Amount)
+			weights += float64(p.Weight)
+		}
+	}
+	remainder := price - min(fixed, price)
+
+	if p, ok := s.Participant(userID); ok && p.Status == ShareAccepted {
+		if p.Amount > 0 {
+			return float64(p.Amount) * min(1, price/fixed)
+		}
+		return remainder * float64(p.Weight) / weights
+	}
+
+	if userID == s.UserID {
+		return remainder / weights
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status is the lifecycle state of a subscription.
+type Status string
+
+const (
+	StatusTrialing  Status = "trialing"
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	StatusExpired   Status = "expired"
+)
+
+// transitions lists allowed status changes, any status except expired itself can also become expired.
+var transitions = map[Status][]Status{
+	StatusTrialing: {StatusActive, StatusCancelled},
+	StatusActive:   {StatusPaused, StatusCancelled},
+	StatusPaused:   {StatusActive, StatusCancelled},
+}
+
+// IsValid reports whether the status is known.
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusTrialing, StatusActive, StatusPaused, StatusCancelled, StatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransition reports whether a subscription can move from the status to the given one.
+func (s Status) CanTransition(to Status) bool {
+	if to == StatusExpired {
+		return s != StatusExpired
+	}
+
+	for _, allowed := range transitions[s] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange is a single recorded status transition of a subscription.
+// Moving to paused opens a pause interval and moving from paused closes it,
+// ExtendEndDate pushes the end date back by the length of the closed pause.
+// Moving to cancelled records the Cancellation, or the unspecified reason without one.
+type StatusChange struct {
+	SubsID        string        `json:"-"`
+	From          Status        `json:"from"`
+	To            Status        `json:"to"`
+	ChangedAt     time.Time     `json:"changed_at"`
+	ExtendEndDate bool          `json:"-"`
+	Cancellation  *Cancellation `json:"-"`
+}
+
+// TransitionError is returned when a subscription can not move between the statuses.
+// It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From Status
+	To   Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
@@ -5,17 +5,114 @@ import (
 )
 
 type Subscription struct {
-	ServiceName string    `json:"service_name"`
-	Price       int       `json:"price"`
-	UserID      string    `json:"user_id"`
-	StartDate   time.Time `json:"start_date"`
-	EndDate     time.Time `json:"end_date"`
+	ID             string        `json:"id"`
+	ServiceName    string        `json:"service_name"`
+	ServiceID      string        `json:"service_id,omitempty"`
+	Price          int           `json:"price"`
+	EffectivePrice *int          `json:"effective_price,omitempty"`
+	UserID         string        `json:"user_id"`
+	StartDate      time.Time     `json:"start_date"`
+	EndDate        time.Time     `json:"end_date"`
+	TrialEndDate   *time.Time    `json:"trial_end_date,omitempty"`
+	TrialPrice     int           `json:"trial_price,omitempty"`
+	BillingPeriod  BillingPeriod `json:"billing_period"`
+	Status         Status        `json:"status"`
+	StatusChanged  time.Time     `json:"status_changed_at"`
+	Tags           []string      `json:"tags,omitempty"`
+	Discounts      []Discount    `json:"discounts,omitempty"`
+	Participants   []Participant `json:"participants,omitempty"`
+	UserShare      *int          `json:"user_share,omitempty"`
+	Pauses         []Pause       `json:"pauses,omitempty"`
+	PriceHistory   []PricePoint  `json:"-"`
+	RenewalDate    *time.Time    `json:"next_renewal_date,omitempty"`
+	// AutoRenew makes EndDate the end of the current term, the subscription renews
+	// for another billing period whenever a term ends instead of expiring.
+	AutoRenew bool `json:"auto_renew"`
+	// PendingService asks the repository to add the service name unknown to the catalog
+	// as a pending review service in the same transaction the subscription is created in.
+	PendingService bool `json:"-"`
 }
 
+// PricePoint is a price of the subscription in effect from the given date
+// until the next price point.
+type PricePoint struct {
+	Price         int       `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+// PriceAt returns the price in effect at the given moment.
+// Moments before the first recorded price use the earliest price,
+// subscriptions without history use the current price.
+func (s Subscription) PriceAt(t time.Time) int {
+	if len(s.PriceHistory) == 0 {
+		return s.Price
+	}
+
+	price := s.PriceHistory[0].Price
+	for _, point := range s.PriceHistory {
+		if point.EffectiveFrom.After(t) {
+			break
+		}
+		price = point.Price
+	}
+	return price
+}
+
+// Pause is an interval when the subscription was paused and not billed.
+// ResumedAt is nil while the subscription is still paused.
+type Pause struct {
+	PausedAt  time.Time  `json:"paused_at"`
+	ResumedAt *time.Time `json:"resumed_at,omitempty"`
+}
+
+// Summary totals are calculated over the whole filtered set,
+// while the page subtotal and breakdown only cover the returned page.
 type Summary struct {
-	TotalPrice    int            `json:"total_price"`
-	SubsCount     int            `json:"total_subscriptions"`
-	PageNumber    int            `json:"page_number"`
-	PageSize      int            `json:"max_page_size"`
-	Subscriptions []Subscription `json:"subscriptions"`
+	TotalPrice    int             `json:"total_price"`
+	TotalItems    int             `json:"total_items"`
+	TotalPages    int             `json:"total_pages"`
+	PageTotal     int             `json:"page_total"`
+	PageNumber    int             `json:"page_number,omitempty"`
+	PageSize      int             `json:"max_page_size"`
+	NextCursor    string          `json:"next_cursor,omitempty"`
+	PrevCursor    string          `json:"prev_cursor,omitempty"`
+	Subscriptions []Subscription  `json:"subscriptions"`
+	Breakdown     []CostBreakdown `json:"breakdown"`
+	Groups        []SummaryGroup  `json:"groups,omitempty"`
+}
+
+// SubsPage is a page of filtered subscriptions together with
+// the count and cost of the whole filtered set.
+// HasMore reports whether more rows follow the page in the direction it was read.
+type SubsPage struct {
+	Subscriptions []Subscription
+	TotalItems    int
+	TotalPrice    int
+	HasMore       bool
+}
+
+// CostBreakdown explains how the amount of a single subscription inside the summary window was calculated.
+// Billing cycles fully covered by the window are charged the full price,
+// cycles cut by the window, by the subscription dates or by pauses are prorated by days.
+// Each cycle is charged the price in effect on its billing date with its discount phase applied,
+// while Price is the current list price. ListAmount is what the window would cost without discounts.
+// If ShareOf is set, the amounts only cover that user's share of a shared subscription.
+// Trial time is not billed by cycles, the trial price is charged once if the trial starts inside the window.
+type CostBreakdown struct {
+	ServiceName    string        `json:"service_name"`
+	UserID         string        `json:"user_id"`
+	ShareOf        string        `json:"share_of,omitempty"`
+	Price          int           `json:"price"`
+	BillingPeriod  BillingPeriod `json:"billing_period"`
+	BilledFrom     time.Time     `json:"billed_from"`
+	BilledUntil    time.Time     `json:"billed_until"`
+	FullCycles     int           `json:"full_cycles"`
+	ProratedDays   float64       `json:"prorated_days"`
+	PausedDays     float64       `json:"paused_days"`
+	TrialDays      float64       `json:"trial_days,omitempty"`
+	TrialAmount    int           `json:"trial_amount,omitempty"`
+	ProratedAmount int           `json:"prorated_amount"`
+	ListAmount     int           `json:"list_amount"`
+	DiscountAmount int           `json:"discount_amount,omitempty"`
+	Amount         int           `json:"amount"`
 }
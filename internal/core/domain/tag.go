@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeTags trims and lower-cases tag names, dropping empty ones and duplicates.
+// The result is sorted so equal tag sets compare equal.
+func NormalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) != 0 {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// GroupBy selects how summary subtotals are grouped.
+type GroupBy string
+
+const (
+	GroupByTag      GroupBy = "tag"
+	GroupByService  GroupBy = "service"
+	GroupByCategory GroupBy = "category"
+	GroupByMonth    GroupBy = "month"
+)
+
+func (g GroupBy) IsValid() bool {
+	switch g {
+	case GroupByTag, GroupByService, GroupByCategory, GroupByMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+// SummaryGroup is the subtotal of the filtered subscriptions sharing the group key.
+// A subscription with several tags is counted in each of their groups,
+// so tag subtotals may add up to more than the summary total.
+type SummaryGroup struct {
+	Key        string `json:"key"`
+	TotalPrice int    `json:"total_price"`
+	TotalItems int    `json:"total_items"`
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// BillingStart returns the date billing cycles are counted from,
+// which is the end of the trial for subscriptions started with one.
+func (s Subscription) BillingStart() time.Time {
+	if s.TrialEndDate != nil {
+		return *s.TrialEndDate
+	}
+	return s.StartDate
+}
+
+// ValidateTrial checks that the trial ends within the subscription term and its price is not negative.
+// A trial price without a trial end date is rejected as well.
+func (s Subscription) ValidateTrial() error {
+	if s.TrialEndDate == nil {
+		if s.TrialPrice != 0 {
+			return ErrInvalidTrial
+		}
+		return nil
+	}
+
+	if s.TrialPrice < 0 || s.TrialEndDate.Before(s.StartDate) {
+		return ErrInvalidTrial
+	}
+
+	if !s.EndDate.IsZero() && s.TrialEndDate.After(s.EndDate) {
+		return ErrInvalidTrial
+	}
+	return nil
+}
+
+// TrialReportFilter selects the trials of the report by service and by the start date.
+// Zero Start or End leaves the window open on that side, End is inclusive.
+type TrialReportFilter struct {
+	ServiceName string
+	Start       time.Time
+	End         time.Time
+}
+
+// TrialConversion sums up how the trials of a single service ended.
+// Trials which are still running are not counted into the conversion rate.
+type TrialConversion struct {
+	ServiceName    string  `json:"service_name"`
+	Trials         int     `json:"trials"`
+	Converted      int     `json:"converted"`
+	Cancelled      int     `json:"cancelled"`
+	Expired        int     `json:"expired"`
+	Trialing       int     `json:"trialing"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// SetConversionRate sets the share of the ended trials that converted to paid, rounded to 4 decimals.
+func (c *TrialConversion) SetConversionRate() {
+	ended := c.Converted + c.Cancelled + c.Expired
+	if ended == 0 {
+		c.ConversionRate = 0
+		return
+	}
+	c.ConversionRate = math.Round(float64(c.Converted)/float64(ended)*10000) / 10000
+}
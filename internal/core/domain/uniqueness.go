@@ -0,0 +1,20 @@
+package domain
+
+// UniquenessPolicy decides how many live subscriptions of the same service a user may hold.
+type UniquenessPolicy string
+
+const (
+	// UniquePerService allows a single subscription per service and user.
+	UniquePerService UniquenessPolicy = "service"
+	// UniqueNone allows any number of subscriptions of the same service, e.g. two storage accounts.
+	UniqueNone UniquenessPolicy = "none"
+)
+
+func (p UniquenessPolicy) IsValid() bool {
+	switch p {
+	case UniquePerService, UniqueNone:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// WebhookEndpoint receives subscription events of the listed types, or of all types if none are listed.
+// Deliveries are signed with the endpoint secret.
+type WebhookEndpoint struct {
+	ID         string      `json:"id"`
+	URL        string      `json:"url"`
+	Secret     string      `json:"secret,omitempty"`
+	EventTypes []EventType `json:"event_types"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// Validate checks the endpoint URL and event type filter.
+func (e WebhookEndpoint) Validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return ErrInvalidWebhook
+	}
+
+	for _, eventType := range e.EventTypes {
+		if !eventType.IsValid() {
+			return ErrInvalidWebhook
+		}
+	}
+	return nil
+}
+
+// Accepts reports whether the endpoint is subscribed to the event type.
+func (e WebhookEndpoint) Accepts(eventType EventType) bool {
+	return len(e.EventTypes) == 0 || slices.Contains(e.EventTypes, eventType)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead marks deliveries that ran out of attempts, they are only retried manually.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is a single event sent to a single endpoint. URL and Secret are those
+// of the endpoint at the time the delivery is claimed.
+type WebhookDelivery struct {
+	ID            int64           `json:"id"`
+	EndpointID    string          `json:"endpoint_id"`
+	EventID       string          `json:"event_id"`
+	EventType     EventType       `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	URL           string          `json:"-"`
+	Secret        string          `json:"-"`
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+)
+
+// AnalyticsService reports aggregates of subscriptions across all users.
+type AnalyticsService struct {
+	repo domain.AnalyticsRepo
+	// minGroupSize is the floor of the minimum group size, requests may only raise it
+	minGroupSize int
+	log          logger.Logger
+}
+
+// NewAnalyticsService creates the analytics service, groups with fewer distinct users than minGroupSize
+// are never reported. A minGroupSize below 1 falls back to the default one.
+func NewAnalyticsService(repo domain.AnalyticsRepo, minGroupSize int, log logger.Logger) *AnalyticsService {
+	if minGroupSize < 1 {
+		minGroupSize = domain.DefaultMinGroupSize
+	}
+	return &AnalyticsService{
+		repo:         repo,
+		minGroupSize: minGroupSize,
+		log:          log,
+	}
+}
+
+// GetServiceStats returns the most popular services with their user counts and total spend.
+func (s *AnalyticsService) GetServiceStats(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.ServiceStats, error) {
+	const op = "AnalyticsService.GetServiceStats"
+	filter = s.normalize(filter)
+	log := s.log.With(analyticsAttrs(op, filter)...)
+
+	if err := filter.Validate(); err != nil {
+		log.Error("Invalid analytics filter", "error", err)
+		return nil, err
+	}
+
+	stats, err := s.repo.ServiceStats(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get service stats", "error", err)
+		return nil, err
+	}
+
+	log.Info("Service stats have been retrieved", slog.Int("services", len(stats)))
+	return stats, nil
+}
+
+// GetPriceStats returns the average and the median of the service prices.
+func (s *AnalyticsService) GetPriceStats(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.PriceStats, error) {
+	const op = "AnalyticsService.GetPriceStats"
+	filter = s.normalize(filter)
+	log := s.log.With(analyticsAttrs(op, filter)...)
+
+	if err := filter.Validate(); err != nil {
+		log.Error("Invalid analytics filter", "error", err)
+		return nil, err
+	}
+
+	stats, err := s.repo.PriceStats(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get price stats", "error", err)
+		return nil, err
+	}
+
+	log.Info("Price stats have been retrieved", slog.Int("services", len(stats)))
+	return stats, nil
+}
+
+// GetServiceUsers returns user counts of the most popular services per bucket, monthly by default.
+func (s *AnalyticsService) GetServiceUsers(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.ServiceUsers, error) {
+	const op = "AnalyticsService.GetServiceUsers"
+	filter = s.normalize(filter)
+	if filter.Bucket == "" {
+		filter.Bucket = domain.PeriodMonth
+	}
+	log := s.log.With(append(analyticsAttrs(op, filter), slog.String("bucket", string(filter.Bucket)))...)
+
+	if err := filter.Validate(); err != nil {
+		log.Error("Invalid analytics filter", "error", err)
+		return nil, err
+	}
+
+	points, err := s.repo.ServiceUsers(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get service users", "error", err)
+		return nil, err
+	}
+
+	log.Info("Service users have been retrieved", slog.Int("points", len(points)))
+	return points, nil
+}
+
+// normalize applies the default limit and raises the minimum group size to the configured floor.
+func (s *AnalyticsService) normalize(filter domain.AnalyticsFilter) domain.AnalyticsFilter {
+	if filter.Limit == 0 {
+		filter.Limit = domain.DefaultAnalyticsLimit
+	}
+	filter.MinGroupSize = max(filter.MinGroupSize, s.minGroupSize)
+	return filter
+}
+
+func analyticsAttrs(op string, filter domain.AnalyticsFilter) []any {
+	return []any{
+		slog.String("op", op),
+		slog.String("service_name", filter.ServiceName),
+		slog.String("filter_start_date", filter.Start.String()),
+		slog.String("filter_end_date", filter.End.String()),
+		slog.Int("limit", filter.Limit),
+		slog.Int("min_group_size", filter.MinGroupSize),
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"submanager/internal/core/domain"
+)
+
+// GetAuditLog retrieves a page of audit events of the user's subscriptions, newest first.
+func (s *SubsService) GetAuditLog(ctx context.Context, filter domain.AuditFilter) (domain.AuditLog, error) {
+	const op = "SubsService.GetAuditLog"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_ID", filter.UserID),
+		slog.String("filter_start_date", filter.Start.String()),
+		slog.String("filter_end_date", filter.End.String()),
+		slog.Int("page_size", filter.PageSize),
+	)
+
+	if !filter.Start.IsZero() && !filter.End.IsZero() && filter.End.Before(filter.Start) {
+		return domain.AuditLog{}, domain.ErrInvalidDate
+	}
+
+	auditPage, err := s.repo.AuditEvents(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get audit events", "error", err)
+		return domain.AuditLog{}, err
+	}
+
+	auditLog := domain.AuditLog{Events: auditPage.Events}
+	if auditLog.Events == nil {
+		auditLog.Events = []domain.AuditEvent{}
+	}
+
+	if events := auditPage.Events; len(events) != 0 {
+		first := domain.Cursor{StartDate: events[0].CreatedAt, ID: strconv.FormatInt(events[0].ID, 10)}
+		last := domain.Cursor{StartDate: events[len(events)-1].CreatedAt, ID: strconv.FormatInt(events[len(events)-1].ID, 10)}
+		auditLog.NextCursor, auditLog.PrevCursor = neighbourCursors(filter.Pagination, first, last, auditPage.HasMore)
+	}
+
+	log.Info("Audit log has been retrieved")
+	return auditLog, nil
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+	"time"
+)
+
+// monthLayout formats budget alert months.
+const monthLayout = "2006-01"
+
+type BudgetService struct {
+	repo    domain.BudgetRepo
+	subs    domain.SubsGetter
+	catalog domain.CatalogRepo
+	log     logger.Logger
+}
+
+// BudgetOption configures optional behaviour of BudgetService.
+type BudgetOption func(s *BudgetService)
+
+// WithBudgetCatalog makes budgets resolve their service or category scope against the catalog,
+// so the scope is stored with the canonical service name or category spelling.
+func WithBudgetCatalog(catalog domain.CatalogRepo) BudgetOption {
+	return func(s *BudgetService) {
+		s.catalog = catalog
+	}
+}
+
+// NewBudgetService creates the budget service, spend is calculated from the subscriptions of subs.
+func NewBudgetService(repo domain.BudgetRepo, subs domain.SubsGetter, log logger.Logger, opts ...BudgetOption) *BudgetService {
+	s := &BudgetService{
+		repo: repo,
+		subs: subs,
+		log:  log,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// CreateBudget stores a budget of the user and returns its ID.
+// Budgets without thresholds are alerted at the default ones, the scope is resolved against the catalog.
+func (s *BudgetService) CreateBudget(ctx context.Context, budget domain.Budget) (string, error) {
+	const op = "BudgetService.CreateBudget"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", budget.UserID),
+		slog.Int("limit", budget.Limit),
+	)
+
+	budget = budget.Normalize()
+	if err := budget.Validate(); err != nil {
+		log.Error("Invalid budget", "error", err)
+		return "", err
+	}
+
+	if err := s.resolveScope(ctx, &budget); err != nil {
+		log.Error("Failed to resolve budget scope", "error", err)
+		return "", err
+	}
+
+	id, err := s.repo.CreateBudget(ctx, budget)
+	if err != nil {
+		log.Error("Failed to create budget", "error", err)
+		return "", err
+	}
+
+	log.Info("Budget has been created", slog.String("ID", id))
+	return id, nil
+}
+
+// GetBudget retrieves a budget by its ID.
+func (s *BudgetService) GetBudget(ctx context.Context, id string) (domain.Budget, error) {
+	const op = "BudgetService.GetBudget"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	budget, err := s.repo.GetBudget(ctx, id)
+	if err != nil {
+		log.Error("Failed to get budget", "error", err)
+		return domain.Budget{}, err
+	}
+
+	log.Info("Budget has been retrieved")
+	return budget, nil
+}
+
+// ListBudgets retrieves all budgets of the user.
+func (s *BudgetService) ListBudgets(ctx context.Context, userID string) ([]domain.Budget, error) {
+	const op = "BudgetService.ListBudgets"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+	)
+
+	budgets, err := s.repo.ListBudgets(ctx, userID)
+	if err != nil {
+		log.Error("Failed to list budgets", "error", err)
+		return nil, err
+	}
+
+	log.Info("Budgets have been retrieved")
+	return budgets, nil
+}
+
+// UpdateBudget overwrites the limit, scope and thresholds of a budget.
+func (s *BudgetService) UpdateBudget(ctx context.Context, budget domain.Budget) error {
+	const op = "BudgetService.UpdateBudget"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", budget.ID),
+		slog.Int("limit", budget.Limit),
+	)
+
+	budget = budget.Normalize()
+	if err := budget.Validate(); err != nil {
+		log.Error("Invalid budget", "error", err)
+		return err
+	}
+
+	if err := s.resolveScope(ctx, &budget); err != nil {
+		log.Error("Failed to resolve budget scope", "error", err)
+		return err
+	}
+
+	if err := s.repo.UpdateBudget(ctx, budget); err != nil {
+		log.Error("Failed to update budget", "error", err)
+		return err
+	}
+
+	log.Info("Budget has been updated")
+	return nil
+}
+
+// DeleteBudget removes a budget with its alerts.
+func (s *BudgetService) DeleteBudget(ctx context.Context, id string) error {
+	const op = "BudgetService.DeleteBudget"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	if err := s.repo.DeleteBudget(ctx, id); err != nil {
+		log.Error("Failed to delete budget", "error", err)
+		return err
+	}
+
+	log.Info("Budget has been deleted")
+	return nil
+}
+
+// GetAlerts retrieves budget alerts of the user, newest first.
+func (s *BudgetService) GetAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.BudgetAlert, error) {
+	const op = "BudgetService.GetAlerts"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", filter.UserID),
+		slog.String("month", filter.Month),
+	)
+
+	if len(filter.Month) != 0 {
+		if _, err := time.Parse(monthLayout, filter.Month); err != nil {
+			return nil, domain.ErrInvalidMonth
+		}
+	}
+
+	alerts, err := s.repo.ListAlerts(ctx, filter)
+	if err != nil {
+		log.Error("Failed to list budget alerts", "error", err)
+		return nil, err
+	}
+
+	log.Info("Budget alerts have been retrieved")
+	return alerts, nil
+}
+
+// EvaluateBudgets calculates the projected spend of the UTC month containing at for every budget
+// of the user and records alerts for the thresholds it has reached.
+func (s *BudgetService) EvaluateBudgets(ctx context.Context, userID string, at time.Time) error {
+	const op = "BudgetService.EvaluateBudgets"
+	month := domain.BudgetMonth(at)
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID),
+		slog.String("month", month.Format(monthLayout)),
+	)
+
+	budgets, err := s.repo.ListBudgets(ctx, userID)
+	if err != nil {
+		log.Error("Failed to list budgets", "error", err)
+		return err
+	}
+
+	for _, budget := range budgets {
+		spend, err := s.spend(ctx, budget, month)
+		if err != nil {
+			log.Error("Failed to calculate budget spend", "budget_id", budget.ID, "error", err)
+			return err
+		}
+
+		for _, threshold := range budget.Reached(spend) {
+			alert := domain.BudgetAlert{
+				BudgetID:  budget.ID,
+				UserID:    userID,
+				Threshold: threshold,
+				Month:     month.Format(monthLayout),
+				Spend:     spend,
+				Limit:     budget.Limit,
+			}
+			added, err := s.repo.AddAlert(ctx, alert)
+			if err != nil {
+				log.Error("Failed to record budget alert", "budget_id", budget.ID, "error", err)
+				return err
+			}
+			if added {
+				log.Info("Budget threshold has been reached", "budget_id", budget.ID,
+					"threshold", threshold, "spend", spend, "limit", budget.Limit)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveScope replaces the service name of the budget scope with the canonical name of the catalog service
+// it is a name or alias of, and the category with the spelling used by the catalog ignoring case.
+// Names and categories unknown to the catalog are kept as they are.
+func (s *BudgetService) resolveScope(ctx context.Context, budget *domain.Budget) error {
+	if s.catalog == nil {
+		return nil
+	}
+
+	if len(budget.ServiceName) != 0 {
+		service, err := s.catalog.FindService(ctx, budget.ServiceName)
+		if errors.Is(err, domain.ErrServiceNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		budget.ServiceName = service.Name
+	}
+
+	if len(budget.Category) != 0 {
+		services, err := s.catalog.ListServices(ctx, "")
+		if err != nil {
+			return err
+		}
+		for _, service := range services {
+			if strings.EqualFold(service.Category, budget.Category) {
+				budget.Category = service.Category
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// spend calculates the cost of the budget scope subscriptions within the month.
+// Subscriptions are matched to the service scope through the catalog aliases and to the category ignoring case.
+func (s *BudgetService) spend(ctx context.Context, budget domain.Budget, month time.Time) (int, error) {
+	filter := domain.SummaryFilter{
+		Start:       month,
+		End:         month.AddDate(0, 1, -1),
+		Mode:        domain.FilterOverlap,
+		UserID:      budget.UserID,
+		ServiceName: budget.ServiceName,
+		GroupBy:     domain.GroupByMonth,
+	}
+	if len(budget.Category) != 0 {
+		filter.GroupBy = domain.GroupByCategory
+	}
+
+	groups, err := s.subs.SummaryGroups(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var spend int
+	for _, group := range groups {
+		if len(budget.Category) == 0 || strings.EqualFold(group.Key, budget.Category) {
+			spend += group.TotalPrice
+		}
+	}
+	return spend, nil
+}
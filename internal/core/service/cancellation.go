@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+)
+
+// GetChurnReport returns per service and period how many subscriptions were cancelled,
+// how long they lasted on average and why they were cancelled.
+func (s *SubsService) GetChurnReport(ctx context.Context, filter domain.ChurnFilter) ([]domain.Churn, error) {
+	const op = "SubsService.GetChurnReport"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("service_name", filter.ServiceName),
+		slog.String("bucket", string(filter.Bucket)),
+		slog.String("filter_start_date", filter.Start.String()),
+		slog.String("filter_end_date", filter.End.String()),
+	)
+
+	if filter.Bucket == "" {
+		filter.Bucket = domain.PeriodMonth
+	}
+	if err := filter.Validate(); err != nil {
+		log.Error("Invalid churn filter", "error", err)
+		return nil, err
+	}
+
+	report, err := s.repo.ChurnReport(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get churn report", "error", err)
+		return nil, err
+	}
+
+	log.Info("Churn report has been retrieved", slog.Int("periods", len(report)))
+	return report, nil
+}
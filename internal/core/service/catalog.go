@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+)
+
+type CatalogService struct {
+	repo domain.CatalogRepo
+	log  logger.Logger
+}
+
+func NewCatalogService(repo domain.CatalogRepo, log logger.Logger) *CatalogService {
+	return &CatalogService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// CreateService adds a service to the catalog and returns its ID.
+// Services created by maintainers are approved unless another status is given.
+func (s *CatalogService) CreateService(ctx context.Context, service domain.Service) (string, error) {
+	const op = "CatalogService.CreateService"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("name", service.Name),
+	)
+
+	if service.Status == "" {
+		service.Status = domain.ServiceApproved
+	}
+
+	if err := service.Validate(); err != nil {
+		log.Error("Invalid catalog service", "error", err)
+		return "", err
+	}
+
+	id, err := s.repo.CreateService(ctx, service)
+	if err != nil {
+		log.Error("Failed to create catalog service", "error", err)
+		return "", err
+	}
+
+	log.Info("Catalog service has been created", slog.String("ID", id))
+	return id, nil
+}
+
+// GetService retrieves a catalog service by its ID.
+func (s *CatalogService) GetService(ctx context.Context, id string) (domain.Service, error) {
+	const op = "CatalogService.GetService"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	service, err := s.repo.GetService(ctx, id)
+	if err != nil {
+		log.Error("Failed to get catalog service", "error", err)
+		return domain.Service{}, err
+	}
+
+	log.Info("Catalog service has been retrieved")
+	return service, nil
+}
+
+// ListServices retrieves catalog services, optionally only those with the given status.
+func (s *CatalogService) ListServices(ctx context.Context, status domain.ServiceStatus) ([]domain.Service, error) {
+	const op = "CatalogService.ListServices"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("status", string(status)),
+	)
+
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidServiceStatus
+	}
+
+	services, err := s.repo.ListServices(ctx, status)
+	if err != nil {
+		log.Error("Failed to list catalog services", "error", err)
+		return nil, err
+	}
+
+	log.Info("Catalog services have been retrieved")
+	return services, nil
+}
+
+// UpdateService overwrites a catalog service, approving a pending service is done by setting its status.
+func (s *CatalogService) UpdateService(ctx context.Context, service domain.Service) error {
+	const op = "CatalogService.UpdateService"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", service.ID),
+		slog.String("name", service.Name),
+	)
+
+	if service.Status == "" {
+		service.Status = domain.ServiceApproved
+	}
+
+	if err := service.Validate(); err != nil {
+		log.Error("Invalid catalog service", "error", err)
+		return err
+	}
+
+	if err := s.repo.UpdateService(ctx, service); err != nil {
+		log.Error("Failed to update catalog service", "error", err)
+		return err
+	}
+
+	log.Info("Catalog service has been updated")
+	return nil
+}
+
+// DeleteService removes a service from the catalog.
+func (s *CatalogService) DeleteService(ctx context.Context, id string) error {
+	const op = "CatalogService.DeleteService"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	if err := s.repo.DeleteService(ctx, id); err != nil {
+		log.Error("Failed to delete catalog service", "error", err)
+		return err
+	}
+
+	log.Info("Catalog service has been deleted")
+	return nil
+}
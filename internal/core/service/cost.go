@@ -0,0 +1,149 @@
+package service
+
+import (
+	"math"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// subsCost calculates how much the subscription costs inside the window [start, end].
+// The window end date is inclusive, so 2025-05-01..2025-05-31 covers the whole May.
+// Paused time is not billed, so cycles touched by a pause are prorated by the remaining days.
+// Trial time is not billed either, billing cycles start at the end of the trial
+// and the trial price is charged once if the subscription starts inside the window.
+// Every cycle is charged its effective price, the list price is only summed up for comparison.
+// If viewer is set, only the viewer's share of every charge is counted.
+func subsCost(subs domain.Subscription, start, end time.Time, viewer string) domain.CostBreakdown {
+	period := subs.BillingPeriod
+	if period.Validate() != nil {
+		period = domain.DefaultBillingPeriod
+	}
+
+	breakdown := domain.CostBreakdown{
+		ServiceName:   subs.ServiceName,
+		UserID:        subs.UserID,
+		Price:         subs.Price,
+		BillingPeriod: period,
+	}
+
+	share := func(price int) float64 { return float64(price) }
+	if viewer != "" {
+		share = func(price int) float64 { return subs.ShareOf(viewer, float64(price)) }
+		if subs.IsShared() {
+			breakdown.ShareOf = viewer
+		}
+	}
+
+	// Billed interval is the intersection of the window and the paid part of the subscription term
+	anchor := subs.BillingStart()
+	from, until := start, end.AddDate(0, 0, 1)
+	if subs.TrialEndDate != nil {
+		trialFrom, trialUntil := maxTime(subs.StartDate, from), minTime(anchor, until)
+		if !subs.EndDate.IsZero() {
+			trialUntil = minTime(trialUntil, subs.EndDate)
+		}
+		if trialFrom.Before(trialUntil) {
+			breakdown.TrialDays = trialUntil.Sub(trialFrom).Hours() / 24
+		}
+		if !subs.StartDate.Before(from) && subs.StartDate.Before(until) {
+			breakdown.TrialAmount = int(math.Round(share(subs.TrialPrice)))
+		}
+	}
+
+	if anchor.After(from) {
+		from = anchor
+	}
+	if !subs.EndDate.IsZero() && subs.EndDate.Before(until) {
+		until = subs.EndDate
+	}
+	if !from.Before(until) {
+		breakdown.Amount, breakdown.ListAmount = breakdown.TrialAmount, breakdown.TrialAmount
+		return breakdown
+	}
+	breakdown.BilledFrom, breakdown.BilledUntil = from, until
+
+	var full, listFull, prorated, listProrated float64
+	for n := firstCycle(period, anchor, from); ; n++ {
+		cycleStart, cycleEnd := period.Shift(anchor, n), period.Shift(anchor, n+1)
+		if !cycleStart.Before(until) {
+			break
+		}
+		if !cycleEnd.After(from) {
+			continue
+		}
+
+		// Each cycle is charged the price in effect on its billing date with its discount applied
+		price, listPrice := share(subs.CyclePrice(n, cycleStart)), share(subs.PriceAt(cycleStart))
+
+		overlapStart, overlapEnd := maxTime(cycleStart, from), minTime(cycleEnd, until)
+		paused := pausedTime(subs.Pauses, overlapStart, overlapEnd)
+		if paused == 0 && overlapStart.Equal(cycleStart) && overlapEnd.Equal(cycleEnd) {
+			breakdown.FullCycles++
+			full += price
+			listFull += listPrice
+			continue
+		}
+
+		breakdown.PausedDays += paused.Hours() / 24
+		days := (overlapEnd.Sub(overlapStart) - paused).Hours() / 24
+		cycleDays := cycleEnd.Sub(cycleStart).Hours() / 24
+		breakdown.ProratedDays += days
+		prorated += price * days / cycleDays
+		listProrated += listPrice * days / cycleDays
+	}
+
+	breakdown.ProratedAmount = int(math.Round(prorated))
+	breakdown.Amount = breakdown.TrialAmount + int(math.Round(full)) + breakdown.ProratedAmount
+	breakdown.ListAmount = breakdown.TrialAmount + int(math.Round(listFull)) + int(math.Round(listProrated))
+	breakdown.DiscountAmount = breakdown.ListAmount - breakdown.Amount
+	return breakdown
+}
+
+// firstCycle returns a cycle index that starts no later than t,
+// so long running subscriptions do not have to be walked from the very first cycle.
+func firstCycle(period domain.BillingPeriod, anchor, t time.Time) int {
+	maxDays := map[domain.PeriodUnit]int{
+		domain.PeriodDay:   1,
+		domain.PeriodWeek:  7,
+		domain.PeriodMonth: 31,
+		domain.PeriodYear:  366,
+	}[period.Unit] * period.Interval
+
+	if !t.After(anchor) || maxDays == 0 {
+		return 0
+	}
+	return int(t.Sub(anchor)/day) / maxDays
+}
+
+// pausedTime returns how much of the interval [from, until) the subscription was paused.
+// A pause which has not been resumed yet lasts until the end of the interval.
+func pausedTime(pauses []domain.Pause, from, until time.Time) time.Duration {
+	var paused time.Duration
+	for _, pause := range pauses {
+		pauseEnd := until
+		if pause.ResumedAt != nil {
+			pauseEnd = minTime(*pause.ResumedAt, until)
+		}
+
+		if pauseStart := maxTime(pause.PausedAt, from); pauseStart.Before(pauseEnd) {
+			paused += pauseEnd.Sub(pauseStart)
+		}
+	}
+	return paused
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"submanager/internal/pkg/logger"
+	"time"
+)
+
+const (
+	// deliveryLease is how long a claimed reminder or webhook delivery is hidden from other workers while it is being sent.
+	deliveryLease = 5 * time.Minute
+	// deliveryBatch limits how many reminders or webhook deliveries are sent in a single run.
+	deliveryBatch = 100
+	// deliverySendWindow is how long a run may spend sending what it claimed. It ends well before the lease does,
+	// so every delivered item is recorded before another run can claim it again.
+	deliverySendWindow = deliveryLease - time.Minute
+	// maxRetryDelay caps the backoff, so deliveries with many attempts are still retried daily.
+	maxRetryDelay = 24 * time.Hour
+	// maxBackoffShift caps how many times the retry delay is doubled, so the shift never overflows.
+	maxBackoffShift = 30
+)
+
+// retryPolicy spaces out the attempts of a failed delivery with exponential backoff.
+type retryPolicy struct {
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// nextAttempt returns when the attempt following the failed one is due,
+// or the zero time if the failed attempt was the last one. Attempts are counted from 1.
+// The delay doubles with every attempt up to maxRetryDelay.
+func (p retryPolicy) nextAttempt(failed int, now time.Time) time.Time {
+	if failed >= p.maxAttempts {
+		return time.Time{}
+	}
+
+	shift := min(max(failed-1, 0), maxBackoffShift)
+	delay := p.retryDelay << shift
+	if delay <= 0 || delay > maxRetryDelay || delay>>shift != p.retryDelay {
+		delay = maxRetryDelay
+	}
+	return now.Add(delay)
+}
+
+// leasedDelivery describes how a claimed item is sent and how the outcome of an attempt is recorded.
+type leasedDelivery[T any] struct {
+	// name is how the item is called in the log, e.g. "reminder"
+	name       string
+	attempts   func(item T) int
+	logArgs    func(item T) []any
+	send       func(ctx context.Context, item T) error
+	markSent   func(ctx context.Context, item T, at time.Time) error
+	markFailed func(ctx context.Context, item T, reason string, next time.Time) error
+}
+
+// deliverClaimed sends the items claimed at claimedAt one by one and returns how many have been delivered.
+// Sending stops once the send window runs out, the items left are claimed again after their lease expires.
+// Outcomes are recorded with ctx, so a delivery made just before the window ends is still recorded.
+// Only failures to record an outcome are returned, a failed delivery is scheduled for a retry instead.
+func deliverClaimed[T any](ctx context.Context, log logger.Logger, claimedAt time.Time, items []T,
+	policy retryPolicy, d leasedDelivery[T]) (int, error) {
+	sendCtx, cancel := context.WithDeadline(ctx, claimedAt.Add(deliverySendWindow))
+	defer cancel()
+
+	var delivered int
+	for _, item := range items {
+		if sendCtx.Err() != nil {
+			log.Warn("Send window has run out, the rest is left for the next run", "name", d.name)
+			break
+		}
+
+		itemLog := log.With(d.logArgs(item)...)
+		sendErr := d.send(sendCtx, item)
+		if sendErr == nil {
+			if err := d.markSent(ctx, item, time.Now()); err != nil {
+				itemLog.Error("Failed to mark "+d.name+" as delivered", "error", err)
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		// Attempts do not include the current one yet
+		attempt := d.attempts(item) + 1
+		next := policy.nextAttempt(attempt, time.Now())
+		itemLog.Error("Failed to deliver "+d.name, "attempt", attempt, "next_attempt", next, "error", sendErr)
+
+		if err := d.markFailed(ctx, item, sendErr.Error(), next); err != nil {
+			itemLog.Error("Failed to record "+d.name+" delivery failure", "error", err)
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
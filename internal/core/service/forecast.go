@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// GetForecast projects upcoming charges of the user over the rest of the current month
+// and the following months, months in total. Zero months falls back to the default horizon.
+func (s *SubsService) GetForecast(ctx context.Context, userID string, months int) (domain.Forecast, error) {
+	const op = "SubsService.GetForecast"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_ID", userID),
+		slog.Int("months", months),
+	)
+
+	if months == 0 {
+		months = domain.DefaultForecastMonths
+	}
+	if months < 1 || months > domain.MaxForecastMonths {
+		return domain.Forecast{}, domain.ErrInvalidForecast
+	}
+
+	from := time.Now().UTC()
+	subsList, err := s.repo.ListBillable(ctx, userID, from, forecastUntil(from, months))
+	if err != nil {
+		log.Error("Failed to get billable subscriptions", "error", err)
+		return domain.Forecast{}, err
+	}
+
+	forecast := ProjectForecast(subsList, userID, from, months)
+
+	log.Info("Forecast has been projected", slog.Int("subscriptions", len(subsList)), slog.Int("total", forecast.Total))
+	return forecast, nil
+}
+
+// ProjectForecast projects charges of the subscriptions from the given moment until the end of
+// the calendar month months-1 months later. Every subscription is charged on its billing dates,
+// trial prices on the start date, and renews until its end date or, if it renews automatically
+// or has no end date, indefinitely. Charges are summed up per service within every month.
+// If userID is set, only the user's share of shared subscriptions is counted.
+func ProjectForecast(subsList []domain.Subscription, userID string, from time.Time, months int) domain.Forecast {
+	from = from.UTC()
+	monthStart := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	forecast := domain.Forecast{
+		UserID: userID,
+		From:   from,
+		Until:  forecastUntil(from, months),
+		Months: make([]domain.ForecastMonth, 0, months),
+	}
+	for i := range months {
+		forecast.Months = append(forecast.Months, domain.ForecastMonth{
+			Month: monthStart.AddDate(0, i, 0).Format(monthLayout),
+			Items: []domain.ForecastItem{},
+		})
+	}
+
+	// Index of the item of every service in the items of every month
+	itemOf := make([]map[string]int, months)
+	for i := range itemOf {
+		itemOf[i] = make(map[string]int)
+	}
+
+	for _, subs := range subsList {
+		for _, c := range upcomingCharges(subs, forecast.From, forecast.Until, userID) {
+			at := c.at.UTC()
+			m := (at.Year()-monthStart.Year())*12 + int(at.Month()-monthStart.Month())
+			month := &forecast.Months[m]
+
+			i, ok := itemOf[m][subs.ServiceName]
+			if !ok {
+				i = len(month.Items)
+				itemOf[m][subs.ServiceName] = i
+				month.Items = append(month.Items, domain.ForecastItem{ServiceName: subs.ServiceName})
+			}
+			item := &month.Items[i]
+			item.Charges++
+			item.Amount += c.amount
+			month.Total += c.amount
+		}
+	}
+
+	for i := range forecast.Months {
+		forecast.Total += forecast.Months[i].Total
+		forecast.Months[i].Cumulative = forecast.Total
+	}
+	return forecast
+}
+
+// forecastUntil returns the end of the forecast horizon, the first day of the month
+// following the last forecast month.
+func forecastUntil(from time.Time, months int) time.Time {
+	from = from.UTC()
+	return time.Date(from.Year(), from.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+}
+
+type charge struct {
+	at     time.Time
+	amount int
+}
+
+// upcomingCharges returns charges of the subscription falling into [from, until).
+// A paid trial is charged on the start date and billing cycles on their billing dates
+// before the end date, each with the price in effect and the discount phase of the cycle.
+// The end date of an auto-renewing subscription only ends its current term, so it is billed past it.
+func upcomingCharges(subs domain.Subscription, from, until time.Time, viewer string) []charge {
+	period := subs.BillingPeriod
+	if period.Validate() != nil {
+		period = domain.DefaultBillingPeriod
+	}
+
+	share := func(price int) int {
+		if viewer == "" {
+			return price
+		}
+		return int(math.Round(subs.ShareOf(viewer, float64(price))))
+	}
+
+	var charges []charge
+	if subs.TrialEndDate != nil && subs.TrialPrice > 0 && !subs.StartDate.Before(from) && subs.StartDate.Before(until) {
+		charges = append(charges, charge{at: subs.StartDate, amount: share(subs.TrialPrice)})
+	}
+
+	anchor := subs.BillingStart()
+	for n := firstCycle(period, anchor, from); ; n++ {
+		billedAt := period.Shift(anchor, n)
+		if !billedAt.Before(until) || (!subs.AutoRenew && !subs.EndDate.IsZero() && !billedAt.Before(subs.EndDate)) {
+			break
+		}
+		if billedAt.Before(from) {
+			continue
+		}
+		charges = append(charges, charge{at: billedAt, amount: share(subs.CyclePrice(n, billedAt))})
+	}
+	return charges
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+)
+
+// ImportSubscriptions creates subscriptions from the parsed rows of an import file and reports the outcome of every row.
+// Rows go through the same checks as CreateSubscription, a user may not import the same service twice
+// unless the uniqueness policy allows it. The checks only read, so a dry run which stops after them
+// and a rejected atomic import write nothing, services unknown to the catalog included.
+// An atomic import stores the rows in a single transaction and stores nothing if any row fails,
+// otherwise every valid row is stored on its own.
+func (s *SubsService) ImportSubscriptions(ctx context.Context, rows []domain.ImportRow, opts domain.ImportOptions) (domain.ImportReport, error) {
+	const op = "SubsService.ImportSubscriptions"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("rows", len(rows)),
+		slog.Bool("dry_run", opts.DryRun),
+		slog.Bool("atomic", opts.Atomic),
+	)
+
+	report := domain.ImportReport{
+		DryRun: opts.DryRun,
+		Atomic: opts.Atomic,
+		Total:  len(rows),
+		Rows:   make([]domain.ImportResult, len(rows)),
+	}
+
+	// Valid rows are kept by their index in the report and the prepared subscription
+	var (
+		valid   []int
+		subsOf  = make([]domain.Subscription, len(rows))
+		planned = make(map[[2]string]bool)
+	)
+	for i, row := range rows {
+		subs := row.Subscription
+		err := row.Err
+		if err == nil {
+			err = s.prepareSubs(ctx, log.With(slog.Int("line", row.Line)), &subs)
+		}
+
+		// Rows of the same file are not stored yet, so the uniqueness between them is checked here
+		key := [2]string{subs.UserID, importServiceKey(subs)}
+		if err == nil && s.uniqueness != domain.UniqueNone && planned[key] {
+			err = domain.ErrSubNotUnique
+		}
+
+		report.Rows[i] = domain.ImportResult{
+			Line:        row.Line,
+			Status:      domain.ImportValid,
+			ServiceName: subs.ServiceName,
+			UserID:      subs.UserID,
+		}
+		if err != nil {
+			report.MarkFailed(i, err)
+			continue
+		}
+		planned[key] = true
+		valid = append(valid, i)
+		subsOf[i] = subs
+	}
+
+	if opts.DryRun || (opts.Atomic && report.Failed > 0) || len(valid) == 0 {
+		log.Info("Subscriptions have not been imported", slog.Int("valid", len(valid)), slog.Int("failed", report.Failed))
+		return report, nil
+	}
+
+	if opts.Atomic {
+		subsList := make([]domain.Subscription, 0, len(valid))
+		for _, i := range valid {
+			subsList = append(subsList, subsOf[i])
+		}
+
+		ids, err := s.repo.CreateBatch(ctx, subsList)
+		if err != nil {
+			log.Error("Failed to import subscriptions", "error", err)
+			return domain.ImportReport{}, err
+		}
+		for n, i := range valid {
+			report.MarkCreated(i, ids[n])
+		}
+	} else {
+		for _, i := range valid {
+			id, err := s.repo.Create(ctx, subsOf[i])
+			if err != nil {
+				log.Error("Failed to import subscription", slog.Int("line", report.Rows[i].Line), "error", err)
+				report.MarkFailed(i, err)
+				continue
+			}
+			report.MarkCreated(i, id)
+		}
+	}
+
+	for _, i := range valid {
+		if report.Rows[i].Status == domain.ImportCreated {
+			s.evaluateBudgets(ctx, log, subsOf[i])
+		}
+	}
+
+	log.Info("Subscriptions have been imported", slog.Int("created", report.Created), slog.Int("failed", report.Failed))
+	return report, nil
+}
+
+// importServiceKey identifies the service of a prepared row, so aliases of one catalog service match.
+// Names unknown to the catalog are compared by their alias key, as they are added to the catalog by it.
+func importServiceKey(subs domain.Subscription) string {
+	if len(subs.ServiceID) != 0 {
+		return "id:" + subs.ServiceID
+	}
+	return "alias:" + domain.NormalizeAlias(subs.ServiceName)
+}
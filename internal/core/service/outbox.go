@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+	"time"
+)
+
+const (
+	// outboxLease is how long a claimed event is hidden from other relays while it is being published.
+	outboxLease = time.Minute
+	// outboxBatch limits how many events are published in a single run.
+	outboxBatch = 100
+)
+
+// OutboxRelay publishes events written to the outbox by subscription changes.
+type OutboxRelay struct {
+	repo       domain.OutboxRepo
+	publisher  domain.EventPublisher
+	retryDelay time.Duration
+	log        logger.Logger
+}
+
+// NewOutboxRelay creates the relay, events failed to publish are retried after retryDelay.
+func NewOutboxRelay(repo domain.OutboxRepo, publisher domain.EventPublisher, retryDelay time.Duration, log logger.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		repo:       repo,
+		publisher:  publisher,
+		retryDelay: retryDelay,
+		log:        log,
+	}
+}
+
+// RelayEvents publishes due outbox events oldest first and returns how many have been published.
+// An event is marked published only after the publisher accepted it, so it is published at least once.
+// After a failed event the later events of the same subscription are left for the following runs,
+// so consumers see the changes of a subscription in order.
+func (s *OutboxRelay) RelayEvents(ctx context.Context) (int, error) {
+	const op = "OutboxRelay.RelayEvents"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	events, err := s.repo.ClaimEvents(ctx, time.Now(), outboxLease, outboxBatch)
+	if err != nil {
+		log.Error("Failed to claim outbox events", "error", err)
+		return 0, err
+	}
+
+	var published int
+	// failed keeps subscriptions with an event that failed in this run
+	failed := make(map[string]bool)
+	for _, event := range events {
+		subsID := event.Event.Subscription.ID
+		log := log.With(slog.Int64("outbox_id", event.ID), slog.String("event_type", string(event.Event.Type)),
+			slog.String("subscription_id", subsID))
+
+		if failed[subsID] {
+			log.Debug("Event is held back by an earlier failed event of the subscription")
+			continue
+		}
+
+		if err := s.publisher.Publish(ctx, event.Event); err != nil {
+			log.Error("Failed to publish event", "attempt", event.Attempts+1, "error", err)
+			failed[subsID] = true
+			if err := s.repo.MarkPublishFailed(ctx, event.ID, err.Error(), time.Now().Add(s.retryDelay)); err != nil {
+				log.Error("Failed to record event publish failure", "error", err)
+				return published, err
+			}
+			continue
+		}
+
+		if err := s.repo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+			log.Error("Failed to mark event as published", "error", err)
+			return published, err
+		}
+		published++
+	}
+
+	log.Info("Outbox events have been relayed", slog.Int("due", len(events)), slog.Int("published", published))
+	return published, nil
+}
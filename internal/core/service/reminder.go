@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+	"time"
+)
+
+// ReminderPolicy controls when renewal reminders are sent and how failed deliveries are retried.
+type ReminderPolicy struct {
+	// DaysBefore is how many days before the end date the user is reminded.
+	DaysBefore int
+	// MaxAttempts is the number of delivery attempts before the reminder is given up.
+	MaxAttempts int
+	// RetryDelay is the delay before the first retry, it doubles with every further attempt up to a day.
+	RetryDelay time.Duration
+}
+
+type ReminderService struct {
+	repo     domain.ReminderRepo
+	notifier domain.Notifier
+	policy   ReminderPolicy
+	log      logger.Logger
+}
+
+func NewReminderService(repo domain.ReminderRepo, notifier domain.Notifier, policy ReminderPolicy, log logger.Logger) *ReminderService {
+	return &ReminderService{
+		repo:     repo,
+		notifier: notifier,
+		policy:   policy,
+		log:      log,
+	}
+}
+
+// SendReminders schedules reminders of subscriptions ending within the reminder window
+// and sends the due ones, returning how many have been delivered.
+// Failed deliveries are retried with exponential backoff by the following runs.
+func (s *ReminderService) SendReminders(ctx context.Context) (int, error) {
+	const op = "ReminderService.SendReminders"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int("days_before", s.policy.DaysBefore),
+	)
+
+	now := time.Now()
+	scheduled, err := s.repo.EnqueueReminders(ctx, now, now.AddDate(0, 0, s.policy.DaysBefore))
+	if err != nil {
+		log.Error("Failed to schedule reminders", "error", err)
+		return 0, err
+	}
+
+	reminders, err := s.repo.ClaimReminders(ctx, now, deliveryLease, deliveryBatch)
+	if err != nil {
+		log.Error("Failed to claim due reminders", "error", err)
+		return 0, err
+	}
+
+	sent, err := deliverClaimed(ctx, log, now, reminders, s.retryPolicy(), leasedDelivery[domain.Reminder]{
+		name:     "reminder",
+		attempts: func(r domain.Reminder) int { return r.Attempts },
+		logArgs: func(r domain.Reminder) []any {
+			return []any{slog.Int64("reminder_id", r.ID), slog.String("subscription_id", r.SubsID)}
+		},
+		send: func(ctx context.Context, r domain.Reminder) error {
+			return s.notifier.Notify(ctx, r.Notification())
+		},
+		markSent: func(ctx context.Context, r domain.Reminder, at time.Time) error {
+			return s.repo.MarkReminderSent(ctx, r.ID, at)
+		},
+		markFailed: func(ctx context.Context, r domain.Reminder, reason string, next time.Time) error {
+			return s.repo.MarkReminderFailed(ctx, r.ID, reason, next)
+		},
+	})
+	if err != nil {
+		return sent, err
+	}
+
+	log.Info("Reminders have been processed", slog.Int64("scheduled", scheduled),
+		slog.Int("due", len(reminders)), slog.Int("sent", sent))
+	return sent, nil
+}
+
+func (s *ReminderService) retryPolicy() retryPolicy {
+	return retryPolicy{maxAttempts: s.policy.MaxAttempts, retryDelay: s.policy.RetryDelay}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+)
+
+// GetSpendSeries returns the spend of the filtered subscriptions bucketed by day, week, month or year.
+// Buckets are calculated by the repository, the series total is summed up from the buckets.
+func (s *SubsService) GetSpendSeries(ctx context.Context, filter domain.SpendSeriesFilter) (domain.SpendSeries, error) {
+	const op = "SubsService.GetSpendSeries"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_id", filter.UserID),
+		slog.String("service_name", filter.ServiceName),
+		slog.String("bucket", string(filter.Bucket)),
+		slog.String("filter_start_date", filter.Start.String()),
+		slog.String("filter_end_date", filter.End.String()),
+		slog.Any("tags", filter.Tags),
+	)
+
+	if filter.Bucket == "" {
+		filter.Bucket = domain.PeriodMonth
+	}
+	if err := filter.Validate(); err != nil {
+		log.Error("Invalid spend series filter", "error", err)
+		return domain.SpendSeries{}, err
+	}
+	filter.Tags = domain.NormalizeTags(filter.Tags)
+
+	points, err := s.repo.SpendSeries(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get spend series", "error", err)
+		return domain.SpendSeries{}, err
+	}
+
+	series := domain.SpendSeries{
+		Bucket: filter.Bucket,
+		Start:  filter.Start,
+		End:    filter.End,
+		Points: points,
+	}
+	for _, point := range points {
+		series.TotalPrice += point.TotalPrice
+	}
+
+	log.Info("Spend series has been retrieved", slog.Int("buckets", len(points)))
+	return series, nil
+}
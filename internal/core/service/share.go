@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// SetSubscriptionParticipants replaces participants sharing the cost of the subscription with the given ID.
+// New participants are invited and pay nothing until they accept.
+func (s *SubsService) SetSubscriptionParticipants(ctx context.Context, id string, participants []domain.Participant) (domain.Subscription, error) {
+	const op = "SubsService.SetSubscriptionParticipants"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+		slog.Int("participants", len(participants)),
+	)
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	if err := domain.ValidateParticipants(subs.UserID, participants); err != nil {
+		log.Error("Invalid participants", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	if err := s.repo.SetParticipants(ctx, id, participants); err != nil {
+		log.Error("Failed to set subscription participants", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	subs, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get shared subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription participants have been set")
+	s.evaluateBudgets(ctx, log, subs)
+	return withBilling(subs, time.Now()), nil
+}
+
+// RespondToShare records the answer of the invited user. Invited participants accept or decline,
+// accepted ones stop sharing the cost by declining.
+func (s *SubsService) RespondToShare(ctx context.Context, id, userID string, to domain.ShareStatus) (domain.Subscription, error) {
+	const op = "SubsService.RespondToShare"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+		slog.String("user_ID", userID),
+		slog.String("to", string(to)),
+	)
+
+	if to != domain.ShareAccepted && to != domain.ShareDeclined {
+		return domain.Subscription{}, domain.ErrInvalidShareStatus
+	}
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	participant, ok := subs.Participant(userID)
+	if !ok {
+		log.Error("User is not invited to share the subscription")
+		return domain.Subscription{}, domain.ErrShareNotFound
+	}
+	if !participant.Status.CanRespond(to) {
+		log.Error("Share has already been answered", slog.String("status", string(participant.Status)))
+		return domain.Subscription{}, domain.ErrShareAnswered
+	}
+
+	err = s.repo.SetShareStatus(ctx, id, userID, participant.Status, to, time.Now())
+	if err != nil {
+		log.Error("Failed to set share status", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	subs, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get shared subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Share has been answered")
+	// The answer moves spend between the owner and the participant
+	s.evaluateBudgets(ctx, log, subs)
+	participantSubs := subs
+	participantSubs.UserID = userID
+	s.evaluateBudgets(ctx, log, participantSubs)
+
+	return withShare(withBilling(subs, time.Now()), userID), nil
+}
+
+// GetSharedSubscriptions returns subscriptions the user has been invited to share
+// with the user's share of the current price.
+func (s *SubsService) GetSharedSubscriptions(ctx context.Context, userID string) ([]domain.Subscription, error) {
+	const op = "SubsService.GetSharedSubscriptions"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_ID", userID),
+	)
+
+	subsList, err := s.repo.SharedWith(ctx, userID)
+	if err != nil {
+		log.Error("Failed to get shared subscriptions", "error", err)
+		return nil, err
+	}
+
+	if len(subsList) == 0 {
+		log.Error("No subscriptions are shared with the user")
+		return nil, domain.ErrSubsNotFound
+	}
+
+	now := time.Now()
+	for i := range subsList {
+		subsList[i] = withShare(withBilling(subsList[i], now), userID)
+	}
+
+	log.Info("Shared subscriptions have been retrieved", slog.Int("count", len(subsList)))
+	return subsList, nil
+}
+
+// withShare sets the part of the effective price paid by the user if the subscription is shared.
+func withShare(subs domain.Subscription, userID string) domain.Subscription {
+	if userID == "" || len(subs.Participants) == 0 || subs.EffectivePrice == nil {
+		return subs
+	}
+	share := int(math.Round(subs.ShareOf(userID, float64(*subs.EffectivePrice))))
+	subs.UserShare = &share
+	return subs
+}
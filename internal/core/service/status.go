@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// ChangeSubscriptionStatus moves the subscription to a new status.
+// It returns a *domain.TransitionError if the lifecycle does not allow the transition.
+func (s *SubsService) ChangeSubscriptionStatus(ctx context.Context, serviceName, userID string, to domain.Status) (domain.Subscription, error) {
+	if !to.IsValid() {
+		return domain.Subscription{}, domain.ErrInvalidStatus
+	}
+	return s.changeStatus(ctx, "SubsService.ChangeSubscriptionStatus", serviceName, userID, domain.StatusChange{To: to})
+}
+
+// PauseSubscription pauses an active subscription, paused time is excluded from the cost.
+func (s *SubsService) PauseSubscription(ctx context.Context, serviceName, userID string) (domain.Subscription, error) {
+	return s.changeStatus(ctx, "SubsService.PauseSubscription", serviceName, userID, domain.StatusChange{To: domain.StatusPaused})
+}
+
+// ResumeSubscription resumes a paused subscription.
+// If extendEndDate is set, the end date is pushed back by the length of the pause.
+func (s *SubsService) ResumeSubscription(ctx context.Context, serviceName, userID string, extendEndDate bool) (domain.Subscription, error) {
+	subs, err := s.repo.Get(ctx, serviceName, userID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	// Resume is only meaningful for paused subscriptions, even though active can be reached from other statuses
+	if subs.Status != domain.StatusPaused {
+		return domain.Subscription{}, &domain.TransitionError{From: subs.Status, To: domain.StatusActive}
+	}
+	return s.changeStatus(ctx, "SubsService.ResumeSubscription", serviceName, userID,
+		domain.StatusChange{To: domain.StatusActive, ExtendEndDate: extendEndDate})
+}
+
+// CancelSubscription cancels the subscription and records the reason and the comment of the cancellation.
+func (s *SubsService) CancelSubscription(ctx context.Context, serviceName, userID string, cancellation domain.Cancellation) (domain.Subscription, error) {
+	if err := cancellation.Validate(); err != nil {
+		return domain.Subscription{}, err
+	}
+	return s.changeStatus(ctx, "SubsService.CancelSubscription", serviceName, userID,
+		domain.StatusChange{To: domain.StatusCancelled, Cancellation: &cancellation})
+}
+
+// changeStatus applies the change to the subscription, the subscription ID, the previous status
+// and the time of the change are filled in from the current state.
+func (s *SubsService) changeStatus(ctx context.Context, op, serviceName, userID string, change domain.StatusChange) (domain.Subscription, error) {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("service_name", serviceName),
+		slog.String("user_ID", userID),
+		slog.String("status", string(change.To)),
+	)
+
+	subs, err := s.repo.Get(ctx, serviceName, userID)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	if !subs.Status.CanTransition(change.To) {
+		log.Error("Status transition is not allowed", "from", subs.Status)
+		return domain.Subscription{}, &domain.TransitionError{From: subs.Status, To: change.To}
+	}
+
+	change.SubsID, change.From, change.ChangedAt = subs.ID, subs.Status, time.Now()
+	if err := s.repo.ChangeStatus(ctx, change); err != nil {
+		log.Error("Failed to change subscription status", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	// Re-read the subscription to pick up pause intervals and the extended end date
+	subs, err = s.repo.Get(ctx, serviceName, userID)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription status has been changed", "from", change.From)
+	return withBilling(subs, change.ChangedAt), nil
+}
+
+// GetStatusHistory retrieves all status transitions of the subscription.
+func (s *SubsService) GetStatusHistory(ctx context.Context, serviceName, userID string) ([]domain.StatusChange, error) {
+	const op = "SubsService.GetStatusHistory"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("service_name", serviceName),
+		slog.String("user_ID", userID),
+	)
+
+	subs, err := s.repo.Get(ctx, serviceName, userID)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return nil, err
+	}
+
+	history, err := s.repo.StatusHistory(ctx, subs.ID)
+	if err != nil {
+		log.Error("Failed to get status history", "error", err)
+		return nil, err
+	}
+
+	log.Info("Subscription status history has been retrieved")
+	return history, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// GetSubscriptionByID retrieves a subscription by its ID.
+func (s *SubsService) GetSubscriptionByID(ctx context.Context, id string) (domain.Subscription, error) {
+	const op = "SubsService.GetSubscriptionByID"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription has been retrieved")
+	return withBilling(subs, time.Now()), nil
+}
+
+// UpdateSubscriptionByID overwrites the terms of the subscription with subs.ID.
+func (s *SubsService) UpdateSubscriptionByID(ctx context.Context, subs domain.Subscription) error {
+	const op = "SubsService.UpdateSubscriptionByID"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", subs.ID),
+		slog.String("start_date", subs.StartDate.String()),
+		slog.Int("price", subs.Price),
+	)
+
+	// Unlike creation, an update has no catalog default to fall back to
+	if subs.Price <= 0 {
+		return domain.ErrPriceField
+	}
+
+	if err := subs.ValidateTrial(); err != nil {
+		log.Error("Invalid trial", "error", err)
+		return err
+	}
+
+	if err := setBillingPeriod(&subs); err != nil {
+		log.Error("Invalid billing period", "error", err)
+		return err
+	}
+	log = log.With("exp_date", subs.EndDate, "billing_period", subs.BillingPeriod)
+
+	updated, err := s.repo.UpdateByID(ctx, subs)
+	if err != nil {
+		log.Error("Failed to update subscription", "error", err)
+		return err
+	}
+
+	log.Info("Subscription has been updated")
+	s.evaluateBudgets(ctx, log, updated)
+	return nil
+}
+
+// PatchSubscription changes only the provided fields of the subscription and returns the result.
+func (s *SubsService) PatchSubscription(ctx context.Context, id string, patch domain.SubsPatch) (domain.Subscription, error) {
+	const op = "SubsService.PatchSubscription"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	subs = patch.Apply(subs)
+	if subs.Price <= 0 {
+		return domain.Subscription{}, domain.ErrPriceField
+	}
+
+	if err := setBillingPeriod(&subs); err != nil {
+		log.Error("Invalid billing period", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	if subs.StartDate.After(subs.EndDate) {
+		return domain.Subscription{}, domain.ErrInvalidDate
+	}
+
+	if err := subs.ValidateTrial(); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	if _, err := s.repo.UpdateByID(ctx, subs); err != nil {
+		log.Error("Failed to update subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	// Re-read the subscription to pick up its pauses, prices and tags
+	subs, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get patched subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription has been patched")
+	s.evaluateBudgets(ctx, log, subs)
+	return withBilling(subs, time.Now()), nil
+}
+
+// DeleteSubscriptionByID deletes a subscription by its ID.
+func (s *SubsService) DeleteSubscriptionByID(ctx context.Context, id string) error {
+	const op = "SubsService.DeleteSubscriptionByID"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	if err := s.repo.DeleteByID(ctx, id); err != nil {
+		log.Error("Failed to delete subscription", "error", err)
+		return err
+	}
+
+	log.Info("Subscription has been deleted")
+	return nil
+}
+
+// SetSubscriptionTags replaces tags of the subscription and returns the result.
+func (s *SubsService) SetSubscriptionTags(ctx context.Context, id string, tags []string) (domain.Subscription, error) {
+	const op = "SubsService.SetSubscriptionTags"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+		slog.Any("tags", tags),
+	)
+
+	if err := s.repo.SetTags(ctx, id, domain.NormalizeTags(tags)); err != nil {
+		log.Error("Failed to set subscription tags", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get tagged subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription tags have been set")
+	return withBilling(subs, time.Now()), nil
+}
+
+// SetSubscriptionDiscounts replaces discount phases of the subscription and returns the result.
+func (s *SubsService) SetSubscriptionDiscounts(ctx context.Context, id string, discounts []domain.Discount) (domain.Subscription, error) {
+	const op = "SubsService.SetSubscriptionDiscounts"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+		slog.Any("discounts", discounts),
+	)
+
+	if err := domain.ValidateDiscounts(discounts); err != nil {
+		log.Error("Invalid discounts", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	if err := s.repo.SetDiscounts(ctx, id, discounts); err != nil {
+		log.Error("Failed to set subscription discounts", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get discounted subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription discounts have been set")
+	s.evaluateBudgets(ctx, log, subs)
+	return withBilling(subs, time.Now()), nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"submanager/internal/core/domain"
 	"submanager/internal/pkg/logger"
@@ -9,20 +10,54 @@ import (
 )
 
 type SubsService struct {
-	repo domain.SubsRepo
-	log  logger.Logger
+	repo       domain.SubsRepo
+	log        logger.Logger
+	uniqueness domain.UniquenessPolicy
+	catalog    domain.CatalogRepo
+	budgets    domain.BudgetEvaluator
 }
 
-func NewSubsService(repo domain.SubsRepo, log logger.Logger) *SubsService {
-	return &SubsService{
-		repo: repo,
-		log:  log,
+// Option configures optional behaviour of SubsService.
+type Option func(s *SubsService)
+
+// WithUniquenessPolicy sets how many subscriptions of the same service a user may hold,
+// one per service by default.
+func WithUniquenessPolicy(policy domain.UniquenessPolicy) Option {
+	return func(s *SubsService) {
+		s.uniqueness = policy
+	}
+}
+
+// WithCatalog makes new subscriptions resolve their service names against the catalog.
+func WithCatalog(catalog domain.CatalogRepo) Option {
+	return func(s *SubsService) {
+		s.catalog = catalog
+	}
+}
+
+// WithBudgets makes creations and updates of subscriptions evaluate budgets of their users.
+func WithBudgets(budgets domain.BudgetEvaluator) Option {
+	return func(s *SubsService) {
+		s.budgets = budgets
+	}
+}
+
+func NewSubsService(repo domain.SubsRepo, log logger.Logger, opts ...Option) *SubsService {
+	s := &SubsService{
+		repo:       repo,
+		log:        log,
+		uniqueness: domain.UniquePerService,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// CreateSubscription creates a new subscription in the database.
-// It checks if the subscription is unique and sets the expiration date if not provided.
-func (s *SubsService) CreateSubscription(ctx context.Context, subs domain.Subscription) error {
+// CreateSubscription creates a new subscription in the database and returns its ID.
+// It links the subscription to the catalog service, checks the uniqueness policy
+// and sets the expiration date if not provided. Subscriptions with a running trial start as trialing.
+func (s *SubsService) CreateSubscription(ctx context.Context, subs domain.Subscription) (string, error) {
 	const op = "SubsService.CreateSubscription"
 	log := s.log.With(
 		slog.String("op", op),
@@ -32,30 +67,128 @@ func (s *SubsService) CreateSubscription(ctx context.Context, subs domain.Subscr
 		slog.Int("price", subs.Price),
 	)
 
-	if subs.EndDate.IsZero() {
-		// Set exp_date as one month from the start of subscription
-		subs.EndDate = subs.StartDate.AddDate(0, 1, 0)
-		log = log.With("exp_date", subs.EndDate)
+	if err := s.prepareSubs(ctx, log, &subs); err != nil {
+		return "", err
 	}
+	log = log.With("exp_date", subs.EndDate, "billing_period", subs.BillingPeriod)
 
-	// Check is subscription unique
-	unique, err := s.repo.IsUnique(ctx, subs.ServiceName, subs.UserID)
+	// Create a new subscription in the database
+	id, err := s.repo.Create(ctx, subs)
 	if err != nil {
+		log.Error("Failed to create new subs", "error", err)
+		return "", err
+	}
+
+	log.Info("Subcription has been created", slog.String("ID", id))
+	s.evaluateBudgets(ctx, log, subs)
+	return id, nil
+}
+
+// prepareSubs resolves the catalog service of a new subscription, validates it and checks the uniqueness policy.
+// It only reads, a service unknown to the catalog is added when the subscription is stored.
+func (s *SubsService) prepareSubs(ctx context.Context, log logger.Logger, subs *domain.Subscription) error {
+	if err := s.resolveService(ctx, subs); err != nil {
+		log.Error("Failed to resolve catalog service", "error", err)
+		return err
+	}
+
+	if err := validateNewSubs(log, subs); err != nil {
+		return err
+	}
+
+	if err := s.checkUnique(ctx, subs.ServiceName, subs.UserID); err != nil {
 		log.Error("Failed to check subscription uniqueness", "error", err)
 		return err
 	}
+	return nil
+}
 
-	if !unique {
-		return domain.ErrSubNotUnique
+// validateNewSubs checks the terms of a new subscription and fills in what the request may omit:
+// the billing period with the expiration date and the initial status.
+func validateNewSubs(log logger.Logger, subs *domain.Subscription) error {
+	if subs.Price <= 0 {
+		return domain.ErrPriceField
 	}
 
-	// Create a new subscription in the database
-	if err := s.repo.Create(ctx, subs); err != nil {
-		log.Error("Failed to create new subs", "error", err)
+	if err := subs.ValidateTrial(); err != nil {
+		log.Error("Invalid trial", "error", err)
+		return err
+	}
+
+	if err := domain.ValidateDiscounts(subs.Discounts); err != nil {
+		log.Error("Invalid discounts", "error", err)
 		return err
 	}
 
-	log.Info("Subcription has been created")
+	if err := setBillingPeriod(subs); err != nil {
+		log.Error("Invalid billing period", "error", err)
+		return err
+	}
+
+	// New subscriptions start their lifecycle as active, or as trialing until the trial ends
+	subs.Status = domain.StatusActive
+	if subs.TrialEndDate != nil && subs.TrialEndDate.After(time.Now()) {
+		subs.Status = domain.StatusTrialing
+	}
+	subs.Tags = domain.NormalizeTags(subs.Tags)
+	return nil
+}
+
+// evaluateBudgets checks budgets of the subscription owner for the month the change starts
+// to affect spend in. Failures are only logged as the subscription has already been changed.
+func (s *SubsService) evaluateBudgets(ctx context.Context, log logger.Logger, subs domain.Subscription) {
+	if s.budgets == nil {
+		return
+	}
+
+	at := time.Now()
+	if subs.StartDate.After(at) {
+		at = subs.StartDate
+	}
+
+	if err := s.budgets.EvaluateBudgets(ctx, subs.UserID, at); err != nil {
+		log.Error("Failed to evaluate budgets", "error", err)
+	}
+}
+
+// resolveService links the subscription to its catalog service and replaces the name with the canonical one.
+// A missing price is taken from the service default price. The catalog is only read here,
+// an unknown name is marked to be added as a pending service once the subscription is stored.
+func (s *SubsService) resolveService(ctx context.Context, subs *domain.Subscription) error {
+	if s.catalog == nil {
+		return nil
+	}
+
+	service, err := s.catalog.FindService(ctx, subs.ServiceName)
+	if errors.Is(err, domain.ErrServiceNotFound) {
+		subs.PendingService = true
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	subs.ServiceID, subs.ServiceName = service.ID, service.Name
+	if subs.Price == 0 {
+		subs.Price = service.DefaultPrice
+	}
+	return nil
+}
+
+// checkUnique returns ErrSubNotUnique if the uniqueness policy forbids another subscription of the service.
+func (s *SubsService) checkUnique(ctx context.Context, serviceName, userID string) error {
+	if s.uniqueness == domain.UniqueNone {
+		return nil
+	}
+
+	unique, err := s.repo.IsUnique(ctx, serviceName, userID)
+	if err != nil {
+		return err
+	}
+
+	if !unique {
+		return domain.ErrSubNotUnique
+	}
 	return nil
 }
 
@@ -75,30 +208,66 @@ func (s *SubsService) GetSubscription(ctx context.Context, serviceName, userID s
 	}
 
 	log.Info("Subscription has been retrieved")
-	return subs, nil
+	return withBilling(subs, time.Now()), nil
 }
 
-// GetSubscriptionList retrieves all subscriptions for a given user ID.
-func (s *SubsService) GetSubscriptionList(ctx context.Context, userID string) ([]domain.Subscription, error) {
-	const op = "SubsService.GetSubscriptionList"
+// GetPriceHistory retrieves the price timeline of the subscription, oldest price first.
+func (s *SubsService) GetPriceHistory(ctx context.Context, serviceName, userID string) ([]domain.PricePoint, error) {
+	const op = "SubsService.GetPriceHistory"
 	log := s.log.With(
 		slog.String("op", op),
+		slog.String("service_name", serviceName),
 		slog.String("user_ID", userID),
 	)
 
-	subs, err := s.repo.List(ctx, userID)
+	subs, err := s.repo.Get(ctx, serviceName, userID)
 	if err != nil {
-		log.Error("Failed to get subscription list", "error", err)
+		log.Error("Failed to get subscription", "error", err)
 		return nil, err
 	}
 
-	if len(subs) == 0 {
+	prices := subs.PriceHistory
+	if len(prices) == 0 {
+		// Subscription has never been repriced since the history is kept
+		prices = []domain.PricePoint{{Price: subs.Price, EffectiveFrom: subs.StartDate}}
+	}
+
+	log.Info("Subscription price history has been retrieved")
+	return prices, nil
+}
+
+// GetSubscriptionList retrieves a page of subscriptions of the user, optionally only those with any of the filter tags.
+func (s *SubsService) GetSubscriptionList(ctx context.Context, filter domain.ListFilter) (domain.SubsList, error) {
+	const op = "SubsService.GetSubscriptionList"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("user_ID", filter.UserID),
+		slog.Any("tags", filter.Tags),
+		slog.Int("page_size", filter.PageSize),
+	)
+
+	filter.Tags = domain.NormalizeTags(filter.Tags)
+	subsPage, err := s.repo.List(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get subscription list", "error", err)
+		return domain.SubsList{}, err
+	}
+
+	if subsPage.TotalItems == 0 {
 		log.Error("Subscription list is empty")
-		return nil, domain.ErrSubsNotFound
+		return domain.SubsList{}, domain.ErrSubsNotFound
 	}
 
+	now := time.Now()
+	for i := range subsPage.Subscriptions {
+		subsPage.Subscriptions[i] = withShare(withBilling(subsPage.Subscriptions[i], now), filter.UserID)
+	}
+
+	list := domain.SubsList{Subscriptions: subsPage.Subscriptions}
+	list.NextCursor, list.PrevCursor = pageCursors(filter.Pagination, subsPage)
+
 	log.Info("Subscription list has been retrieved")
-	return subs, nil
+	return list, nil
 }
 
 // UpdateSubscription updates an existing subscription in the database.
@@ -112,18 +281,30 @@ func (s *SubsService) UpdateSubscription(ctx context.Context, subs domain.Subscr
 		slog.Int("price", subs.Price),
 	)
 
-	if subs.EndDate.IsZero() {
-		// Set exp_date as one month from the start of subscription
-		subs.EndDate = subs.StartDate.AddDate(0, 1, 0)
-		log = log.With("exp_date", subs.EndDate)
+	// Unlike creation, an update has no catalog default to fall back to
+	if subs.Price <= 0 {
+		return domain.ErrPriceField
+	}
+
+	if err := subs.ValidateTrial(); err != nil {
+		log.Error("Invalid trial", "error", err)
+		return err
 	}
 
-	if err := s.repo.Update(ctx, subs); err != nil {
+	if err := setBillingPeriod(&subs); err != nil {
+		log.Error("Invalid billing period", "error", err)
+		return err
+	}
+	log = log.With("exp_date", subs.EndDate, "billing_period", subs.BillingPeriod)
+
+	updated, err := s.repo.Update(ctx, subs)
+	if err != nil {
 		log.Error("Failed to update subscription", "error", err)
 		return err
 	}
 
 	log.Info("Subscription has been updated")
+	s.evaluateBudgets(ctx, log, updated)
 	return nil
 }
 
@@ -162,46 +343,226 @@ func (s *SubsService) DeleteSubscriptionList(ctx context.Context, userID string)
 	return nil
 }
 
+// RestoreSubscription brings back a deleted subscription unless the uniqueness policy forbids it
+// because the service has been subscribed to again since.
+func (s *SubsService) RestoreSubscription(ctx context.Context, serviceName, userID string) (domain.Subscription, error) {
+	const op = "SubsService.RestoreSubscription"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("service_name", serviceName),
+		slog.String("user_ID", userID),
+	)
+
+	if err := s.checkUnique(ctx, serviceName, userID); err != nil {
+		log.Error("Failed to check subscription uniqueness", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	id, err := s.repo.Restore(ctx, serviceName, userID)
+	if err != nil {
+		log.Error("Failed to restore subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	subs, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		log.Error("Failed to get restored subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	log.Info("Subscription has been restored")
+	return withBilling(subs, time.Now()), nil
+}
+
+// PurgeDeletedSubscriptions permanently removes subscriptions deleted longer than retention ago.
+func (s *SubsService) PurgeDeletedSubscriptions(ctx context.Context, retention time.Duration) (int64, error) {
+	const op = "SubsService.PurgeDeletedSubscriptions"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("retention", retention.String()),
+	)
+
+	purged, err := s.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.Error("Failed to purge deleted subscriptions", "error", err)
+		return 0, err
+	}
+
+	log.Info("Deleted subscriptions have been purged", slog.Int64("purged", purged))
+	return purged, nil
+}
+
+// ExpireSubscriptions renews auto-renewing subscriptions whose term has ended, moves the other
+// live subscriptions that have ended to the expired status and returns how many have been expired.
+func (s *SubsService) ExpireSubscriptions(ctx context.Context) (int64, error) {
+	const op = "SubsService.ExpireSubscriptions"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	now := time.Now()
+	renewed, err := s.repo.RenewEnded(ctx, now)
+	if err != nil {
+		log.Error("Failed to renew ended subscriptions", "error", err)
+		return 0, err
+	}
+
+	expired, err := s.repo.ExpireEnded(ctx, now)
+	if err != nil {
+		log.Error("Failed to expire ended subscriptions", "error", err)
+		return 0, err
+	}
+
+	log.Info("Ended subscriptions have been renewed or expired", slog.Int64("renewed", renewed),
+		slog.Int64("expired", expired))
+	return expired, nil
+}
+
 // GetSummaryByFilter retrieves a summary of subscriptions based on the provided filter criteria.
-// It returns the total price, count of subscriptions, and the list of subscriptions.
-func (s *SubsService) GetSummaryByFilter(ctx context.Context, start, end time.Time, serviceName, userID string, pageNum, pageSize int) (domain.Summary, error) {
+// Total count and cost are calculated by the repository over the whole filtered set,
+// the page subtotal and the per-subscription cost breakdown only cover the requested page.
+// If filter.GroupBy is set, subtotals of the whole filtered set are added per group.
+func (s *SubsService) GetSummaryByFilter(ctx context.Context, filter domain.SummaryFilter) (domain.Summary, error) {
 	const op = "SubsService.GetSummaryByFilter"
 	log := s.log.With(
 		slog.String("op", op),
-		slog.String("service_name", serviceName),
-		slog.String("user_id", userID),
-		slog.String("filter_start_date", start.String()),
-		slog.String("filter_end_date", end.String()),
-		slog.Int("page_number", pageNum),
-		slog.Int("page_size", pageSize),
+		slog.String("service_name", filter.ServiceName),
+		slog.String("user_id", filter.UserID),
+		slog.String("filter_mode", string(filter.Mode)),
+		slog.String("filter_start_date", filter.Start.String()),
+		slog.String("filter_end_date", filter.End.String()),
+		slog.Int("page_number", filter.PageNumber),
+		slog.Int("page_size", filter.PageSize),
+		slog.Any("tags", filter.Tags),
+		slog.String("group_by", string(filter.GroupBy)),
 	)
 
-	subs, err := s.repo.SubsListByFilter(ctx, start, end, serviceName, userID, pageNum, pageSize)
+	if filter.Mode == "" {
+		filter.Mode = domain.FilterOverlap
+	}
+
+	if !filter.Mode.IsValid() {
+		return domain.Summary{}, domain.ErrInvalidFilterMode
+	}
+
+	if filter.GroupBy != "" && !filter.GroupBy.IsValid() {
+		return domain.Summary{}, domain.ErrInvalidGroupBy
+	}
+	filter.Tags = domain.NormalizeTags(filter.Tags)
+
+	if filter.Mode == domain.FilterActive {
+		// Active mode looks at a single instant, so the cost is calculated for that day
+		filter.End = filter.Start
+	}
+
+	page, err := s.repo.SubsListByFilter(ctx, filter)
 	if err != nil {
 		log.Error("Failed to get subs list by filter", "error", err)
 		return domain.Summary{}, err
 	}
 
-	if len(subs) == 0 {
+	if page.TotalItems == 0 {
 		log.Error("Subscription list is empty")
 		return domain.Summary{}, domain.ErrSubsNotFound
 	}
 
+	breakdown := make([]domain.CostBreakdown, 0, len(page.Subscriptions))
+	for _, sub := range page.Subscriptions {
+		breakdown = append(breakdown, subsCost(sub, filter.Start, filter.End, filter.UserID))
+	}
+
 	summary := domain.Summary{
-		TotalPrice:    getSummary(subs),
-		SubsCount:     len(subs),
-		PageNumber:    pageNum,
-		PageSize:      pageSize,
-		Subscriptions: subs,
+		TotalPrice:    page.TotalPrice,
+		TotalItems:    page.TotalItems,
+		TotalPages:    (page.TotalItems + filter.PageSize - 1) / filter.PageSize,
+		PageTotal:     getSummary(breakdown),
+		PageNumber:    filter.PageNumber,
+		PageSize:      filter.PageSize,
+		Subscriptions: page.Subscriptions,
+		Breakdown:     breakdown,
+	}
+	summary.NextCursor, summary.PrevCursor = pageCursors(filter.Pagination, page)
+
+	if filter.GroupBy != "" {
+		summary.Groups, err = s.repo.SummaryGroups(ctx, filter)
+		if err != nil {
+			log.Error("Failed to get summary groups", "error", err)
+			return domain.Summary{}, err
+		}
 	}
-	log.Info("Subscription list summary has been calculated successfully", "subs_count", summary.SubsCount, "total_price", summary.TotalPrice, "page_number", summary.PageNumber, "page_size", summary.PageSize)
+	log.Info("Subscription list summary has been calculated successfully", "total_items", summary.TotalItems, "total_price", summary.TotalPrice, "page_number", summary.PageNumber, "page_size", summary.PageSize)
 	return summary, nil
 }
 
-func getSummary(subsList []domain.Subscription) int {
+func getSummary(breakdown []domain.CostBreakdown) int {
 	var total int
-	for _, sub := range subsList {
-		total += sub.Price
+	for _, cost := range breakdown {
+		total += cost.Amount
 	}
 	return total
 }
+
+// setBillingPeriod defaults the billing period to monthly, validates it
+// and sets the expiration date to one period after the billing start if it is not provided.
+func setBillingPeriod(subs *domain.Subscription) error {
+	if subs.BillingPeriod.IsZero() {
+		subs.BillingPeriod = domain.DefaultBillingPeriod
+	}
+
+	if err := subs.BillingPeriod.Validate(); err != nil {
+		return err
+	}
+
+	if subs.EndDate.IsZero() {
+		subs.EndDate = subs.BillingPeriod.Shift(subs.BillingStart(), 1)
+	}
+	return nil
+}
+
+// withBilling fills the next renewal date and the effective price of the subscription relative to now.
+func withBilling(subs domain.Subscription, now time.Time) domain.Subscription {
+	if renewal := subs.NextRenewal(now); !renewal.IsZero() {
+		subs.RenewalDate = &renewal
+	}
+	effective := subs.EffectivePriceAt(now)
+	subs.EffectivePrice = &effective
+	return subs
+}
+
+// pageCursors returns the cursors of the pages following and preceding the given one.
+func pageCursors(page domain.Pagination, subsPage domain.SubsPage) (next, prev string) {
+	subs := subsPage.Subscriptions
+	if len(subs) == 0 {
+		return "", ""
+	}
+
+	first := domain.Cursor{StartDate: subs[0].StartDate, ID: subs[0].ID}
+	last := domain.Cursor{StartDate: subs[len(subs)-1].StartDate, ID: subs[len(subs)-1].ID}
+	return neighbourCursors(page, first, last, subsPage.HasMore)
+}
+
+// neighbourCursors returns cursors of the pages around the one bounded by the first and last rows.
+func neighbourCursors(page domain.Pagination, firstRow, lastRow domain.Cursor, hasMore bool) (next, prev string) {
+	first, last := firstRow.Encode(), lastRow.Encode()
+	switch {
+	case page.Before != nil:
+		// Page was read backwards, so extra rows mean there is a previous page
+		next = last
+		if hasMore {
+			prev = first
+		}
+	case page.After != nil:
+		prev = first
+		if hasMore {
+			next = last
+		}
+	default:
+		if page.PageNumber > 1 {
+			prev = first
+		}
+		if hasMore {
+			next = last
+		}
+	}
+	return next, prev
+}
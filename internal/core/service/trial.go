@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// ConvertTrials moves trialing subscriptions whose trial has ended to the active status
+// and returns how many have been converted.
+func (s *SubsService) ConvertTrials(ctx context.Context) (int64, error) {
+	const op = "SubsService.ConvertTrials"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	converted, err := s.repo.ConvertEndedTrials(ctx, time.Now())
+	if err != nil {
+		log.Error("Failed to convert ended trials", "error", err)
+		return 0, err
+	}
+
+	log.Info("Ended trials have been converted", slog.Int64("converted", converted))
+	return converted, nil
+}
+
+// GetTrialReport returns per service how many trials started in the filter window
+// converted to paid, were cancelled or expired, together with the conversion rate.
+func (s *SubsService) GetTrialReport(ctx context.Context, filter domain.TrialReportFilter) ([]domain.TrialConversion, error) {
+	const op = "SubsService.GetTrialReport"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("service_name", filter.ServiceName),
+		slog.String("filter_start_date", filter.Start.String()),
+		slog.String("filter_end_date", filter.End.String()),
+	)
+
+	if !filter.Start.IsZero() && !filter.End.IsZero() && filter.End.Before(filter.Start) {
+		return nil, domain.ErrInvalidDate
+	}
+
+	report, err := s.repo.TrialReport(ctx, filter)
+	if err != nil {
+		log.Error("Failed to get trial report", "error", err)
+		return nil, err
+	}
+
+	for i := range report {
+		report[i].SetConversionRate()
+	}
+
+	log.Info("Trial report has been retrieved", slog.Int("services", len(report)))
+	return report, nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+	"time"
+)
+
+// WebhookPolicy controls how failed webhook deliveries are retried.
+type WebhookPolicy struct {
+	// MaxAttempts is the number of delivery attempts before the delivery is moved to the dead letters.
+	MaxAttempts int
+	// RetryDelay is the delay before the first retry, it doubles with every further attempt up to a day.
+	RetryDelay time.Duration
+}
+
+// WebhookService fans subscription events out to registered endpoints and delivers them.
+type WebhookService struct {
+	repo   domain.WebhookRepo
+	sender domain.WebhookSender
+	policy WebhookPolicy
+	log    logger.Logger
+}
+
+func NewWebhookService(repo domain.WebhookRepo, sender domain.WebhookSender, policy WebhookPolicy, log logger.Logger) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		policy: policy,
+		log:    log,
+	}
+}
+
+// Publish schedules the event for every endpoint subscribed to its type,
+// it is sent by the following DeliverWebhooks run. A repeated event is scheduled only once.
+func (s *WebhookService) Publish(ctx context.Context, event domain.Event) error {
+	const op = "WebhookService.Publish"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("event_id", event.ID),
+		slog.String("event_type", string(event.Type)),
+	)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error("Failed to encode event", "error", err)
+		return err
+	}
+
+	scheduled, err := s.repo.EnqueueDeliveries(ctx, event, payload)
+	if err != nil {
+		log.Error("Failed to schedule webhook deliveries", "error", err)
+		return err
+	}
+
+	log.Info("Webhook deliveries have been scheduled", slog.Int64("scheduled", scheduled))
+	return nil
+}
+
+// DeliverWebhooks sends the due deliveries, returning how many have been delivered.
+// Failed deliveries are retried with exponential backoff by the following runs
+// and moved to the dead letters once they run out of attempts.
+func (s *WebhookService) DeliverWebhooks(ctx context.Context) (int, error) {
+	const op = "WebhookService.DeliverWebhooks"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	now := time.Now()
+	deliveries, err := s.repo.ClaimDeliveries(ctx, now, deliveryLease, deliveryBatch)
+	if err != nil {
+		log.Error("Failed to claim due deliveries", "error", err)
+		return 0, err
+	}
+
+	delivered, err := deliverClaimed(ctx, log, now, deliveries, s.retryPolicy(), leasedDelivery[domain.WebhookDelivery]{
+		name:     "webhook",
+		attempts: func(d domain.WebhookDelivery) int { return d.Attempts },
+		logArgs: func(d domain.WebhookDelivery) []any {
+			return []any{slog.Int64("delivery_id", d.ID), slog.String("endpoint_id", d.EndpointID)}
+		},
+		send: s.sender.Send,
+		markSent: func(ctx context.Context, d domain.WebhookDelivery, at time.Time) error {
+			return s.repo.MarkDelivered(ctx, d.ID, at)
+		},
+		markFailed: func(ctx context.Context, d domain.WebhookDelivery, reason string, next time.Time) error {
+			return s.repo.MarkDeliveryFailed(ctx, d.ID, reason, next)
+		},
+	})
+	if err != nil {
+		return delivered, err
+	}
+
+	log.Info("Webhook deliveries have been processed", slog.Int("due", len(deliveries)),
+		slog.Int("delivered", delivered))
+	return delivered, nil
+}
+
+func (s *WebhookService) retryPolicy() retryPolicy {
+	return retryPolicy{maxAttempts: s.policy.MaxAttempts, retryDelay: s.policy.RetryDelay}
+}
+
+// CreateEndpoint registers the endpoint and returns it with its secret.
+// A secret is generated if none is given, it is not shown again afterwards.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, endpoint domain.WebhookEndpoint) (domain.WebhookEndpoint, error) {
+	const op = "WebhookService.CreateEndpoint"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("url", endpoint.URL),
+		slog.Any("event_types", endpoint.EventTypes),
+	)
+
+	if err := endpoint.Validate(); err != nil {
+		log.Error("Invalid webhook endpoint", "error", err)
+		return domain.WebhookEndpoint{}, err
+	}
+
+	if len(endpoint.Secret) == 0 {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			log.Error("Failed to generate webhook secret", "error", err)
+			return domain.WebhookEndpoint{}, err
+		}
+		endpoint.Secret = secret
+	}
+
+	id, err := s.repo.CreateEndpoint(ctx, endpoint)
+	if err != nil {
+		log.Error("Failed to create webhook endpoint", "error", err)
+		return domain.WebhookEndpoint{}, err
+	}
+	endpoint.ID = id
+
+	log.Info("Webhook endpoint has been created", slog.String("ID", id))
+	return endpoint, nil
+}
+
+// GetEndpoint retrieves an endpoint by its ID without its secret.
+func (s *WebhookService) GetEndpoint(ctx context.Context, id string) (domain.WebhookEndpoint, error) {
+	const op = "WebhookService.GetEndpoint"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	endpoint, err := s.repo.GetEndpoint(ctx, id)
+	if err != nil {
+		log.Error("Failed to get webhook endpoint", "error", err)
+		return domain.WebhookEndpoint{}, err
+	}
+
+	log.Info("Webhook endpoint has been retrieved")
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+// ListEndpoints retrieves all endpoints without their secrets.
+func (s *WebhookService) ListEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	const op = "WebhookService.ListEndpoints"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	endpoints, err := s.repo.ListEndpoints(ctx)
+	if err != nil {
+		log.Error("Failed to get webhook endpoints", "error", err)
+		return nil, err
+	}
+
+	for i := range endpoints {
+		endpoints[i].Secret = ""
+	}
+
+	log.Info("Webhook endpoints have been retrieved")
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes the endpoint, its pending deliveries are dropped.
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id string) error {
+	const op = "WebhookService.DeleteEndpoint"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("ID", id),
+	)
+
+	if err := s.repo.DeleteEndpoint(ctx, id); err != nil {
+		log.Error("Failed to delete webhook endpoint", "error", err)
+		return err
+	}
+
+	log.Info("Webhook endpoint has been deleted")
+	return nil
+}
+
+// GetDeadLetters retrieves deliveries that ran out of attempts, newest first.
+func (s *WebhookService) GetDeadLetters(ctx context.Context) ([]domain.WebhookDelivery, error) {
+	const op = "WebhookService.GetDeadLetters"
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	deliveries, err := s.repo.ListDeliveries(ctx, domain.DeliveryDead)
+	if err != nil {
+		log.Error("Failed to get dead letters", "error", err)
+		return nil, err
+	}
+
+	log.Info("Dead letters have been retrieved", slog.Int("count", len(deliveries)))
+	return deliveries, nil
+}
+
+// Redeliver schedules the delivery to be sent again by the next run with a fresh attempt budget.
+func (s *WebhookService) Redeliver(ctx context.Context, id int64) error {
+	const op = "WebhookService.Redeliver"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("ID", id),
+	)
+
+	if err := s.repo.Redeliver(ctx, id); err != nil {
+		log.Error("Failed to schedule redelivery", "error", err)
+		return err
+	}
+
+	log.Info("Webhook delivery has been scheduled for redelivery")
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,6 +1,7 @@
 package httputils
 
 import (
+	"errors"
 	"net/http"
 	"submanager/internal/core/domain"
 
@@ -25,11 +26,29 @@ func SendMessage(ctx *gin.Context, code int, message string) {
 }
 
 func GetStatus(err error) int {
-	switch err {
-	case domain.ErrSubNotUnique:
+	switch {
+	case errors.Is(err, domain.ErrSubNotUnique), errors.Is(err, domain.ErrStatusConflict),
+		errors.Is(err, domain.ErrSubsAmbiguous), errors.Is(err, domain.ErrAliasTaken):
 		return http.StatusConflict
-	case domain.ErrSubsNotFound:
+	case errors.Is(err, domain.ErrSubsNotFound), errors.Is(err, domain.ErrServiceNotFound),
+		errors.Is(err, domain.ErrBudgetNotFound), errors.Is(err, domain.ErrWebhookNotFound),
+		errors.Is(err, domain.ErrDeliveryNotFound), errors.Is(err, domain.ErrShareNotFound):
 		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidBillingPeriod), errors.Is(err, domain.ErrInvalidFilterMode),
+		errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidDate), errors.Is(err, domain.ErrPriceField), errors.Is(err, domain.ErrInvalidTrial),
+		errors.Is(err, domain.ErrInvalidCancellation),
+		errors.Is(err, domain.ErrInvalidImport),
+		errors.Is(err, domain.ErrInvalidDiscount), errors.Is(err, domain.ErrInvalidShare), errors.Is(err, domain.ErrInvalidShareStatus),
+		errors.Is(err, domain.ErrInvalidService), errors.Is(err, domain.ErrInvalidServiceStatus),
+		errors.Is(err, domain.ErrInvalidGroupBy), errors.Is(err, domain.ErrInvalidForecast), errors.Is(err, domain.ErrInvalidBucket),
+		errors.Is(err, domain.ErrInvalidAnalytics),
+		errors.Is(err, domain.ErrInvalidBudget), errors.Is(err, domain.ErrInvalidMonth), errors.Is(err, domain.ErrInvalidWebhook):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrShareAnswered):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrAdminToken):
+		return http.StatusUnauthorized
 	default:
 		return http.StatusInternalServerError
 	}
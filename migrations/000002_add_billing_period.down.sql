@@ -0,0 +1,3 @@
+ALTER TABLE Subscriptions
+    DROP COLUMN IF EXISTS Period_interval,
+    DROP COLUMN IF EXISTS Period_unit;
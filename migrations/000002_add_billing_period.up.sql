@@ -0,0 +1,5 @@
+ALTER TABLE Subscriptions
+    ADD COLUMN IF NOT EXISTS Period_unit TEXT NOT NULL DEFAULT 'month'
+        CHECK (Period_unit IN ('day', 'week', 'month', 'year')),
+    ADD COLUMN IF NOT EXISTS Period_interval INT NOT NULL DEFAULT 1
+        CHECK (Period_interval > 0);
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS subscription_cost(Subscriptions, TIMESTAMPTZ, TIMESTAMPTZ);
+
+DROP FUNCTION IF EXISTS billing_shift(TIMESTAMPTZ, TEXT, INT, INT);
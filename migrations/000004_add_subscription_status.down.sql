@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_status_history_subscription;
+
+DROP TABLE IF EXISTS Subscription_status_history;
+
+ALTER TABLE Subscriptions
+    DROP COLUMN IF EXISTS Status_changed_at,
+    DROP COLUMN IF EXISTS Status;
@@ -0,0 +1,15 @@
+ALTER TABLE Subscriptions
+    ADD COLUMN IF NOT EXISTS Status TEXT NOT NULL DEFAULT 'active'
+        CHECK (Status IN ('trialing', 'active', 'paused', 'cancelled', 'expired')),
+    ADD COLUMN IF NOT EXISTS Status_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE TABLE IF NOT EXISTS Subscription_status_history(
+    ID BIGSERIAL PRIMARY KEY,
+    Subscription_ID UUID NOT NULL REFERENCES Subscriptions(ID) ON DELETE CASCADE,
+    From_status TEXT NOT NULL,
+    To_status TEXT NOT NULL,
+    Changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_history_subscription
+    ON Subscription_status_history(Subscription_ID, Changed_at);
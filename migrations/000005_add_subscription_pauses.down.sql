@@ -0,0 +1,48 @@
+-- Mirrors the service cost calculation: billing cycles inside [w_start, w_until) are charged
+-- the full price, cycles cut by the window or by the subscription term are prorated by days
+CREATE OR REPLACE FUNCTION subscription_cost(s Subscriptions, w_start TIMESTAMPTZ, w_until TIMESTAMPTZ)
+RETURNS BIGINT
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    billed_from  TIMESTAMPTZ := GREATEST(w_start, s.Start_date);
+    billed_until TIMESTAMPTZ := LEAST(w_until, COALESCE(s.Exp_date, w_until));
+    max_days     INT := s.Period_interval * CASE s.Period_unit
+        WHEN 'day' THEN 1 WHEN 'week' THEN 7 WHEN 'month' THEN 31 ELSE 366 END;
+    cycle_start  TIMESTAMPTZ;
+    cycle_end    TIMESTAMPTZ;
+    full_cycles  BIGINT := 0;
+    prorated     NUMERIC := 0;
+    n            INT := 0;
+BEGIN
+    IF billed_from >= billed_until THEN
+        RETURN 0;
+    END IF;
+
+    -- Skip cycles which surely end before the billed interval
+    IF billed_from > s.Start_date THEN
+        n := floor(EXTRACT(EPOCH FROM billed_from - s.Start_date) / 86400)::INT / max_days;
+    END IF;
+
+    LOOP
+        cycle_start := billing_shift(s.Start_date, s.Period_unit, s.Period_interval, n);
+        cycle_end   := billing_shift(s.Start_date, s.Period_unit, s.Period_interval, n + 1);
+        EXIT WHEN cycle_start >= billed_until;
+        n := n + 1;
+        CONTINUE WHEN cycle_end <= billed_from;
+
+        IF cycle_start >= billed_from AND cycle_end <= billed_until THEN
+            full_cycles := full_cycles + 1;
+        ELSE
+            prorated := prorated + s.Price
+                * EXTRACT(EPOCH FROM LEAST(cycle_end, billed_until) - GREATEST(cycle_start, billed_from))
+                / EXTRACT(EPOCH FROM cycle_end - cycle_start);
+        END IF;
+    END LOOP;
+
+    RETURN full_cycles * s.Price + round(prorated);
+END;
+$$;
+
+DROP INDEX IF EXISTS idx_pauses_open;
+
+DROP TABLE IF EXISTS Subscription_pauses;
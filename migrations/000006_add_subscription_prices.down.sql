@@ -0,0 +1,64 @@
+-- Mirrors the service cost calculation: billing cycles inside [w_start, w_until) are charged
+-- the full price, cycles cut by the window, by the subscription term or by pauses are prorated by days
+CREATE OR REPLACE FUNCTION subscription_cost(s Subscriptions, w_start TIMESTAMPTZ, w_until TIMESTAMPTZ)
+RETURNS BIGINT
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    billed_from  TIMESTAMPTZ := GREATEST(w_start, s.Start_date);
+    billed_until TIMESTAMPTZ := LEAST(w_until, COALESCE(s.Exp_date, w_until));
+    max_days     INT := s.Period_interval * CASE s.Period_unit
+        WHEN 'day' THEN 1 WHEN 'week' THEN 7 WHEN 'month' THEN 31 ELSE 366 END;
+    cycle_start  TIMESTAMPTZ;
+    cycle_end    TIMESTAMPTZ;
+    o_start      TIMESTAMPTZ;
+    o_end        TIMESTAMPTZ;
+    paused       NUMERIC;
+    full_cycles  BIGINT := 0;
+    prorated     NUMERIC := 0;
+    n            INT := 0;
+BEGIN
+    IF billed_from >= billed_until THEN
+        RETURN 0;
+    END IF;
+
+    -- Skip cycles which surely end before the billed interval
+    IF billed_from > s.Start_date THEN
+        n := floor(EXTRACT(EPOCH FROM billed_from - s.Start_date) / 86400)::INT / max_days;
+    END IF;
+
+    LOOP
+        cycle_start := billing_shift(s.Start_date, s.Period_unit, s.Period_interval, n);
+        cycle_end   := billing_shift(s.Start_date, s.Period_unit, s.Period_interval, n + 1);
+        EXIT WHEN cycle_start >= billed_until;
+        n := n + 1;
+        CONTINUE WHEN cycle_end <= billed_from;
+
+        o_start := GREATEST(cycle_start, billed_from);
+        o_end   := LEAST(cycle_end, billed_until);
+
+        SELECT COALESCE(SUM(EXTRACT(EPOCH FROM
+                LEAST(COALESCE(p.Resumed_at, o_end), o_end) - GREATEST(p.Paused_at, o_start))), 0)
+        INTO paused
+        FROM Subscription_pauses p
+        WHERE p.Subscription_ID = s.ID
+            AND p.Paused_at < o_end
+            AND (p.Resumed_at IS NULL OR p.Resumed_at > o_start);
+
+        IF paused = 0 AND o_start = cycle_start AND o_end = cycle_end THEN
+            full_cycles := full_cycles + 1;
+        ELSE
+            prorated := prorated + s.Price
+                * (EXTRACT(EPOCH FROM o_end - o_start) - paused)
+                / EXTRACT(EPOCH FROM cycle_end - cycle_start);
+        END IF;
+    END LOOP;
+
+    RETURN full_cycles * s.Price + round(prorated);
+END;
+$$;
+
+DROP FUNCTION IF EXISTS subscription_price_at(Subscriptions, TIMESTAMPTZ);
+
+DROP INDEX IF EXISTS idx_prices_subscription;
+
+DROP TABLE IF EXISTS Subscription_prices;
@@ -0,0 +1,89 @@
+CREATE TABLE IF NOT EXISTS Subscription_prices(
+    ID BIGSERIAL PRIMARY KEY,
+    Subscription_ID UUID NOT NULL REFERENCES Subscriptions(ID) ON DELETE CASCADE,
+    Price INT NOT NULL CHECK(Price >= 0),
+    Effective_from TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_prices_subscription
+    ON Subscription_prices(Subscription_ID, Effective_from);
+
+-- Existing subscriptions keep their current price from the start
+INSERT INTO Subscription_prices(Subscription_ID, Price, Effective_from)
+SELECT ID, Price, COALESCE(Start_date, NOW()) FROM Subscriptions;
+
+-- Mirrors Subscription.PriceAt
+CREATE OR REPLACE FUNCTION subscription_price_at(s Subscriptions, at TIMESTAMPTZ)
+RETURNS INT
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(
+        (SELECT p.Price FROM Subscription_prices p
+            WHERE p.Subscription_ID = s.ID AND p.Effective_from <= at
+            ORDER BY p.Effective_from DESC, p.ID DESC LIMIT 1),
+        (SELECT p.Price FROM Subscription_prices p
+            WHERE p.Subscription_ID = s.ID
+            ORDER BY p.Effective_from, p.ID LIMIT 1),
+        s.Price)
+$$;
+
+-- Mirrors the service cost calculation: billing cycles inside [w_start, w_until) are charged
+-- the full price, cycles cut by the window, by the subscription term or by pauses are prorated by days.
+-- Each cycle is charged the price in effect on its billing date
+CREATE OR REPLACE FUNCTION subscription_cost(s Subscriptions, w_start TIMESTAMPTZ, w_until TIMESTAMPTZ)
+RETURNS BIGINT
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    billed_from  TIMESTAMPTZ := GREATEST(w_start, s.Start_date);
+    billed_until TIMESTAMPTZ := LEAST(w_until, COALESCE(s.Exp_date, w_until));
+    max_days     INT := s.Period_interval * CASE s.Period_unit
+        WHEN 'day' THEN 1 WHEN 'week' THEN 7 WHEN 'month' THEN 31 ELSE 366 END;
+    cycle_start  TIMESTAMPTZ;
+    cycle_end    TIMESTAMPTZ;
+    o_start      TIMESTAMPTZ;
+    o_end        TIMESTAMPTZ;
+    paused       NUMERIC;
+    price        INT;
+    full_amount  BIGINT := 0;
+    prorated     NUMERIC := 0;
+    n            INT := 0;
+BEGIN
+    IF billed_from >= billed_until THEN
+        RETURN 0;
+    END IF;
+
+    -- Skip cycles which surely end before the billed interval
+    IF billed_from > s.Start_date THEN
+        n := floor(EXTRACT(EPOCH FROM billed_from - s.Start_date) / 86400)::INT / max_days;
+    END IF;
+
+    LOOP
+        cycle_start := billing_shift(s.Start_date, s.Period_unit, s.Period_interval, n);
+        cycle_end   := billing_shift(s.Start_date, s.Period_unit, s.Period_interval, n + 1);
+        EXIT WHEN cycle_start >= billed_until;
+        n := n + 1;
+        CONTINUE WHEN cycle_end <= billed_from;
+
+        price   := subscription_price_at(s, cycle_start);
+        o_start := GREATEST(cycle_start, billed_from);
+        o_end   := LEAST(cycle_end, billed_until);
+
+        SELECT COALESCE(SUM(EXTRACT(EPOCH FROM
+                LEAST(COALESCE(p.Resumed_at, o_end), o_end) - GREATEST(p.Paused_at, o_start))), 0)
+        INTO paused
+        FROM Subscription_pauses p
+        WHERE p.Subscription_ID = s.ID
+            AND p.Paused_at < o_end
+            AND (p.Resumed_at IS NULL OR p.Resumed_at > o_start);
+
+        IF paused = 0 AND o_start = cycle_start AND o_end = cycle_end THEN
+            full_amount := full_amount + price;
+        ELSE
+            prorated := prorated + price
+                * (EXTRACT(EPOCH FROM o_end - o_start) - paused)
+                / EXTRACT(EPOCH FROM cycle_end - cycle_start);
+        END IF;
+    END LOOP;
+
+    RETURN full_amount + round(prorated);
+END;
+$$;
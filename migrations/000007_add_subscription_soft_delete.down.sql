@@ -0,0 +1,9 @@
+DELETE FROM Subscriptions WHERE Deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_subscriptions_deleted_at;
+DROP INDEX IF EXISTS idx_subscriptions_user_service;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_service
+    ON Subscriptions(User_ID, Service_name);
+
+ALTER TABLE Subscriptions
+    DROP COLUMN IF EXISTS Deleted_at;
@@ -0,0 +1,10 @@
+ALTER TABLE Subscriptions
+    ADD COLUMN IF NOT EXISTS Deleted_at TIMESTAMPTZ;
+
+-- Soft-deleted subscriptions no longer block creating the same service again
+DROP INDEX IF EXISTS idx_subscriptions_user_service;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_service
+    ON Subscriptions(User_ID, Service_name) WHERE Deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_deleted_at
+    ON Subscriptions(Deleted_at) WHERE Deleted_at IS NOT NULL;
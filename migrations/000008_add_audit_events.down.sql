@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_events_user;
+
+DROP TABLE IF EXISTS Audit_events;
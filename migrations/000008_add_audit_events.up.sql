@@ -0,0 +1,15 @@
+-- Audit events outlive the subscriptions they describe, so there is no foreign key
+CREATE TABLE IF NOT EXISTS Audit_events(
+    ID BIGSERIAL PRIMARY KEY,
+    Subscription_ID UUID NOT NULL,
+    User_ID UUID NOT NULL,
+    Actor TEXT NOT NULL,
+    Operation TEXT NOT NULL CHECK (Operation IN ('create', 'update', 'delete', 'delete_list', 'restore')),
+    Before JSONB,
+    After JSONB,
+    Request_ID TEXT,
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_user
+    ON Audit_events(User_ID, Created_at DESC, ID DESC);
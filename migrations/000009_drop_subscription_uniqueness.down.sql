@@ -0,0 +1,5 @@
+-- Fails if a user holds several live subscriptions of the same service,
+-- they have to be deleted before rolling back
+DROP INDEX IF EXISTS idx_subscriptions_user_service;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_user_service
+    ON Subscriptions(User_ID, Service_name) WHERE Deleted_at IS NULL;
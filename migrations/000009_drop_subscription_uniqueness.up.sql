@@ -0,0 +1,4 @@
+-- Uniqueness of a service per user is enforced by the configurable service policy
+DROP INDEX IF EXISTS idx_subscriptions_user_service;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_service
+    ON Subscriptions(User_ID, Service_name) WHERE Deleted_at IS NULL;
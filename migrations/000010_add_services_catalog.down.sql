@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_subscriptions_service;
+
+ALTER TABLE Subscriptions
+    DROP COLUMN IF EXISTS Service_ID;
+
+DROP INDEX IF EXISTS idx_service_aliases_service;
+
+DROP TABLE IF EXISTS Service_aliases;
+
+DROP TABLE IF EXISTS Services;
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS Services(
+    ID UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    Name TEXT NOT NULL,
+    Category TEXT NOT NULL DEFAULT '',
+    Vendor TEXT NOT NULL DEFAULT '',
+    Default_price INT NOT NULL DEFAULT 0 CHECK (Default_price >= 0),
+    Status TEXT NOT NULL DEFAULT 'approved' CHECK (Status IN ('approved', 'pending_review')),
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Canonical names and aliases, matched by their normalized form (lower case letters and digits only)
+CREATE TABLE IF NOT EXISTS Service_aliases(
+    Normalized TEXT PRIMARY KEY,
+    Alias TEXT NOT NULL,
+    Service_ID UUID NOT NULL REFERENCES Services(ID) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_aliases_service
+    ON Service_aliases(Service_ID);
+
+ALTER TABLE Subscriptions
+    ADD COLUMN IF NOT EXISTS Service_ID UUID REFERENCES Services(ID) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service
+    ON Subscriptions(Service_ID);
+
+-- Existing free text names become pending services, one per normalized name
+INSERT INTO Services(Name, Status)
+SELECT MIN(Service_name), 'pending_review'
+FROM Subscriptions
+GROUP BY COALESCE(NULLIF(lower(regexp_replace(Service_name, '[^[:alnum:]]', '', 'g')), ''), lower(trim(Service_name)));
+
+INSERT INTO Service_aliases(Normalized, Alias, Service_ID)
+SELECT COALESCE(NULLIF(lower(regexp_replace(Name, '[^[:alnum:]]', '', 'g')), ''), lower(trim(Name))), Name, ID
+FROM Services
+ON CONFLICT (Normalized) DO NOTHING;
+
+UPDATE Subscriptions s
+SET Service_ID = a.Service_ID
+FROM Service_aliases a
+WHERE a.Normalized = COALESCE(NULLIF(lower(regexp_replace(s.Service_name, '[^[:alnum:]]', '', 'g')), ''),
+    lower(trim(s.Service_name)));
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_subscription_tags_tag;
+
+DROP TABLE IF EXISTS Subscription_tags;
+
+DROP TABLE IF EXISTS Tags;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS Tags(
+    ID BIGSERIAL PRIMARY KEY,
+    Name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS Subscription_tags(
+    Subscription_ID UUID NOT NULL REFERENCES Subscriptions(ID) ON DELETE CASCADE,
+    Tag_ID BIGINT NOT NULL REFERENCES Tags(ID) ON DELETE CASCADE,
+    PRIMARY KEY (Subscription_ID, Tag_ID)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_tags_tag
+    ON Subscription_tags(Tag_ID);
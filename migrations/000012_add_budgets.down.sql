@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_budget_alerts_user;
+
+DROP TABLE IF EXISTS Budget_alerts;
+
+DROP INDEX IF EXISTS idx_budgets_user;
+
+DROP TABLE IF EXISTS Budgets;
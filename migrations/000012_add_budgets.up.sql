@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS Budgets(
+    ID UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    User_ID UUID NOT NULL,
+    Amount INT NOT NULL CHECK (Amount > 0),
+    Category TEXT NOT NULL DEFAULT '',
+    Service_name TEXT NOT NULL DEFAULT '',
+    Thresholds INT[] NOT NULL,
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (Category = '' OR Service_name = '')
+);
+
+CREATE INDEX IF NOT EXISTS idx_budgets_user
+    ON Budgets(User_ID);
+
+-- Month is the first day of the alerted month, every threshold is alerted once a month
+CREATE TABLE IF NOT EXISTS Budget_alerts(
+    ID BIGSERIAL PRIMARY KEY,
+    Budget_ID UUID NOT NULL REFERENCES Budgets(ID) ON DELETE CASCADE,
+    User_ID UUID NOT NULL,
+    Threshold INT NOT NULL,
+    Month DATE NOT NULL,
+    Spend INT NOT NULL,
+    Amount INT NOT NULL,
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (Budget_ID, Threshold, Month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_alerts_user
+    ON Budget_alerts(User_ID, Created_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reminders_due;
+
+DROP TABLE IF EXISTS Reminders;
@@ -0,0 +1,18 @@
+-- One reminder per subscription end date, so extending a subscription schedules a new one
+CREATE TABLE IF NOT EXISTS Reminders(
+    ID BIGSERIAL PRIMARY KEY,
+    Subscription_ID UUID NOT NULL REFERENCES Subscriptions(ID) ON DELETE CASCADE,
+    User_ID UUID NOT NULL,
+    Service_name TEXT NOT NULL,
+    End_date TIMESTAMPTZ NOT NULL,
+    Status TEXT NOT NULL DEFAULT 'pending' CHECK (Status IN ('pending', 'sent', 'failed')),
+    Attempts INT NOT NULL DEFAULT 0,
+    Last_error TEXT,
+    Next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    Sent_at TIMESTAMPTZ,
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (Subscription_ID, End_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reminders_due
+    ON Reminders(Next_attempt_at, ID) WHERE Status = 'pending';
@@ -0,0 +1,30 @@
+-- Empty Event_types subscribes the endpoint to all event types
+CREATE TABLE IF NOT EXISTS Webhook_endpoints(
+    ID UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    URL TEXT NOT NULL,
+    Secret TEXT NOT NULL,
+    Event_types TEXT[] NOT NULL DEFAULT '{}',
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Payload is the event as sent, so redeliveries send the same body
+CREATE TABLE IF NOT EXISTS Webhook_deliveries(
+    ID BIGSERIAL PRIMARY KEY,
+    Endpoint_ID UUID NOT NULL REFERENCES Webhook_endpoints(ID) ON DELETE CASCADE,
+    Event_ID TEXT NOT NULL,
+    Event_type TEXT NOT NULL,
+    Payload JSONB NOT NULL,
+    Status TEXT NOT NULL DEFAULT 'pending' CHECK (Status IN ('pending', 'delivered', 'dead')),
+    Attempts INT NOT NULL DEFAULT 0,
+    Last_error TEXT,
+    Next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    Delivered_at TIMESTAMPTZ,
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (Endpoint_ID, Event_ID)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+    ON Webhook_deliveries(Next_attempt_at, ID) WHERE Status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_dead
+    ON Webhook_deliveries(Created_at DESC) WHERE Status = 'dead';
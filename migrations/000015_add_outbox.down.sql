@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+
+DROP TABLE IF EXISTS Outbox;
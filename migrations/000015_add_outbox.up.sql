@@ -0,0 +1,15 @@
+-- Events are written in the same transaction as the subscription change and relayed afterwards
+CREATE TABLE IF NOT EXISTS Outbox(
+    ID BIGSERIAL PRIMARY KEY,
+    Event_ID TEXT NOT NULL UNIQUE,
+    Event_type TEXT NOT NULL,
+    Payload JSONB NOT NULL,
+    Attempts INT NOT NULL DEFAULT 0,
+    Last_error TEXT,
+    Next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    Published_at TIMESTAMPTZ,
+    Created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished
+    ON Outbox(Next_attempt_at, ID) WHERE Published_at IS NULL;
@@ -0,0 +1,127 @@
+CREATE TABLE IF NOT EXISTS Subscription_participants(
+    Subscription_ID UUID NOT NULL REFERENCES Subscriptions(ID) ON DELETE CASCADE,
+    User_ID UUID NOT NULL,
+    Weight INT NOT NULL DEFAULT 0 CHECK(Weight >= 0),
+    Amount INT NOT NULL DEFAULT 0 CHECK(Amount >= 0),
+    Status TEXT NOT NULL DEFAULT 'invited' CHECK(Status IN ('invited', 'accepted', 'declined')),
+    Invited_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    Responded_at TIMESTAMPTZ,
+    PRIMARY KEY(Subscription_ID, User_ID),
+    CHECK((Weight > 0) <> (Amount > 0))
+);
+
+CREATE INDEX IF NOT EXISTS idx_participants_user
+    ON Subscription_participants(User_ID, Status);
+
+-- Mirrors Subscription.ShareOf: accepted participants with a fixed amount pay it first, scaled down
+-- if the amounts exceed the price, and the remainder is split by weight between the owner, who has
+-- weight 1, and the accepted participants with a weight. Shares which are not accepted stay with the owner
+CREATE OR REPLACE FUNCTION subscription_share(s Subscriptions, viewer UUID, price NUMERIC)
+RETURNS NUMERIC
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    fixed     NUMERIC;
+    weights   NUMERIC;
+    remainder NUMERIC;
+    p         Subscription_participants;
+BEGIN
+    IF viewer IS NULL THEN
+        RETURN price;
+    END IF;
+
+    SELECT COALESCE(SUM(Amount), 0), 1 + COALESCE(SUM(Weight), 0)
+    INTO fixed, weights
+    FROM Subscription_participants
+    WHERE Subscription_ID = s.ID AND Status = 'accepted';
+    remainder := price - LEAST(fixed, price);
+
+    SELECT * INTO p
+    FROM Subscription_participants
+    WHERE Subscription_ID = s.ID AND User_ID = viewer AND Status = 'accepted';
+
+    IF FOUND THEN
+        IF p.Amount > 0 THEN
+            RETURN p.Amount * LEAST(1, price / fixed);
+        END IF;
+        RETURN remainder * p.Weight / weights;
+    END IF;
+
+    IF viewer = s.User_ID THEN
+        RETURN remainder / weights;
+    END IF;
+    RETURN 0;
+END;
+$$;
+
+DROP FUNCTION IF EXISTS subscription_cost(Subscriptions, TIMESTAMPTZ, TIMESTAMPTZ);
+
+-- Mirrors the service cost calculation: the trial price is charged once when the trial starts
+-- inside [w_start, w_until), paid billing cycles are counted from the end of the trial.
+-- Billing cycles inside the window are charged the full price, cycles cut by the window,
+-- by the subscription term or by pauses are prorated by days.
+-- Each cycle is charged the price in effect on its billing date with its discount phase applied.
+-- If the viewer is set, only the viewer's share of every charge is counted
+CREATE FUNCTION subscription_cost(s Subscriptions, w_start TIMESTAMPTZ, w_until TIMESTAMPTZ, viewer UUID DEFAULT NULL)
+RETURNS BIGINT
+LANGUAGE plpgsql STABLE AS $$
+DECLARE
+    anchor       TIMESTAMPTZ := COALESCE(s.Trial_end, s.Start_date);
+    billed_from  TIMESTAMPTZ := GREATEST(w_start, anchor);
+    billed_until TIMESTAMPTZ := LEAST(w_until, COALESCE(s.Exp_date, w_until));
+    max_days     INT := s.Period_interval * CASE s.Period_unit
+        WHEN 'day' THEN 1 WHEN 'week' THEN 7 WHEN 'month' THEN 31 ELSE 366 END;
+    cycle_start  TIMESTAMPTZ;
+    cycle_end    TIMESTAMPTZ;
+    o_start      TIMESTAMPTZ;
+    o_end        TIMESTAMPTZ;
+    paused       NUMERIC;
+    price        NUMERIC;
+    trial_amount BIGINT := 0;
+    full_amount  NUMERIC := 0;
+    prorated     NUMERIC := 0;
+    n            INT := 0;
+BEGIN
+    IF s.Trial_end IS NOT NULL AND s.Start_date >= w_start AND s.Start_date < w_until THEN
+        trial_amount := round(subscription_share(s, viewer, s.Trial_price));
+    END IF;
+
+    IF billed_from >= billed_until THEN
+        RETURN trial_amount;
+    END IF;
+
+    -- Skip cycles which surely end before the billed interval
+    IF billed_from > anchor THEN
+        n := floor(EXTRACT(EPOCH FROM billed_from - anchor) / 86400)::INT / max_days;
+    END IF;
+
+    LOOP
+        cycle_start := billing_shift(anchor, s.Period_unit, s.Period_interval, n);
+        cycle_end   := billing_shift(anchor, s.Period_unit, s.Period_interval, n + 1);
+        EXIT WHEN cycle_start >= billed_until;
+        n := n + 1;
+        CONTINUE WHEN cycle_end <= billed_from;
+
+        price   := subscription_share(s, viewer, subscription_cycle_price(s, n - 1, cycle_start));
+        o_start := GREATEST(cycle_start, billed_from);
+        o_end   := LEAST(cycle_end, billed_until);
+
+        SELECT COALESCE(SUM(EXTRACT(EPOCH FROM
+                LEAST(COALESCE(p.Resumed_at, o_end), o_end) - GREATEST(p.Paused_at, o_start))), 0)
+        INTO paused
+        FROM Subscription_pauses p
+        WHERE p.Subscription_ID = s.ID
+            AND p.Paused_at < o_end
+            AND (p.Resumed_at IS NULL OR p.Resumed_at > o_start);
+
+        IF paused = 0 AND o_start = cycle_start AND o_end = cycle_end THEN
+            full_amount := full_amount + price;
+        ELSE
+            prorated := prorated + price
+                * (EXTRACT(EPOCH FROM o_end - o_start) - paused)
+                / EXTRACT(EPOCH FROM cycle_end - cycle_start);
+        END IF;
+    END LOOP;
+
+    RETURN trial_amount + round(full_amount) + round(prorated);
+END;
+$$;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_cancellations_service;
+
+DROP TABLE IF EXISTS Subscription_cancellations;
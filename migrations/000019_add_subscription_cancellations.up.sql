@@ -0,0 +1,23 @@
+-- Cancellations keep the service, the owner and the term of the subscription,
+-- so churn can be reported after the subscription itself has been purged
+CREATE TABLE IF NOT EXISTS Subscription_cancellations(
+    ID BIGSERIAL PRIMARY KEY,
+    Subscription_ID UUID REFERENCES Subscriptions(ID) ON DELETE SET NULL,
+    User_ID UUID NOT NULL,
+    Service_name TEXT NOT NULL,
+    Reason TEXT NOT NULL CHECK (Reason IN ('too_expensive', 'not_using', 'switched_service',
+        'missing_features', 'technical_issues', 'other', 'unspecified')),
+    Comment TEXT NOT NULL DEFAULT '',
+    Started_at TIMESTAMPTZ NOT NULL,
+    Cancelled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cancellations_service
+    ON Subscription_cancellations(Service_name, Cancelled_at);
+
+-- Subscriptions cancelled before reasons were recorded are kept with the unspecified reason
+INSERT INTO Subscription_cancellations(Subscription_ID, User_ID, Service_name, Reason, Started_at, Cancelled_at)
+SELECT s.ID, s.User_ID, s.Service_name, 'unspecified', COALESCE(s.Start_date, h.Changed_at), h.Changed_at
+FROM Subscription_status_history h
+JOIN Subscriptions s ON s.ID = h.Subscription_ID
+WHERE h.To_status = 'cancelled';
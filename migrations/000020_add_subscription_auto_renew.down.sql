@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_subscriptions_renewal;
+
+ALTER TABLE Subscriptions
+    DROP COLUMN IF EXISTS Auto_renew;
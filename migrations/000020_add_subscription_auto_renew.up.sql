@@ -0,0 +1,13 @@
+-- The end date of an auto-renewing subscription is the end of its current term,
+-- it is moved forward by whole billing periods when the term ends instead of expiring the subscription
+ALTER TABLE Subscriptions
+    ADD COLUMN IF NOT EXISTS Auto_renew BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- End dates derived from the billing period were meant to renew
+UPDATE Subscriptions
+SET Auto_renew = TRUE
+WHERE Exp_date = billing_shift(COALESCE(Trial_end, Start_date), Period_unit, Period_interval, 1);
+
+-- Ended auto-renewing terms are looked up to be renewed
+CREATE INDEX IF NOT EXISTS idx_subscriptions_renewal
+    ON Subscriptions(Exp_date) WHERE Auto_renew AND Deleted_at IS NULL;
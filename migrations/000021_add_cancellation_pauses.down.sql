@@ -0,0 +1,2 @@
+ALTER TABLE Subscription_cancellations
+    DROP COLUMN IF EXISTS Paused;
@@ -0,0 +1,12 @@
+-- Time the subscription spent paused before the cancellation, so its lifetime excludes pauses
+-- even after the subscription and its pauses have been purged
+ALTER TABLE Subscription_cancellations
+    ADD COLUMN IF NOT EXISTS Paused INTERVAL NOT NULL DEFAULT INTERVAL '0';
+
+UPDATE Subscription_cancellations c
+SET Paused = (
+    SELECT COALESCE(SUM(GREATEST(LEAST(COALESCE(p.Resumed_at, c.Cancelled_at), c.Cancelled_at)
+        - GREATEST(p.Paused_at, c.Started_at), INTERVAL '0')), INTERVAL '0')
+    FROM Subscription_pauses p
+    WHERE p.Subscription_ID = c.Subscription_ID AND p.Paused_at < c.Cancelled_at)
+WHERE c.Subscription_ID IS NOT NULL;
@@ -0,0 +1,7 @@
+DELETE FROM Reminders WHERE Status = 'skipped';
+
+ALTER TABLE Reminders
+    DROP CONSTRAINT IF EXISTS reminders_status_check;
+
+ALTER TABLE Reminders
+    ADD CONSTRAINT reminders_status_check CHECK (Status IN ('pending', 'sent', 'failed'));
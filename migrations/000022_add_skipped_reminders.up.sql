@@ -0,0 +1,6 @@
+-- Reminders of subscriptions that no longer end on the reminded date are skipped instead of sent
+ALTER TABLE Reminders
+    DROP CONSTRAINT IF EXISTS reminders_status_check;
+
+ALTER TABLE Reminders
+    ADD CONSTRAINT reminders_status_check CHECK (Status IN ('pending', 'sent', 'failed', 'skipped'));
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_unpublished_subscription;
+
+ALTER TABLE Outbox
+    DROP COLUMN IF EXISTS Subscription_ID;
@@ -0,0 +1,10 @@
+-- Events of a subscription are relayed in order, so an unpublished event holds back the later ones
+ALTER TABLE Outbox
+    ADD COLUMN IF NOT EXISTS Subscription_ID UUID;
+
+UPDATE Outbox
+SET Subscription_ID = NULLIF(Payload->'subscription'->>'id', '')::UUID
+WHERE Subscription_ID IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished_subscription
+    ON Outbox(Subscription_ID, ID) WHERE Published_at IS NULL;
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestGetServiceStatsMinGroupSize(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewMockAnalyticsRepo()
+	analyticsServ := service.NewAnalyticsService(repo, 5, logger.New(logger.Debug))
+
+	cases := []struct {
+		name      string
+		requested int
+		applied   int
+		services  int
+	}{
+		// The configured floor can not be lowered, so the niche service stays hidden
+		{"default", 0, 5, 2},
+		{"lowered", 1, 5, 2},
+		{"raised", 10, 10, 1},
+	}
+
+	for _, c := range cases {
+		filter := domain.AnalyticsFilter{Start: date(2025, time.January, 1), End: date(2025, time.June, 30), MinGroupSize: c.requested}
+		stats, err := analyticsServ.GetServiceStats(ctx, filter)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", c.name, err)
+		}
+		if repo.Filter.MinGroupSize != c.applied || len(stats) != c.services {
+			t.Errorf("%s: expected min group size %d with %d services, got %d with %d",
+				c.name, c.applied, c.services, repo.Filter.MinGroupSize, len(stats))
+		}
+	}
+
+	if repo.Filter.Limit != domain.DefaultAnalyticsLimit {
+		t.Errorf("Expected default limit %d, got %d", domain.DefaultAnalyticsLimit, repo.Filter.Limit)
+	}
+}
+
+func TestAnalyticsFilterValidation(t *testing.T) {
+	ctx := context.Background()
+	analyticsServ := service.NewAnalyticsService(mock.NewMockAnalyticsRepo(), 0, logger.New(logger.Debug))
+
+	cases := []struct {
+		name     string
+		filter   domain.AnalyticsFilter
+		expected error
+	}{
+		{"end before start", domain.AnalyticsFilter{Start: date(2025, time.June, 1), End: date(2025, time.May, 1)}, domain.ErrInvalidDate},
+		{"limit too high", domain.AnalyticsFilter{Start: date(2025, time.May, 1), End: date(2025, time.June, 1), Limit: 101}, domain.ErrInvalidAnalytics},
+		{"unknown bucket", domain.AnalyticsFilter{Start: date(2025, time.May, 1), End: date(2025, time.June, 1), Bucket: "quarter"}, domain.ErrInvalidBucket},
+	}
+
+	for _, c := range cases {
+		if _, err := analyticsServ.GetServiceUsers(ctx, c.filter); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestGetPriceStatsAndServiceUsers(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewMockAnalyticsRepo()
+	analyticsServ := service.NewAnalyticsService(repo, 0, logger.New(logger.Debug))
+	filter := domain.AnalyticsFilter{Start: date(2025, time.January, 1), End: date(2025, time.March, 31), Limit: 1}
+
+	prices, err := analyticsServ.GetPriceStats(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(prices) != 1 || prices[0].ServiceName != "Netflix" || prices[0].MedianPrice != 600 {
+		t.Errorf("Expected the Netflix median price 600, got %+v", prices)
+	}
+
+	users, err := analyticsServ.GetServiceUsers(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.Filter.Bucket != domain.PeriodMonth || repo.Filter.MinGroupSize != domain.DefaultMinGroupSize {
+		t.Errorf("Expected monthly buckets with the default group size, got %+v", repo.Filter)
+	}
+	if len(users) != 1 || users[0].Users != 12 {
+		t.Errorf("Expected 12 Netflix users, got %+v", users)
+	}
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestAuditMetaContext(t *testing.T) {
+	ctx := context.Background()
+	if meta := domain.AuditMetaFromContext(ctx); meta != (domain.AuditMeta{}) {
+		t.Errorf("Expected empty audit meta, got %+v", meta)
+	}
+
+	meta := domain.AuditMeta{Actor: "support", RequestID: "req-1"}
+	ctx = domain.WithAuditMeta(ctx, meta)
+	if got := domain.AuditMetaFromContext(ctx); got != meta {
+		t.Errorf("Expected audit meta %+v, got %+v", meta, got)
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	ctx := domain.WithAuditMeta(context.Background(), domain.AuditMeta{Actor: "support", RequestID: "req-1"})
+
+	filter := domain.AuditFilter{
+		UserID:     "user123",
+		Start:      date(2025, time.January, 1),
+		End:        date(2025, time.January, 31),
+		Pagination: domain.Pagination{PageNumber: 1, PageSize: 2},
+	}
+	auditLog, err := serv.GetAuditLog(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(auditLog.Events) != 2 {
+		t.Fatalf("Expected 2 audit events, got %d", len(auditLog.Events))
+	}
+	if event := auditLog.Events[0]; event.Actor != "support" || event.RequestID != "req-1" {
+		t.Errorf("Expected event by support in req-1, got %s in %s", event.Actor, event.RequestID)
+	}
+	if auditLog.PrevCursor != "" {
+		t.Errorf("Expected no previous cursor on the first page, got %s", auditLog.PrevCursor)
+	}
+
+	next, err := domain.DecodeCursor(auditLog.NextCursor)
+	if err != nil {
+		t.Fatalf("Expected valid next cursor, got %v", err)
+	}
+	if id, err := next.SeqID(); err != nil || id != 6 {
+		t.Errorf("Expected next cursor after event 6, got %s", next.ID)
+	}
+
+	// Audit cursors only point at sequential event IDs
+	crafted := domain.Cursor{StartDate: next.StartDate, ID: "6 OR 1=1"}
+	if _, err := crafted.SeqID(); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidCursor, err)
+	}
+
+	// Empty log is not an error
+	filter.UserID = "notexist"
+	auditLog, err = serv.GetAuditLog(ctx, filter)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if auditLog.Events == nil || len(auditLog.Events) != 0 {
+		t.Errorf("Expected empty audit events, got %v", auditLog.Events)
+	}
+
+	// Check if date range is reversed
+	filter.Start, filter.End = filter.End, filter.Start
+	if _, err := serv.GetAuditLog(ctx, filter); !errors.Is(err, domain.ErrInvalidDate) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidDate, err)
+	}
+}
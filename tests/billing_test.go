@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"submanager/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBillingPeriodShift(t *testing.T) {
+	start := date(2025, time.January, 31)
+
+	cases := []struct {
+		period   domain.BillingPeriod
+		n        int
+		expected time.Time
+	}{
+		{domain.BillingPeriod{Unit: domain.PeriodMonth, Interval: 1}, 1, date(2025, time.February, 28)},
+		{domain.BillingPeriod{Unit: domain.PeriodMonth, Interval: 1}, 2, date(2025, time.March, 31)},
+		{domain.BillingPeriod{Unit: domain.PeriodMonth, Interval: 3}, 1, date(2025, time.April, 30)},
+		{domain.BillingPeriod{Unit: domain.PeriodYear, Interval: 1}, 1, date(2026, time.January, 31)},
+		{domain.BillingPeriod{Unit: domain.PeriodWeek, Interval: 2}, 1, date(2025, time.February, 14)},
+		{domain.BillingPeriod{Unit: domain.PeriodDay, Interval: 45}, 2, date(2025, time.May, 1)},
+	}
+
+	for _, c := range cases {
+		if got := c.period.Shift(start, c.n); !got.Equal(c.expected) {
+			t.Errorf("Expected %v for %+v x%d, got %v", c.expected, c.period, c.n, got)
+		}
+	}
+
+	// Jan 31 01:00 in Moscow is Jan 30 in UTC, where months are clamped like billing_shift does
+	moscow := time.Date(2025, time.January, 31, 1, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	monthly := domain.BillingPeriod{Unit: domain.PeriodMonth, Interval: 1}
+	if got, expected := monthly.Shift(moscow, 1), time.Date(2025, time.February, 28, 22, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("Expected %v for a Moscow anchor, got %v", expected, got)
+	}
+}
+
+func TestNextRenewal(t *testing.T) {
+	subs := domain.Subscription{
+		StartDate:     date(2025, time.January, 10),
+		EndDate:       date(2026, time.January, 10),
+		BillingPeriod: domain.BillingPeriod{Unit: domain.PeriodMonth, Interval: 3},
+	}
+
+	if got := subs.NextRenewal(date(2025, time.May, 1)); !got.Equal(date(2025, time.July, 10)) {
+		t.Errorf("Expected %v, got %v", date(2025, time.July, 10), got)
+	}
+
+	// Check if subscription ends before the next renewal
+	if got := subs.NextRenewal(date(2026, time.January, 10)); !got.IsZero() {
+		t.Errorf("Expected zero renewal date, got %v", got)
+	}
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+// spendRepo reports fixed summary groups as the spend of every month.
+type spendRepo struct {
+	*mock.MockSubsRepo
+	groups []domain.SummaryGroup
+}
+
+func (repo *spendRepo) SummaryGroups(ctx context.Context, filter domain.SummaryFilter) ([]domain.SummaryGroup, error) {
+	return repo.groups, nil
+}
+
+func TestBudgetValidate(t *testing.T) {
+	budget := domain.Budget{UserID: "user123", Limit: 3000, Thresholds: []int{100, 50, 100}}.Normalize()
+	if err := budget.Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := []int{50, 100}; !slices.Equal(budget.Thresholds, expected) {
+		t.Errorf("Expected thresholds %v, got %v", expected, budget.Thresholds)
+	}
+
+	// Budgets without thresholds are alerted at the default ones
+	budget = domain.Budget{UserID: "user123", Limit: 3000}.Normalize()
+	if !slices.Equal(budget.Thresholds, domain.DefaultBudgetThresholds) {
+		t.Errorf("Expected default thresholds %v, got %v", domain.DefaultBudgetThresholds, budget.Thresholds)
+	}
+
+	invalid := []domain.Budget{
+		{Limit: 0},
+		{Limit: 3000, Category: "entertainment", ServiceName: "Netflix"},
+		{Limit: 3000, Thresholds: []int{0}},
+	}
+	for _, budget := range invalid {
+		if err := budget.Normalize().Validate(); !errors.Is(err, domain.ErrInvalidBudget) {
+			t.Errorf("Expected error %v for %+v, got %v", domain.ErrInvalidBudget, budget, err)
+		}
+	}
+}
+
+func TestEvaluateBudgets(t *testing.T) {
+	ctx := context.Background()
+	at := date(2025, time.May, 20)
+
+	subsRepo := &spendRepo{MockSubsRepo: mock.NewMockSubsRepo()}
+	budgetRepo := mock.NewMockBudgetRepo(
+		domain.Budget{ID: "total", UserID: "user123", Limit: 1000, Thresholds: []int{80, 100}},
+		domain.Budget{ID: "fun", UserID: "user123", Limit: 500, Category: "entertainment", Thresholds: []int{100}},
+	)
+	budgetServ := service.NewBudgetService(budgetRepo, subsRepo, logger.New(logger.Debug))
+
+	subsRepo.groups = []domain.SummaryGroup{
+		{Key: "entertainment", TotalPrice: 300},
+		{Key: "work", TotalPrice: 550},
+	}
+	if err := budgetServ.EvaluateBudgets(ctx, "user123", at); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Spend of 850 reaches 80% of the total budget, the category budget only counts 300
+	alerts, _ := budgetServ.GetAlerts(ctx, domain.AlertFilter{UserID: "user123"})
+	if len(alerts) != 1 || alerts[0].BudgetID != "total" || alerts[0].Threshold != 80 || alerts[0].Month != "2025-05" {
+		t.Fatalf("Expected a single 80%% alert of the total budget in 2025-05, got %+v", alerts)
+	}
+
+	// Thresholds are alerted once a month
+	subsRepo.groups = []domain.SummaryGroup{
+		{Key: "entertainment", TotalPrice: 500},
+		{Key: "work", TotalPrice: 550},
+	}
+	if err := budgetServ.EvaluateBudgets(ctx, "user123", at); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	alerts, _ = budgetServ.GetAlerts(ctx, domain.AlertFilter{UserID: "user123", Month: "2025-05"})
+	if len(alerts) != 3 {
+		t.Errorf("Expected 80%% and 100%% alerts of the total budget and a 100%% alert of the category budget, got %+v", alerts)
+	}
+
+	if _, err := budgetServ.GetAlerts(ctx, domain.AlertFilter{UserID: "user123", Month: "May"}); !errors.Is(err, domain.ErrInvalidMonth) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidMonth, err)
+	}
+}
+
+func TestBudgetScope(t *testing.T) {
+	ctx := context.Background()
+
+	subsRepo := &spendRepo{
+		MockSubsRepo: mock.NewMockSubsRepo(),
+		groups:       []domain.SummaryGroup{{Key: "entertainment", TotalPrice: 600}},
+	}
+	budgetServ := service.NewBudgetService(mock.NewMockBudgetRepo(), subsRepo, logger.New(logger.Debug),
+		service.WithBudgetCatalog(mock.NewMockCatalogRepo(yandexPlus)))
+
+	// Scopes are stored as the catalog names them, unknown ones as they are
+	tests := []struct {
+		budget   domain.Budget
+		expected domain.Budget
+	}{
+		{domain.Budget{ServiceName: "yandex plus"}, domain.Budget{ServiceName: "Yandex Plus"}},
+		{domain.Budget{ServiceName: "Яндекс Плюс"}, domain.Budget{ServiceName: "Yandex Plus"}},
+		{domain.Budget{Category: " Entertainment "}, domain.Budget{Category: "entertainment"}},
+		{domain.Budget{ServiceName: "Kinopoisk"}, domain.Budget{ServiceName: "Kinopoisk"}},
+	}
+	for _, test := range tests {
+		test.budget.UserID, test.budget.Limit = "user123", 500
+		id, err := budgetServ.CreateBudget(ctx, test.budget)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		budget, _ := budgetServ.GetBudget(ctx, id)
+		if budget.ServiceName != test.expected.ServiceName || budget.Category != test.expected.Category {
+			t.Errorf("Expected scope %q/%q, got %q/%q", test.expected.ServiceName, test.expected.Category,
+				budget.ServiceName, budget.Category)
+		}
+	}
+
+	// Categories stored before they were resolved still match ignoring case
+	budgetRepo := mock.NewMockBudgetRepo(domain.Budget{ID: "fun", UserID: "user123", Limit: 500, Category: "Entertainment", Thresholds: []int{100}})
+	budgetServ = service.NewBudgetService(budgetRepo, subsRepo, logger.New(logger.Debug))
+	if err := budgetServ.EvaluateBudgets(ctx, "user123", date(2025, time.May, 20)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if alerts, _ := budgetServ.GetAlerts(ctx, domain.AlertFilter{UserID: "user123"}); len(alerts) != 1 || alerts[0].Spend != 600 {
+		t.Errorf("Expected a single alert of the category budget with spend 600, got %+v", alerts)
+	}
+}
+
+func TestCreateSubscriptionEvaluatesBudgets(t *testing.T) {
+	ctx := context.Background()
+
+	subsRepo := &spendRepo{
+		MockSubsRepo: mock.NewMockSubsRepo(),
+		groups:       []domain.SummaryGroup{{Key: "2025-05", TotalPrice: 3100}},
+	}
+	budgetRepo := mock.NewMockBudgetRepo(domain.Budget{ID: "total", UserID: "user123", Limit: 3000, Thresholds: []int{100}})
+	budgetServ := service.NewBudgetService(budgetRepo, subsRepo, logger.New(logger.Debug))
+	subsServ := service.NewSubsService(subsRepo, logger.New(logger.Debug), service.WithBudgets(budgetServ))
+
+	_, err := subsServ.CreateSubscription(ctx, domain.Subscription{
+		ServiceName: "netflix",
+		UserID:      "user123",
+		StartDate:   time.Now(),
+		Price:       100,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	alerts, _ := budgetServ.GetAlerts(ctx, domain.AlertFilter{UserID: "user123"})
+	if len(alerts) != 1 || alerts[0].Spend != 3100 {
+		t.Errorf("Expected a single alert with spend 3100, got %+v", alerts)
+	}
+}
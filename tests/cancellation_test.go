@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestValidateCancellation(t *testing.T) {
+	cases := []struct {
+		name         string
+		cancellation domain.Cancellation
+		expected     error
+	}{
+		{"reason only", domain.Cancellation{Reason: domain.CancelTooExpensive}, nil},
+		{"reason with comment", domain.Cancellation{Reason: domain.CancelOther, Comment: "moving abroad"}, nil},
+		{"no reason", domain.Cancellation{Comment: "bye"}, domain.ErrInvalidCancellation},
+		{"unknown reason", domain.Cancellation{Reason: "bored"}, domain.ErrInvalidCancellation},
+		{"unspecified reason", domain.Cancellation{Reason: domain.CancelUnspecified}, domain.ErrInvalidCancellation},
+		{"long comment", domain.Cancellation{Reason: domain.CancelOther, Comment: strings.Repeat("a", domain.MaxCancelComment+1)}, domain.ErrInvalidCancellation},
+	}
+
+	for _, c := range cases {
+		if err := c.cancellation.Validate(); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestCancelSubscription(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewMockSubsRepo()
+	subsServ := service.NewSubsService(repo, logger.New(logger.Debug))
+
+	// Default test case
+	cancellation := domain.Cancellation{Reason: domain.CancelSwitchedService, Comment: "found a cheaper one"}
+	subs, err := subsServ.CancelSubscription(ctx, "TestService", "user123", cancellation)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if subs.Status != domain.StatusCancelled {
+		t.Errorf("Expected status %s, got %s", domain.StatusCancelled, subs.Status)
+	}
+
+	change := repo.Changed(subs.ID)
+	if change.Cancellation == nil || *change.Cancellation != cancellation {
+		t.Errorf("Expected cancellation %+v to be recorded, got %+v", cancellation, change.Cancellation)
+	}
+
+	// Check if subscription is already cancelled
+	_, err = subsServ.CancelSubscription(ctx, "TestService", "user123", cancellation)
+	if !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidTransition, err)
+	}
+
+	// A paused subscription can be cancelled without resuming it first
+	if _, err := subsServ.CancelSubscription(ctx, "paused", "user123", cancellation); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// Check if reason is invalid
+	if _, err := subsServ.CancelSubscription(ctx, "other", "user123", domain.Cancellation{}); err != domain.ErrInvalidCancellation {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidCancellation, err)
+	}
+
+	// Check if subscription not found
+	if _, err := subsServ.CancelSubscription(ctx, "notexist", "user123", cancellation); err != domain.ErrSubsNotFound {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+}
+
+func TestGetChurnReport(t *testing.T) {
+	ctx := context.Background()
+	start, end := date(2025, time.January, 1), date(2025, time.December, 31)
+
+	// Default test case, the bucket defaults to a month
+	report, err := serv.GetChurnReport(ctx, domain.ChurnFilter{Start: start, End: end})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(report))
+	}
+	if !report[0].End.Equal(date(2025, time.February, 1)) {
+		t.Errorf("Expected monthly buckets, got %v..%v", report[0].Start, report[0].End)
+	}
+	if report[0].Reasons[domain.CancelTooExpensive] != 2 || report[0].AvgLifetimeDays != 120 {
+		t.Errorf("Expected 2 too_expensive cancellations lasting 120 days, got %+v", report[0])
+	}
+
+	// Check if filtered by service
+	report, err = serv.GetChurnReport(ctx, domain.ChurnFilter{Start: start, End: end, ServiceName: "Spotify", Bucket: domain.PeriodYear})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 1 || report[0].Cancellations != 1 {
+		t.Errorf("Expected a single Spotify cancellation, got %+v", report)
+	}
+
+	// Check if window is invalid
+	if _, err := serv.GetChurnReport(ctx, domain.ChurnFilter{Start: end, End: start}); err != domain.ErrInvalidDate {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidDate, err)
+	}
+
+	// Check if bucket is invalid
+	if _, err := serv.GetChurnReport(ctx, domain.ChurnFilter{Start: start, End: end, Bucket: "hour"}); err != domain.ErrInvalidBucket {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidBucket, err)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+var yandexPlus = domain.Service{
+	ID:           "yandex-plus",
+	Name:         "Yandex Plus",
+	Aliases:      []string{"Яндекс Плюс"},
+	Category:     "entertainment",
+	DefaultPrice: 399,
+	Status:       domain.ServiceApproved,
+}
+
+func TestNormalizeServiceName(t *testing.T) {
+	for _, name := range []string{"Yandex Plus", "yandex plus", "YandexPlus", " yandex-plus "} {
+		if got := domain.NormalizeServiceName(name); got != "yandexplus" {
+			t.Errorf("Expected %q to be normalized to yandexplus, got %q", name, got)
+		}
+	}
+
+	if got := domain.NormalizeServiceName("Яндекс Плюс"); got != "яндексплюс" {
+		t.Errorf("Expected non-latin names to keep their letters, got %q", got)
+	}
+}
+
+func TestCreateSubscriptionResolvesCatalog(t *testing.T) {
+	ctx := context.Background()
+
+	subsRepo := mock.NewMockSubsRepo()
+	catalogRepo := mock.NewMockCatalogRepo(yandexPlus)
+	catalogServ := service.NewSubsService(subsRepo, logger.New(logger.Debug), service.WithCatalog(catalogRepo))
+
+	subs := domain.Subscription{
+		ServiceName: "yandexplus",
+		UserID:      "user123",
+		StartDate:   time.Now(),
+	}
+	if _, err := catalogServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	created := subsRepo.Created()
+	if created.ServiceID != yandexPlus.ID || created.ServiceName != yandexPlus.Name {
+		t.Errorf("Expected subscription linked to %s, got %s (%s)", yandexPlus.Name, created.ServiceName, created.ServiceID)
+	}
+	// Missing price is taken from the catalog
+	if created.Price != yandexPlus.DefaultPrice {
+		t.Errorf("Expected default price %d, got %d", yandexPlus.DefaultPrice, created.Price)
+	}
+
+	// Unknown services are accepted and added for review together with the subscription
+	subs.ServiceName, subs.Price = "Kinopoisk", 299
+	if _, err := catalogServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	created = subsRepo.Created()
+	if !created.PendingService || created.ServiceID != "" {
+		t.Errorf("Expected the unknown service to be added with the subscription, got %+v", created)
+	}
+
+	// Unknown service without a price has no default to fall back to
+	subs.ServiceName, subs.Price = "Unknown Music", 0
+	if _, err := catalogServ.CreateSubscription(ctx, subs); !errors.Is(err, domain.ErrPriceField) {
+		t.Errorf("Expected error %v, got %v", domain.ErrPriceField, err)
+	}
+
+	// Resolving names never writes to the catalog, so rejected subscriptions leave no services behind
+	services, _ := catalogRepo.ListServices(ctx, "")
+	if len(services) != 1 {
+		t.Errorf("Expected the catalog to hold only %s, got %+v", yandexPlus.Name, services)
+	}
+}
+
+func TestCatalogService(t *testing.T) {
+	ctx := context.Background()
+	catalogServ := service.NewCatalogService(mock.NewMockCatalogRepo(yandexPlus), logger.New(logger.Debug))
+
+	id, err := catalogServ.CreateService(ctx, domain.Service{Name: "Spotify", Category: "music", DefaultPrice: 199})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	created, err := catalogServ.GetService(ctx, id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.Status != domain.ServiceApproved {
+		t.Errorf("Expected status %s, got %s", domain.ServiceApproved, created.Status)
+	}
+
+	// Check if the name is an alias of another service
+	if _, err := catalogServ.CreateService(ctx, domain.Service{Name: "yandex plus"}); !errors.Is(err, domain.ErrAliasTaken) {
+		t.Errorf("Expected error %v, got %v", domain.ErrAliasTaken, err)
+	}
+
+	if _, err := catalogServ.CreateService(ctx, domain.Service{Name: " ", DefaultPrice: 100}); !errors.Is(err, domain.ErrInvalidService) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidService, err)
+	}
+
+	if _, err := catalogServ.ListServices(ctx, "unknown"); !errors.Is(err, domain.ErrInvalidServiceStatus) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidServiceStatus, err)
+	}
+
+	if err := catalogServ.DeleteService(ctx, "notexist"); !errors.Is(err, domain.ErrServiceNotFound) {
+		t.Errorf("Expected error %v, got %v", domain.ErrServiceNotFound, err)
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"strings"
+	"submanager/internal/app"
+	"testing"
+	"time"
+)
+
+// validConfig returns a configuration with the default worker and retry settings.
+func validConfig() app.Config {
+	var cfg app.Config
+	cfg.Purge = app.PurgeConfig{Retention: 720 * time.Hour, Interval: time.Hour}
+	cfg.Reminder = app.ReminderConfig{DaysBefore: 3, Interval: 10 * time.Minute, MaxAttempts: 5, RetryDelay: time.Minute}
+	cfg.Webhook = app.WebhookConfig{Interval: 30 * time.Second, MaxAttempts: 8, RetryDelay: 30 * time.Second}
+	cfg.Expiry = app.ExpiryConfig{Interval: time.Hour}
+	cfg.Outbox = app.OutboxConfig{Interval: 5 * time.Second, RetryDelay: 30 * time.Second}
+	return cfg
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(cfg *app.Config)
+	}{
+		{"PURGE_INTERVAL", func(cfg *app.Config) { cfg.Purge.Interval = 0 }},
+		{"PURGE_RETENTION", func(cfg *app.Config) { cfg.Purge.Retention = 0 }},
+		{"PURGE_RETENTION", func(cfg *app.Config) { cfg.Purge.Retention = -time.Hour }},
+		{"REMINDER_INTERVAL", func(cfg *app.Config) { cfg.Reminder.Interval = 0 }},
+		{"REMINDER_RETRY_DELAY", func(cfg *app.Config) { cfg.Reminder.RetryDelay = -time.Minute }},
+		{"REMINDER_DAYS_BEFORE", func(cfg *app.Config) { cfg.Reminder.DaysBefore = 0 }},
+		{"REMINDER_MAX_ATTEMPTS", func(cfg *app.Config) { cfg.Reminder.MaxAttempts = 0 }},
+		{"WEBHOOK_INTERVAL", func(cfg *app.Config) { cfg.Webhook.Interval = 0 }},
+		{"WEBHOOK_RETRY_DELAY", func(cfg *app.Config) { cfg.Webhook.RetryDelay = 0 }},
+		{"WEBHOOK_MAX_ATTEMPTS", func(cfg *app.Config) { cfg.Webhook.MaxAttempts = -1 }},
+		{"EXPIRY_INTERVAL", func(cfg *app.Config) { cfg.Expiry.Interval = 0 }},
+		{"OUTBOX_INTERVAL", func(cfg *app.Config) { cfg.Outbox.Interval = 0 }},
+		{"OUTBOX_RETRY_DELAY", func(cfg *app.Config) { cfg.Outbox.RetryDelay = 0 }},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		tt.modify(&cfg)
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.name) {
+			t.Errorf("Expected error about %s, got %v", tt.name, err)
+		}
+	}
+}
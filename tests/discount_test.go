@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestDiscountApply(t *testing.T) {
+	cases := []struct {
+		discount domain.Discount
+		expected int
+	}{
+		{domain.Discount{Kind: domain.DiscountPercent, Value: 25}, 225},
+		{domain.Discount{Kind: domain.DiscountPercent, Value: 100}, 0},
+		{domain.Discount{Kind: domain.DiscountAmount, Value: 100}, 200},
+		{domain.Discount{Kind: domain.DiscountAmount, Value: 500}, 0},
+		{domain.Discount{Kind: domain.DiscountPrice, Value: 99}, 99},
+	}
+
+	for _, c := range cases {
+		if got := c.discount.Apply(300); got != c.expected {
+			t.Errorf("Expected %d for %+v, got %d", c.expected, c.discount, got)
+		}
+	}
+}
+
+func TestValidateDiscounts(t *testing.T) {
+	cases := []struct {
+		name      string
+		discounts []domain.Discount
+		expected  error
+	}{
+		{"no discounts", nil, nil},
+		{"intro price", []domain.Discount{{Kind: domain.DiscountPrice, Value: 99, Cycles: 3}}, nil},
+		{"lasting discount last", []domain.Discount{
+			{Kind: domain.DiscountPrice, Value: 0, Cycles: 1},
+			{Kind: domain.DiscountPercent, Value: 10},
+		}, nil},
+		{"lasting discount first", []domain.Discount{
+			{Kind: domain.DiscountPercent, Value: 10},
+			{Kind: domain.DiscountPrice, Value: 0, Cycles: 1},
+		}, domain.ErrInvalidDiscount},
+		{"unknown kind", []domain.Discount{{Kind: "coupon", Value: 10, Cycles: 1}}, domain.ErrInvalidDiscount},
+		{"percent over 100", []domain.Discount{{Kind: domain.DiscountPercent, Value: 120, Cycles: 1}}, domain.ErrInvalidDiscount},
+		{"zero amount", []domain.Discount{{Kind: domain.DiscountAmount, Cycles: 1}}, domain.ErrInvalidDiscount},
+		{"negative cycles", []domain.Discount{{Kind: domain.DiscountPrice, Value: 99, Cycles: -1}}, domain.ErrInvalidDiscount},
+	}
+
+	for _, c := range cases {
+		if err := domain.ValidateDiscounts(c.discounts); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestEffectivePrice(t *testing.T) {
+	subs := mock.ProratedSubs
+	subs.Discounts = mock.ProratedDiscounts
+
+	cases := []struct {
+		at       time.Time
+		expected int
+	}{
+		{date(2025, time.January, 20), 100},
+		{date(2025, time.February, 15), 100},
+		{date(2025, time.March, 20), 150},
+		{date(2025, time.April, 15), 300},
+	}
+
+	for _, c := range cases {
+		if got := subs.EffectivePriceAt(c.at); got != c.expected {
+			t.Errorf("Expected effective price %d at %v, got %d", c.expected, c.at, got)
+		}
+	}
+}
+
+func TestGetSummaryByFilterDiscounts(t *testing.T) {
+	ctx := context.Background()
+
+	// Mar 1 - Apr 30 covers the half of the Feb 15 - Mar 15 cycle charged 100,
+	// the Mar 15 - Apr 15 cycle charged 150 and the half of the first undiscounted cycle
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.March, 1),
+		End:         date(2025, time.April, 30),
+		ServiceName: "discounted",
+		UserID:      "user123",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 100*14/28 + 150 + 300*16/30
+	cost := summary.Breakdown[0]
+	if cost.Amount != 360 || summary.PageTotal != 360 {
+		t.Errorf("Expected amount 360, got %d", cost.Amount)
+	}
+
+	// The list amount matches the undiscounted proration
+	if cost.ListAmount != 610 || cost.DiscountAmount != 250 {
+		t.Errorf("Expected list amount 610 with 250 discount, got %d with %d", cost.ListAmount, cost.DiscountAmount)
+	}
+}
+
+func TestSetSubscriptionDiscounts(t *testing.T) {
+	ctx := context.Background()
+	subsServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug))
+
+	subs, err := subsServ.SetSubscriptionDiscounts(ctx, "Netflix-id", mock.ProratedDiscounts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(subs.Discounts) != 2 || subs.EffectivePrice == nil {
+		t.Errorf("Expected discounts with the effective price, got %+v", subs)
+	}
+
+	invalid := []domain.Discount{{Kind: domain.DiscountPercent, Value: 0, Cycles: 1}}
+	if _, err := subsServ.SetSubscriptionDiscounts(ctx, "Netflix-id", invalid); !errors.Is(err, domain.ErrInvalidDiscount) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidDiscount, err)
+	}
+
+	if _, err := subsServ.SetSubscriptionDiscounts(ctx, "notexist-id", nil); !errors.Is(err, domain.ErrSubsNotFound) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+}
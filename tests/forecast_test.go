@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestProjectForecast(t *testing.T) {
+	yearly := domain.Subscription{
+		ID:            "yearly-id",
+		ServiceName:   "yearly",
+		Price:         1200,
+		StartDate:     date(2024, time.June, 1),
+		EndDate:       date(2025, time.June, 1),
+		AutoRenew:     true,
+		BillingPeriod: domain.BillingPeriod{Unit: domain.PeriodYear, Interval: 1},
+	}
+	ending := domain.Subscription{
+		ID:            "ending-id",
+		ServiceName:   "ending",
+		Price:         100,
+		StartDate:     date(2025, time.January, 25),
+		EndDate:       date(2025, time.June, 25),
+		BillingPeriod: domain.DefaultBillingPeriod,
+	}
+	trialEnd := date(2025, time.July, 10)
+	trial := domain.Subscription{
+		ID:            "trial-id",
+		ServiceName:   "trial",
+		Price:         200,
+		StartDate:     date(2025, time.June, 10),
+		EndDate:       date(2025, time.August, 10),
+		TrialEndDate:  &trialEnd,
+		TrialPrice:    50,
+		AutoRenew:     true,
+		BillingPeriod: domain.DefaultBillingPeriod,
+	}
+	monthly := mock.ProratedSubs
+	monthly.ID = "prorated-id"
+
+	// May 20 - Jul 31: the monthly subscription renews on Jun 15 and Jul 15, the auto-renewing yearly one on Jun 1,
+	// the ending one on May 25 only, and the trial is charged on Jun 10 before the first paid cycle on Jul 10
+	from := time.Date(2025, time.May, 20, 12, 0, 0, 0, time.UTC)
+	forecast := service.ProjectForecast([]domain.Subscription{monthly, yearly, ending, trial}, "", from, 3)
+
+	if !forecast.Until.Equal(date(2025, time.August, 1)) {
+		t.Errorf("Expected forecast until Aug 1, got %v", forecast.Until)
+	}
+	if len(forecast.Months) != 3 {
+		t.Fatalf("Expected 3 months, got %d", len(forecast.Months))
+	}
+
+	expected := []struct {
+		month      string
+		total      int
+		cumulative int
+		items      int
+	}{
+		{"2025-05", 100, 100, 1},
+		{"2025-06", 1550, 1650, 3},
+		{"2025-07", 500, 2150, 2},
+	}
+	for i, e := range expected {
+		month := forecast.Months[i]
+		if month.Month != e.month || month.Total != e.total || month.Cumulative != e.cumulative || len(month.Items) != e.items {
+			t.Errorf("Expected %+v, got %+v", e, month)
+		}
+	}
+	if forecast.Total != 2150 {
+		t.Errorf("Expected total 2150, got %d", forecast.Total)
+	}
+
+	item := forecast.Months[2].Items[1]
+	if item.ServiceName != "trial" || item.Charges != 1 || item.Amount != 200 {
+		t.Errorf("Expected the first paid cycle of the trial, got %+v", item)
+	}
+}
+
+func TestProjectForecastAutoRenew(t *testing.T) {
+	// Both subscriptions end on Jun 1, only the one with the derived end date renews
+	renewing := domain.Subscription{
+		ID:            "renewing-id",
+		ServiceName:   "kinopoisk",
+		Price:         300,
+		StartDate:     date(2025, time.May, 1),
+		EndDate:       date(2025, time.June, 1),
+		AutoRenew:     true,
+		BillingPeriod: domain.DefaultBillingPeriod,
+	}
+	ending := renewing
+	ending.ID, ending.Price, ending.AutoRenew = "ending-id", 100, false
+
+	forecast := service.ProjectForecast([]domain.Subscription{renewing, ending}, "", date(2025, time.May, 1), 3)
+
+	expected := []struct {
+		total   int
+		charges int
+	}{{400, 2}, {300, 1}, {300, 1}}
+	for i, e := range expected {
+		month := forecast.Months[i]
+		if month.Total != e.total || len(month.Items) != 1 {
+			t.Fatalf("Expected a single kinopoisk item of %d in month %d, got %+v", e.total, i, month)
+		}
+		if item := month.Items[0]; item.ServiceName != "kinopoisk" || item.Charges != e.charges {
+			t.Errorf("Expected %d charges of kinopoisk in month %d, got %+v", e.charges, i, item)
+		}
+	}
+}
+
+func TestRenewedEndDate(t *testing.T) {
+	subs := domain.Subscription{
+		StartDate:     date(2024, time.December, 31),
+		EndDate:       date(2025, time.January, 31),
+		AutoRenew:     true,
+		BillingPeriod: domain.DefaultBillingPeriod,
+	}
+
+	tests := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		{date(2025, time.January, 1), date(2025, time.January, 31)},
+		{date(2025, time.January, 31), date(2025, time.February, 28)},
+		{date(2025, time.March, 1), date(2025, time.March, 31)},
+	}
+	for _, test := range tests {
+		if got := subs.RenewedEndDate(test.now); !got.Equal(test.expected) {
+			t.Errorf("Expected the term running at %v to end on %v, got %v", test.now, test.expected, got)
+		}
+	}
+
+	// A term renewed to Feb 28 renews to the end of March, counted from the start date
+	renewed := subs
+	renewed.EndDate = date(2025, time.February, 28)
+	if got := renewed.RenewedEndDate(date(2025, time.February, 28)); !got.Equal(date(2025, time.March, 31)) {
+		t.Errorf("Expected the term to end on Mar 31, got %v", got)
+	}
+
+	// Unlike an ending one, an auto-renewing subscription renews past its end date
+	if renewal := subs.NextRenewal(date(2025, time.March, 1)); !renewal.Equal(date(2025, time.March, 31)) {
+		t.Errorf("Expected the next renewal on Mar 31, got %v", renewal)
+	}
+	subs.AutoRenew = false
+	if renewal := subs.NextRenewal(date(2025, time.March, 1)); !renewal.IsZero() {
+		t.Errorf("Expected no renewal after the end date, got %v", renewal)
+	}
+}
+
+func TestProjectForecastShareAndDiscounts(t *testing.T) {
+	subs := mock.ProratedSubs
+	subs.Participants = mock.ProratedParticipants
+	subs.Discounts = mock.ProratedDiscounts
+
+	// Feb 15 is the second cycle charged 100, Mar 15 the third one with 50 percent off,
+	// and the participant pays two thirds of every charge
+	from := date(2025, time.February, 1)
+	forecast := service.ProjectForecast([]domain.Subscription{subs}, "friend456", from, 2)
+
+	if forecast.Months[0].Total != 67 || forecast.Months[1].Total != 100 {
+		t.Errorf("Expected the participant share 67 and 100, got %d and %d",
+			forecast.Months[0].Total, forecast.Months[1].Total)
+	}
+}
+
+func TestGetForecast(t *testing.T) {
+	ctx := context.Background()
+
+	forecast, err := serv.GetForecast(ctx, "user123", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(forecast.Months) != domain.DefaultForecastMonths {
+		t.Errorf("Expected %d months by default, got %d", domain.DefaultForecastMonths, len(forecast.Months))
+	}
+
+	forecast, err = serv.GetForecast(ctx, "notexist", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(forecast.Months) != 3 || forecast.Total != 0 {
+		t.Errorf("Expected 3 empty months, got %+v", forecast)
+	}
+
+	for _, months := range []int{-1, domain.MaxForecastMonths + 1} {
+		if _, err := serv.GetForecast(ctx, "user123", months); !errors.Is(err, domain.ErrInvalidForecast) {
+			t.Errorf("Expected error %v for %d months, got %v", domain.ErrInvalidForecast, months, err)
+		}
+	}
+}
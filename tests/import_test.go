@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"submanager/internal/adapters/http/dto"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const importUser = "185925eb-2114-4c2a-bae7-6fdafa58d1d4"
+
+// importContext returns a request context uploading the CSV file with the column mapping.
+func importContext(t *testing.T, csv string, mapping map[string]string) *gin.Context {
+	t.Helper()
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	file, err := form.CreateFormFile("file", "subscriptions.csv")
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	file.Write([]byte(csv))
+	for field, column := range mapping {
+		form.WriteField("mapping["+field+"]", column)
+	}
+	form.Close()
+
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPost, "/subs/import", &buf)
+	ctx.Request.Header.Set("Content-Type", form.FormDataContentType())
+	return ctx
+}
+
+func TestGetImportCSV(t *testing.T) {
+	// Default test case, columns are matched by the field names
+	csv := "service_name,price,start_date,end_date,user_id\n" +
+		"Netflix,599,2025-01-01,,185925eb-2114-4c2a-bae7-6fdafa58d1d4\n" +
+		"Spotify,abc,2025-01-01,,185925eb-2114-4c2a-bae7-6fdafa58d1d4\n" +
+		"Yandex Plus,299\n"
+	rows, err := dto.GetImportCSV(importContext(t, csv, nil))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Err != nil || rows[0].Line != 2 || rows[0].Subscription.Price != 599 || !rows[0].Subscription.StartDate.Equal(date(2025, time.January, 1)) {
+		t.Errorf("Expected Netflix row parsed from line 2, got %+v", rows[0])
+	}
+	if rows[1].Err != domain.ErrPriceField {
+		t.Errorf("Expected error %v, got %v", domain.ErrPriceField, rows[1].Err)
+	}
+	if rows[2].Err == nil {
+		t.Errorf("Expected error for the short row, got %+v", rows[2])
+	}
+
+	// Check if columns are mapped
+	csv = "Service,Started,Customer\nNetflix,2025-02-01,185925eb-2114-4c2a-bae7-6fdafa58d1d4\n"
+	mapping := map[string]string{"service_name": "Service", "start_date": "Started", "user_id": "customer"}
+	rows, err = dto.GetImportCSV(importContext(t, csv, mapping))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rows[0].Err != nil || rows[0].Subscription.ServiceName != "Netflix" || rows[0].Subscription.UserID != importUser {
+		t.Errorf("Expected mapped Netflix row, got %+v", rows[0])
+	}
+
+	// Check if required column is missing
+	_, err = dto.GetImportCSV(importContext(t, "service_name,price\nNetflix,599\n", nil))
+	if !errors.Is(err, domain.ErrInvalidImport) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidImport, err)
+	}
+
+	// Check if mapping field is unknown
+	if _, err := dto.GetImportCSV(importContext(t, csv, map[string]string{"status": "Status"})); err == nil {
+		t.Errorf("Expected error for an unknown mapping field")
+	}
+}
+
+func TestImportSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	start := date(2025, time.January, 1)
+	rows := []domain.ImportRow{
+		{Line: 2, Subscription: domain.Subscription{ServiceName: "Netflix", Price: 599, UserID: importUser, StartDate: start}},
+		{Line: 3, Subscription: domain.Subscription{ServiceName: "notunique", Price: 299, UserID: importUser, StartDate: start}},
+		{Line: 4, Subscription: domain.Subscription{ServiceName: "Spotify", Price: 199, UserID: importUser, StartDate: start}},
+		{Line: 5, Subscription: domain.Subscription{ServiceName: "Netflix", Price: 599, UserID: importUser, StartDate: start}},
+		{Line: 6, Err: domain.ErrInvalidUserID},
+	}
+
+	// Default test case, valid rows are created one by one
+	repo := mock.NewMockSubsRepo()
+	subsServ := service.NewSubsService(repo, logger.New(logger.Debug))
+	report, err := subsServ.ImportSubscriptions(ctx, rows, domain.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Total != 5 || report.Created != 2 || report.Failed != 3 {
+		t.Errorf("Expected 2 created and 3 failed rows, got %+v", report)
+	}
+
+	expected := []domain.ImportRowStatus{domain.ImportCreated, domain.ImportFailed, domain.ImportCreated, domain.ImportFailed, domain.ImportFailed}
+	for i, result := range report.Rows {
+		if result.Status != expected[i] || result.Line != rows[i].Line {
+			t.Errorf("Expected line %d to be %s, got %+v", rows[i].Line, expected[i], result)
+		}
+	}
+	if report.Rows[3].Error != domain.ErrSubNotUnique.Error() || report.Rows[0].ID != "Netflix-id" {
+		t.Errorf("Expected the second Netflix row to be rejected as not unique, got %+v", report.Rows)
+	}
+
+	// Check if dry run creates nothing
+	repo = mock.NewMockSubsRepo()
+	subsServ = service.NewSubsService(repo, logger.New(logger.Debug))
+	report, err = subsServ.ImportSubscriptions(ctx, rows[:1], domain.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Created != 0 || report.Rows[0].Status != domain.ImportValid || repo.Created().ServiceName != "" {
+		t.Errorf("Expected a valid row without creating it, got %+v", report)
+	}
+
+	// Check if atomic import stores nothing when a row fails
+	report, err = subsServ.ImportSubscriptions(ctx, rows, domain.ImportOptions{Atomic: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Created != 0 || report.Failed != 3 || repo.Batch() != nil {
+		t.Errorf("Expected nothing to be created, got %+v", report)
+	}
+
+	// Check if atomic import stores all rows in a single batch
+	report, err = subsServ.ImportSubscriptions(ctx, []domain.ImportRow{rows[0], rows[2]}, domain.ImportOptions{Atomic: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Created != 2 || len(repo.Batch()) != 2 || report.Rows[1].ID != "Spotify-id" {
+		t.Errorf("Expected 2 rows created in a batch, got %+v", report)
+	}
+}
+
+func TestImportSubscriptionsCatalog(t *testing.T) {
+	ctx := context.Background()
+	catalogRepo := mock.NewMockCatalogRepo(yandexPlus)
+	subsServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug), service.WithCatalog(catalogRepo))
+
+	start := date(2025, time.January, 1)
+	rows := []domain.ImportRow{
+		{Line: 2, Subscription: domain.Subscription{ServiceName: "yandex plus", UserID: importUser, StartDate: start}},
+		{Line: 3, Subscription: domain.Subscription{ServiceName: "Kinopoisk", Price: 299, UserID: importUser, StartDate: start}},
+		{Line: 4, Subscription: domain.Subscription{ServiceName: "kinopoisk", Price: 299, UserID: importUser, StartDate: start}},
+	}
+
+	// Default test case, a dry run resolves names without adding unknown ones to the catalog
+	report, err := subsServ.ImportSubscriptions(ctx, rows[:2], domain.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Failed != 0 || report.Rows[0].ServiceName != yandexPlus.Name {
+		t.Errorf("Expected valid rows resolved against the catalog, got %+v", report)
+	}
+
+	// Check if rejected atomic import leaves the catalog untouched
+	report, err = subsServ.ImportSubscriptions(ctx, rows, domain.ImportOptions{Atomic: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Created != 0 || report.Rows[2].Error != domain.ErrSubNotUnique.Error() {
+		t.Errorf("Expected the second spelling of Kinopoisk to be rejected, got %+v", report.Rows)
+	}
+
+	services, _ := catalogRepo.ListServices(ctx, "")
+	if len(services) != 1 {
+		t.Errorf("Expected the catalog to hold only %s, got %+v", yandexPlus.Name, services)
+	}
+}
+
+func TestImportSubscriptionsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	catalogRepo := mock.NewMockCatalogRepo(yandexPlus)
+	subsServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug), service.WithCatalog(catalogRepo))
+
+	start := date(2025, time.January, 1)
+	rows := []domain.ImportRow{
+		{Line: 2, Subscription: domain.Subscription{ServiceName: "Yandex Plus", UserID: importUser, StartDate: start}},
+		{Line: 3, Subscription: domain.Subscription{ServiceName: "Яндекс Плюс", UserID: importUser, StartDate: start}},
+		{Line: 4, Subscription: domain.Subscription{ServiceName: "+++", Price: 100, UserID: importUser, StartDate: start}},
+		{Line: 5, Subscription: domain.Subscription{ServiceName: "---", Price: 100, UserID: importUser, StartDate: start}},
+	}
+
+	// Aliases of one catalog service are duplicates, names without letters or digits are not
+	report, err := subsServ.ImportSubscriptions(ctx, rows, domain.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Failed != 1 || report.Rows[1].Error != domain.ErrSubNotUnique.Error() {
+		t.Errorf("Expected only the alias of Yandex Plus to be rejected, got %+v", report.Rows)
+	}
+
+	// An atomic import reports the duplicate per row instead of failing the batch
+	report, err = subsServ.ImportSubscriptions(ctx, rows, domain.ImportOptions{Atomic: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Created != 0 || report.Rows[1].Status != domain.ImportFailed {
+		t.Errorf("Expected the atomic import to be rejected by the duplicate row, got %+v", report.Rows)
+	}
+}
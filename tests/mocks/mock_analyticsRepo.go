@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"context"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// AnalyticsServices are the per service aggregates returned by MockAnalyticsRepo, most users first.
+var AnalyticsServices = []domain.ServiceStats{
+	{ServiceName: "Netflix", Users: 12, Subscriptions: 13, TotalSpend: 7800},
+	{ServiceName: "Spotify", Users: 7, Subscriptions: 7, TotalSpend: 2100},
+	{ServiceName: "Niche", Users: 2, Subscriptions: 2, TotalSpend: 900},
+}
+
+// MockAnalyticsRepo drops the groups smaller than the requested minimum group size like the real queries
+// and keeps the last filter it was called with.
+type MockAnalyticsRepo struct {
+	Filter domain.AnalyticsFilter
+}
+
+func NewMockAnalyticsRepo() *MockAnalyticsRepo {
+	return &MockAnalyticsRepo{}
+}
+
+func (repo *MockAnalyticsRepo) ServiceStats(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.ServiceStats, error) {
+	repo.Filter = filter
+	var stats []domain.ServiceStats
+	for _, s := range AnalyticsServices {
+		if s.Users >= filter.MinGroupSize && len(stats) < filter.Limit {
+			stats = append(stats, s)
+		}
+	}
+	return stats, nil
+}
+func (repo *MockAnalyticsRepo) PriceStats(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.PriceStats, error) {
+	repo.Filter = filter
+	var stats []domain.PriceStats
+	for _, s := range AnalyticsServices {
+		if s.Users >= filter.MinGroupSize && len(stats) < filter.Limit {
+			avg := float64(s.TotalSpend) / float64(s.Subscriptions)
+			stats = append(stats, domain.PriceStats{
+				ServiceName:   s.ServiceName,
+				Users:         s.Users,
+				Subscriptions: s.Subscriptions,
+				AvgPrice:      avg,
+				MedianPrice:   avg,
+			})
+		}
+	}
+	return stats, nil
+}
+
+// ServiceUsers reports every service with all of its users in a single bucket covering the window.
+func (repo *MockAnalyticsRepo) ServiceUsers(ctx context.Context, filter domain.AnalyticsFilter) ([]domain.ServiceUsers, error) {
+	repo.Filter = filter
+	var points []domain.ServiceUsers
+	for _, s := range AnalyticsServices {
+		if s.Users >= filter.MinGroupSize && len(points) < filter.Limit {
+			points = append(points, domain.ServiceUsers{
+				ServiceName: s.ServiceName,
+				Start:       filter.Start,
+				End:         filter.End.Add(24 * time.Hour),
+				Users:       s.Users,
+			})
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,80 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+)
+
+// MockBudgetRepo keeps budgets and their alerts in memory.
+type MockBudgetRepo struct {
+	budgets map[string]domain.Budget
+	alerts  []domain.BudgetAlert
+}
+
+func NewMockBudgetRepo(budgets ...domain.Budget) *MockBudgetRepo {
+	repo := &MockBudgetRepo{
+		budgets: make(map[string]domain.Budget),
+	}
+	for _, budget := range budgets {
+		repo.budgets[budget.ID] = budget
+	}
+	return repo
+}
+
+func (repo *MockBudgetRepo) CreateBudget(ctx context.Context, budget domain.Budget) (string, error) {
+	budget.ID = fmt.Sprintf("budget-%d", len(repo.budgets)+1)
+	repo.budgets[budget.ID] = budget
+	return budget.ID, nil
+}
+func (repo *MockBudgetRepo) GetBudget(ctx context.Context, id string) (domain.Budget, error) {
+	budget, ok := repo.budgets[id]
+	if !ok {
+		return domain.Budget{}, domain.ErrBudgetNotFound
+	}
+	return budget, nil
+}
+func (repo *MockBudgetRepo) ListBudgets(ctx context.Context, userID string) ([]domain.Budget, error) {
+	var budgets []domain.Budget
+	for _, budget := range repo.budgets {
+		if budget.UserID == userID {
+			budgets = append(budgets, budget)
+		}
+	}
+	return budgets, nil
+}
+func (repo *MockBudgetRepo) UpdateBudget(ctx context.Context, budget domain.Budget) error {
+	stored, ok := repo.budgets[budget.ID]
+	if !ok {
+		return domain.ErrBudgetNotFound
+	}
+	budget.UserID = stored.UserID
+	repo.budgets[budget.ID] = budget
+	return nil
+}
+func (repo *MockBudgetRepo) DeleteBudget(ctx context.Context, id string) error {
+	if _, ok := repo.budgets[id]; !ok {
+		return domain.ErrBudgetNotFound
+	}
+	delete(repo.budgets, id)
+	return nil
+}
+func (repo *MockBudgetRepo) AddAlert(ctx context.Context, alert domain.BudgetAlert) (bool, error) {
+	for _, stored := range repo.alerts {
+		if stored.BudgetID == alert.BudgetID && stored.Threshold == alert.Threshold && stored.Month == alert.Month {
+			return false, nil
+		}
+	}
+	alert.ID = int64(len(repo.alerts) + 1)
+	repo.alerts = append(repo.alerts, alert)
+	return true, nil
+}
+func (repo *MockBudgetRepo) ListAlerts(ctx context.Context, filter domain.AlertFilter) ([]domain.BudgetAlert, error) {
+	var alerts []domain.BudgetAlert
+	for _, alert := range repo.alerts {
+		if alert.UserID == filter.UserID && (filter.Month == "" || alert.Month == filter.Month) {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,81 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"submanager/internal/core/domain"
+)
+
+// MockCatalogRepo keeps the catalog in memory, services are matched by normalized names and aliases.
+type MockCatalogRepo struct {
+	services map[string]domain.Service
+}
+
+func NewMockCatalogRepo(services ...domain.Service) *MockCatalogRepo {
+	repo := &MockCatalogRepo{
+		services: make(map[string]domain.Service),
+	}
+	for _, service := range services {
+		repo.services[service.ID] = service
+	}
+	return repo
+}
+
+func (repo *MockCatalogRepo) CreateService(ctx context.Context, service domain.Service) (string, error) {
+	for _, name := range append([]string{service.Name}, service.Aliases...) {
+		if _, ok := repo.find(name); ok {
+			return "", domain.ErrAliasTaken
+		}
+	}
+	service.ID = fmt.Sprintf("service-%d", len(repo.services)+1)
+	repo.services[service.ID] = service
+	return service.ID, nil
+}
+func (repo *MockCatalogRepo) GetService(ctx context.Context, id string) (domain.Service, error) {
+	service, ok := repo.services[id]
+	if !ok {
+		return domain.Service{}, domain.ErrServiceNotFound
+	}
+	return service, nil
+}
+func (repo *MockCatalogRepo) ListServices(ctx context.Context, status domain.ServiceStatus) ([]domain.Service, error) {
+	var services []domain.Service
+	for _, service := range repo.services {
+		if status == "" || service.Status == status {
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+func (repo *MockCatalogRepo) UpdateService(ctx context.Context, service domain.Service) error {
+	if _, ok := repo.services[service.ID]; !ok {
+		return domain.ErrServiceNotFound
+	}
+	repo.services[service.ID] = service
+	return nil
+}
+func (repo *MockCatalogRepo) DeleteService(ctx context.Context, id string) error {
+	if _, ok := repo.services[id]; !ok {
+		return domain.ErrServiceNotFound
+	}
+	delete(repo.services, id)
+	return nil
+}
+func (repo *MockCatalogRepo) FindService(ctx context.Context, name string) (domain.Service, error) {
+	if service, ok := repo.find(name); ok {
+		return service, nil
+	}
+	return domain.Service{}, domain.ErrServiceNotFound
+}
+
+func (repo *MockCatalogRepo) find(name string) (domain.Service, bool) {
+	normalized := domain.NormalizeServiceName(name)
+	for _, service := range repo.services {
+		for _, alias := range append([]string{service.Name}, service.Aliases...) {
+			if domain.NormalizeServiceName(alias) == normalized {
+				return service, true
+			}
+		}
+	}
+	return domain.Service{}, false
+}
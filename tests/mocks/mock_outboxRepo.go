@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"context"
+	"slices"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// OutboxRow is an outbox event kept by MockOutboxRepo with its publish state.
+type OutboxRow struct {
+	domain.OutboxEvent
+	NextAttemptAt time.Time
+	PublishedAt   *time.Time
+	LastError     string
+}
+
+// MockOutboxRepo keeps the outbox in memory, events are relayed in the order they were written.
+type MockOutboxRepo struct {
+	Rows []*OutboxRow
+}
+
+// NewMockOutboxRepo writes the events to the outbox.
+func NewMockOutboxRepo(events ...domain.Event) *MockOutboxRepo {
+	repo := &MockOutboxRepo{}
+	for i, event := range events {
+		repo.Rows = append(repo.Rows, &OutboxRow{OutboxEvent: domain.OutboxEvent{ID: int64(i + 1), Event: event}})
+	}
+	return repo
+}
+
+// ClaimEvents holds back events of subscriptions with an earlier event waiting for a retry, like OutboxRepo.ClaimEvents.
+func (repo *MockOutboxRepo) ClaimEvents(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	var claimed []domain.OutboxEvent
+	waiting := make(map[string]bool)
+	for _, row := range repo.Rows {
+		subsID := row.Event.Subscription.ID
+		if row.PublishedAt == nil && row.NextAttemptAt.After(now) {
+			waiting[subsID] = true
+			continue
+		}
+		if row.PublishedAt == nil && !waiting[subsID] && len(claimed) < limit {
+			row.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, row.OutboxEvent)
+		}
+	}
+	return claimed, nil
+}
+func (repo *MockOutboxRepo) MarkPublished(ctx context.Context, id int64, publishedAt time.Time) error {
+	row := repo.row(id)
+	row.PublishedAt = &publishedAt
+	row.Attempts++
+	return nil
+}
+func (repo *MockOutboxRepo) MarkPublishFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	row := repo.row(id)
+	row.Attempts++
+	row.LastError, row.NextAttemptAt = reason, nextAttempt
+	return nil
+}
+
+func (repo *MockOutboxRepo) row(id int64) *OutboxRow {
+	i := slices.IndexFunc(repo.Rows, func(row *OutboxRow) bool { return row.ID == id })
+	return repo.Rows[i]
+}
@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"context"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// MockReminderRepo keeps reminders in memory, Enqueue schedules the reminders it was created with.
+// Subscriptions holds the current state of reminded subscriptions, reminders of subscriptions
+// missing from it are treated as live.
+type MockReminderRepo struct {
+	upcoming      []domain.Reminder
+	Reminders     map[int64]*domain.Reminder
+	Subscriptions map[string]domain.Subscription
+}
+
+func NewMockReminderRepo(upcoming ...domain.Reminder) *MockReminderRepo {
+	return &MockReminderRepo{
+		upcoming:      upcoming,
+		Reminders:     make(map[int64]*domain.Reminder),
+		Subscriptions: make(map[string]domain.Subscription),
+	}
+}
+
+func (repo *MockReminderRepo) EnqueueReminders(ctx context.Context, from, until time.Time) (int64, error) {
+	var scheduled int64
+	for _, reminder := range repo.upcoming {
+		if _, ok := repo.Reminders[reminder.ID]; ok || !reminder.EndDate.After(from) || reminder.EndDate.After(until) {
+			continue
+		}
+		reminder.Status = domain.ReminderPending
+		repo.Reminders[reminder.ID] = &reminder
+		scheduled++
+	}
+	return scheduled, nil
+}
+func (repo *MockReminderRepo) ClaimReminders(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.Reminder, error) {
+	var claimed []domain.Reminder
+	for _, reminder := range repo.Reminders {
+		if reminder.Status == domain.ReminderPending && !reminder.NextAttemptAt.After(now) && len(claimed) < limit {
+			if subs, ok := repo.Subscriptions[reminder.SubsID]; ok && !endsOn(subs, reminder.EndDate) {
+				reminder.Status = domain.ReminderSkipped
+				continue
+			}
+			reminder.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, *reminder)
+		}
+	}
+	return claimed, nil
+}
+func (repo *MockReminderRepo) MarkReminderSent(ctx context.Context, id int64, sentAt time.Time) error {
+	reminder := repo.Reminders[id]
+	reminder.Status, reminder.SentAt = domain.ReminderSent, &sentAt
+	reminder.Attempts++
+	return nil
+}
+func (repo *MockReminderRepo) MarkReminderFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	reminder := repo.Reminders[id]
+	reminder.Attempts++
+	reminder.LastError = reason
+	if nextAttempt.IsZero() {
+		reminder.Status = domain.ReminderFailed
+	} else {
+		reminder.NextAttemptAt = nextAttempt
+	}
+	return nil
+}
+
+// endsOn tells whether the subscription is live and still ends on the reminded date, like ReminderRepo.ClaimReminders.
+func endsOn(subs domain.Subscription, endDate time.Time) bool {
+	live := subs.Status == domain.StatusActive || subs.Status == domain.StatusTrialing
+	return live && subs.EndDate.Equal(endDate)
+}
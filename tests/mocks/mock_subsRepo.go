@@ -2,37 +2,213 @@ package mock
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"submanager/internal/core/domain"
 	"time"
 )
 
+// ProratedSubs is a monthly subscription returned for the "prorated" service name.
+var ProratedSubs = domain.Subscription{
+	ServiceName:   "prorated",
+	Price:         300,
+	UserID:        "user123",
+	StartDate:     time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+	EndDate:       time.Date(2025, time.December, 15, 0, 0, 0, 0, time.UTC),
+	BillingPeriod: domain.DefaultBillingPeriod,
+}
+
+// ProratedPause pauses ProratedSubs for the whole Mar 15 - Apr 15 cycle.
+var ProratedPause = [2]time.Time{
+	time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+	time.Date(2025, time.April, 15, 0, 0, 0, 0, time.UTC),
+}
+
+// ProratedDiscounts charge ProratedSubs 100 for the first two cycles and take 50 percent off the third,
+// returned for the "discounted" service name.
+var ProratedDiscounts = []domain.Discount{
+	{Kind: domain.DiscountPrice, Value: 100, Cycles: 2},
+	{Kind: domain.DiscountPercent, Value: 50, Cycles: 1},
+}
+
+// ProratedTrialEnd ends a trial of ProratedSubs on Mar 1, returned for the "trial" service name.
+var ProratedTrialEnd = time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+// ProratedParticipants share ProratedSubs with a participant paying two thirds and a pending invitation,
+// returned for the "shared" service name.
+var ProratedParticipants = []domain.Participant{
+	{UserID: "friend456", Weight: 2, Status: domain.ShareAccepted},
+	{UserID: "invited789", Amount: 50, Status: domain.ShareInvited},
+}
+
 type MockSubsRepo struct {
+	// changes keeps the last status change made through ChangeStatus by subscription ID
+	changes map[string]domain.StatusChange
+	// updates keeps the last subscription stored through UpdateByID by subscription ID
+	updates map[string]domain.Subscription
+	// created keeps the last subscription stored through Create
+	created domain.Subscription
+	// trialHistory keeps the transitions out of trialing recorded through Create in order
+	trialHistory []domain.StatusChange
+	// batch keeps the subscriptions stored through the last CreateBatch
+	batch []domain.Subscription
+	// tags keeps the tags stored through SetTags by subscription ID
+	tags map[string][]string
+	// discounts keeps the discounts stored through SetDiscounts by subscription ID
+	discounts map[string][]domain.Discount
+	// participants keeps the participants stored through SetParticipants by subscription ID
+	participants map[string][]domain.Participant
+	// events keeps the events the changes would write to the outbox, in order
+	events []domain.Event
+	// ended keeps the subscriptions RenewEnded and ExpireEnded look at, ProratedSubs by default
+	ended []domain.Subscription
 }
 
 func NewMockSubsRepo() *MockSubsRepo {
-	return &MockSubsRepo{}
+	return &MockSubsRepo{
+		changes:      make(map[string]domain.StatusChange),
+		updates:      make(map[string]domain.Subscription),
+		tags:         make(map[string][]string),
+		discounts:    make(map[string][]domain.Discount),
+		participants: make(map[string][]domain.Participant),
+		ended:        []domain.Subscription{proratedActive()},
+	}
 }
 
-func (repo *MockSubsRepo) Create(ctx context.Context, subs domain.Subscription) error {
-	return nil
+// proratedActive returns ProratedSubs stored as an active subscription.
+func proratedActive() domain.Subscription {
+	subs := ProratedSubs
+	subs.ID, subs.Status = "prorated-id", domain.StatusActive
+	return subs
+}
+
+func (repo *MockSubsRepo) Create(ctx context.Context, subs domain.Subscription) (string, error) {
+	subs.ID = subs.ServiceName + "-id"
+	repo.created = subs
+	// Like createSubs, an ended trial records the conversion at the end of the trial
+	if subs.Status == domain.StatusActive && subs.TrialEndDate != nil {
+		repo.trialHistory = append(repo.trialHistory, domain.StatusChange{SubsID: subs.ID,
+			From: domain.StatusTrialing, To: domain.StatusActive, ChangedAt: *subs.TrialEndDate})
+	}
+	return subs.ID, repo.record(domain.EventSubsCreated, subs)
+}
+
+// Created returns the last subscription passed to Create.
+func (repo *MockSubsRepo) Created() domain.Subscription {
+	return repo.created
+}
+
+func (repo *MockSubsRepo) CreateBatch(ctx context.Context, subsList []domain.Subscription) ([]string, error) {
+	repo.batch = subsList
+	ids := make([]string, 0, len(subsList))
+	for _, subs := range subsList {
+		subs.ID = subs.ServiceName + "-id"
+		if err := repo.record(domain.EventSubsCreated, subs); err != nil {
+			return nil, err
+		}
+		ids = append(ids, subs.ID)
+	}
+	return ids, nil
+}
+
+// Batch returns the subscriptions passed to the last CreateBatch.
+func (repo *MockSubsRepo) Batch() []domain.Subscription {
+	return repo.batch
 }
 func (repo *MockSubsRepo) Delete(ctx context.Context, serviceName string, userID string) error {
 	if serviceName == "notexist" {
 		return domain.ErrSubsNotFound
 	}
-	return nil
+	return repo.record(domain.EventSubsDeleted, domain.Subscription{ID: serviceName + "-id", ServiceName: serviceName, UserID: userID})
 }
+
+// DeleteList deletes two subscriptions of the user.
 func (repo *MockSubsRepo) DeleteList(ctx context.Context, userID string) error {
 	if userID == "notexist" {
 		return domain.ErrSubsNotFound
 	}
+	for _, id := range []string{"first-id", "second-id"} {
+		if err := repo.record(domain.EventSubsDeleted, domain.Subscription{ID: id, UserID: userID}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+func (repo *MockSubsRepo) DeleteByID(ctx context.Context, id string) error {
+	if id == "notexist-id" {
+		return domain.ErrSubsNotFound
+	}
+	return repo.record(domain.EventSubsDeleted, domain.Subscription{ID: id})
+}
+func (repo *MockSubsRepo) Restore(ctx context.Context, serviceName string, userID string) (string, error) {
+	if serviceName == "notdeleted" {
+		return "", domain.ErrSubsNotFound
+	}
+	subs := domain.Subscription{ID: serviceName + "-id", ServiceName: serviceName, UserID: userID}
+	return subs.ID, repo.record(domain.EventSubsRestored, subs)
+}
+
+// Events returns the events the changes made so far would have written to the outbox.
+func (repo *MockSubsRepo) Events() []domain.Event {
+	return repo.events
+}
+
+// record keeps the event of a change, like SubsRepo writes it to the outbox in the transaction of the change.
+func (repo *MockSubsRepo) record(eventType domain.EventType, subs domain.Subscription) error {
+	event, err := domain.NewEvent(eventType, subs)
+	if err != nil {
+		return err
+	}
+	repo.events = append(repo.events, event)
+	return nil
+}
+func (repo *MockSubsRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if before.After(time.Now()) {
+		return 0, nil
+	}
+	return 2, nil
+}
+func (repo *MockSubsRepo) AuditEvents(ctx context.Context, filter domain.AuditFilter) (domain.AuditPage, error) {
+	if filter.UserID == "notexist" {
+		return domain.AuditPage{}, nil
+	}
+	meta := domain.AuditMetaFromContext(ctx)
+	return domain.AuditPage{
+		Events: []domain.AuditEvent{
+			{ID: 7, UserID: filter.UserID, Actor: meta.Actor, Operation: domain.AuditUpdate, RequestID: meta.RequestID},
+			{ID: 6, UserID: filter.UserID, Actor: meta.Actor, Operation: domain.AuditCreate, RequestID: meta.RequestID},
+		},
+		HasMore: true,
+	}, nil
+}
+func (repo *MockSubsRepo) GetByID(ctx context.Context, id string) (domain.Subscription, error) {
+	serviceName, ok := strings.CutSuffix(id, "-id")
+	if !ok || serviceName == "notexist" {
+		return domain.Subscription{}, domain.ErrSubsNotFound
+	}
+	subs := ProratedSubs
+	subs.ID, subs.ServiceName, subs.Status = id, serviceName, domain.StatusActive
+	if stored, ok := repo.updates[id]; ok {
+		subs = stored
+	}
+	subs.Tags, subs.Discounts, subs.Participants = repo.tags[id], repo.discounts[id], repo.participants[id]
+	return subs, nil
+}
 func (repo *MockSubsRepo) Get(ctx context.Context, serviceName string, userID string) (domain.Subscription, error) {
 	if serviceName == "notexist" {
 		return domain.Subscription{}, domain.ErrSubsNotFound
 	}
-	return domain.Subscription{}, nil
+	if serviceName == "ambiguous" {
+		return domain.Subscription{}, domain.ErrSubsAmbiguous
+	}
+	subs := domain.Subscription{ID: serviceName + "-id", ServiceName: serviceName, UserID: userID, Status: domain.StatusActive}
+	if serviceName == "paused" {
+		subs.Status = domain.StatusPaused
+	}
+	if change, ok := repo.changes[subs.ID]; ok {
+		subs.Status, subs.StatusChanged = change.To, change.ChangedAt
+	}
+	return subs, nil
 }
 func (repo *MockSubsRepo) IsUnique(ctx context.Context, serviceName string, userID string) (bool, error) {
 	if serviceName == "notunique" {
@@ -40,25 +216,365 @@ func (repo *MockSubsRepo) IsUnique(ctx context.Context, serviceName string, user
 	}
 	return true, nil
 }
-func (repo *MockSubsRepo) List(ctx context.Context, userID string) ([]domain.Subscription, error) {
-	if userID == "notexist" {
-		return []domain.Subscription{}, nil
+
+// List echoes the requested tags in the returned subscription.
+func (repo *MockSubsRepo) List(ctx context.Context, filter domain.ListFilter) (domain.SubsPage, error) {
+	if filter.UserID == "notexist" {
+		return domain.SubsPage{}, nil
 	}
-	return []domain.Subscription{
-		{},
+	return domain.SubsPage{
+		Subscriptions: []domain.Subscription{{ID: "185925eb-2114-4c2a-bae7-6fdafa58d1d4", Tags: filter.Tags}},
+		TotalItems:    2,
+		HasMore:       true,
 	}, nil
 }
-func (repo *MockSubsRepo) SubsListByFilter(ctx context.Context, start time.Time, end time.Time, serviceName, userID string, pageNum, pageSize int) ([]domain.Subscription, error) {
+func (repo *MockSubsRepo) SubsListByFilter(ctx context.Context, filter domain.SummaryFilter) (domain.SubsPage, error) {
+	serviceName := filter.ServiceName
 	if serviceName == "notexist" {
-		return []domain.Subscription{}, nil
+		return domain.SubsPage{}, nil
 	}
-	return []domain.Subscription{
-		{},
+	if serviceName == "prorated" {
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{ProratedSubs},
+			TotalItems:    1,
+			TotalPrice:    610,
+		}, nil
+	}
+	if serviceName == "discounted" {
+		subs := ProratedSubs
+		subs.Discounts = ProratedDiscounts
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{subs},
+			TotalItems:    1,
+			TotalPrice:    360,
+		}, nil
+	}
+	if serviceName == "shared" {
+		subs := ProratedSubs
+		subs.Participants = ProratedParticipants
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{subs},
+			TotalItems:    1,
+			TotalPrice:    203,
+		}, nil
+	}
+	if serviceName == "trial" {
+		subs := ProratedSubs
+		subs.TrialEndDate, subs.TrialPrice = &ProratedTrialEnd, 50
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{subs},
+			TotalItems:    1,
+			TotalPrice:    350,
+		}, nil
+	}
+	if serviceName == "repriced" {
+		subs := ProratedSubs
+		subs.PriceHistory = []domain.PricePoint{
+			{Price: 300, EffectiveFrom: ProratedSubs.StartDate},
+			{Price: 600, EffectiveFrom: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		}
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{subs},
+			TotalItems:    1,
+			TotalPrice:    770,
+		}, nil
+	}
+	if serviceName == "pausedprorated" {
+		subs := ProratedSubs
+		subs.Pauses = []domain.Pause{{PausedAt: ProratedPause[0], ResumedAt: &ProratedPause[1]}}
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{subs},
+			TotalItems:    1,
+			TotalPrice:    310,
+		}, nil
+	}
+	if serviceName == "moscow" {
+		// subscription_cost clamps the Jan 30 22:00 UTC anchor to a Jan 30 - Feb 28 cycle of 29 days
+		// and charges 300 / 29 for Feb 27
+		subs := ProratedSubs
+		subs.ServiceName, subs.EndDate = serviceName, time.Time{}
+		subs.StartDate = time.Date(2025, time.January, 31, 1, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+		return domain.SubsPage{
+			Subscriptions: []domain.Subscription{subs},
+			TotalItems:    1,
+			TotalPrice:    10,
+		}, nil
+	}
+	if filter.PageNumber > 1 {
+		// The only subscription is on the first page, later pages still report the totals
+		return domain.SubsPage{TotalItems: 1}, nil
+	}
+	return domain.SubsPage{
+		Subscriptions: []domain.Subscription{{}},
+		TotalItems:    1,
 	}, nil
 }
-func (repo *MockSubsRepo) Update(ctx context.Context, subs domain.Subscription) error {
-	if subs.ServiceName == "notexist" {
+
+// SummaryGroups returns a single group keyed like SubsRepo.SummaryGroups keys a subscription
+// tagged with the first filter tag, of the filter service and without a catalog category.
+func (repo *MockSubsRepo) SummaryGroups(ctx context.Context, filter domain.SummaryFilter) ([]domain.SummaryGroup, error) {
+	var key string
+	switch filter.GroupBy {
+	case domain.GroupByService:
+		key = filter.ServiceName
+	case domain.GroupByCategory:
+		key = "uncategorized"
+	case domain.GroupByTag:
+		key = "untagged"
+		if len(filter.Tags) != 0 {
+			key = filter.Tags[0]
+		}
+	case domain.GroupByMonth:
+		key = filter.Start.UTC().Format("2006-01")
+	default:
+		return nil, domain.ErrInvalidGroupBy
+	}
+	return []domain.SummaryGroup{{Key: key, TotalPrice: 100, TotalItems: 1}}, nil
+}
+
+// ListBillable returns ProratedSubs for every user except "notexist".
+func (repo *MockSubsRepo) ListBillable(ctx context.Context, userID string, from, until time.Time) ([]domain.Subscription, error) {
+	if userID == "notexist" {
+		return nil, nil
+	}
+	return []domain.Subscription{proratedActive()}, nil
+}
+
+// SpendSeries returns a point per bucket of the window costing 100 for each running subscription,
+// the "notexist" service has none.
+func (repo *MockSubsRepo) SpendSeries(ctx context.Context, filter domain.SpendSeriesFilter) ([]domain.SpendPoint, error) {
+	step := domain.BillingPeriod{Unit: filter.Bucket, Interval: 1}
+	until := filter.End.AddDate(0, 0, 1)
+
+	var points []domain.SpendPoint
+	for n := 0; step.Shift(filter.Start, n).Before(until); n++ {
+		point := domain.SpendPoint{Start: step.Shift(filter.Start, n), End: step.Shift(filter.Start, n+1)}
+		if point.End.After(until) {
+			point.End = until
+		}
+		if filter.ServiceName != "notexist" {
+			point.TotalPrice, point.TotalItems = 100, 1
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+func (repo *MockSubsRepo) SetTags(ctx context.Context, id string, tags []string) error {
+	if id == "notexist-id" {
+		return domain.ErrSubsNotFound
+	}
+	repo.tags[id] = tags
+	return nil
+}
+func (repo *MockSubsRepo) SetDiscounts(ctx context.Context, id string, discounts []domain.Discount) error {
+	if id == "notexist-id" {
 		return domain.ErrSubsNotFound
 	}
+	repo.discounts[id] = discounts
 	return nil
 }
+func (repo *MockSubsRepo) SetParticipants(ctx context.Context, id string, participants []domain.Participant) error {
+	if id == "notexist-id" {
+		return domain.ErrSubsNotFound
+	}
+	stored := make([]domain.Participant, 0, len(participants))
+	for _, p := range participants {
+		p.Status = domain.ShareInvited
+		for _, old := range repo.participants[id] {
+			if old.UserID == p.UserID && old.Status != domain.ShareDeclined {
+				p.Status = old.Status
+			}
+		}
+		stored = append(stored, p)
+	}
+	repo.participants[id] = stored
+	return nil
+}
+func (repo *MockSubsRepo) SetShareStatus(ctx context.Context, id, userID string, from, to domain.ShareStatus, at time.Time) error {
+	for i, p := range repo.participants[id] {
+		if p.UserID == userID && p.Status == from {
+			repo.participants[id][i].Status, repo.participants[id][i].RespondedAt = to, &at
+			return nil
+		}
+	}
+	return domain.ErrStatusConflict
+}
+
+// SharedWith returns subscriptions stored through SetParticipants which list the user.
+func (repo *MockSubsRepo) SharedWith(ctx context.Context, userID string) ([]domain.Subscription, error) {
+	var subsList []domain.Subscription
+	for id, participants := range repo.participants {
+		for _, p := range participants {
+			if p.UserID == userID {
+				subs, err := repo.GetByID(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				subsList = append(subsList, subs)
+			}
+		}
+	}
+	return subsList, nil
+}
+func (repo *MockSubsRepo) Update(ctx context.Context, subs domain.Subscription) (domain.Subscription, error) {
+	if subs.ServiceName == "notexist" {
+		return domain.Subscription{}, domain.ErrSubsNotFound
+	}
+	subs.ID = subs.ServiceName + "-id"
+	return subs, repo.record(domain.EventSubsUpdated, subs)
+}
+func (repo *MockSubsRepo) UpdateByID(ctx context.Context, subs domain.Subscription) (domain.Subscription, error) {
+	if subs.ID == "notexist-id" {
+		return domain.Subscription{}, domain.ErrSubsNotFound
+	}
+	// The owner and the service are not changed by the update
+	stored, err := repo.GetByID(ctx, subs.ID)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	subs.UserID, subs.ServiceName = stored.UserID, stored.ServiceName
+	repo.updates[subs.ID] = subs
+	return subs, repo.record(domain.EventSubsUpdated, subs)
+}
+func (repo *MockSubsRepo) ChangeStatus(ctx context.Context, change domain.StatusChange) error {
+	repo.changes[change.SubsID] = change
+	return repo.record(domain.EventSubsStatusChanged, domain.Subscription{ID: change.SubsID, Status: change.To, StatusChanged: change.ChangedAt})
+}
+
+// Changed returns the last status change passed to ChangeStatus for the subscription.
+func (repo *MockSubsRepo) Changed(subsID string) domain.StatusChange {
+	return repo.changes[subsID]
+}
+
+// SetEnded replaces the subscriptions RenewEnded and ExpireEnded look at.
+func (repo *MockSubsRepo) SetEnded(subsList ...domain.Subscription) {
+	repo.ended = subsList
+}
+
+// Ended returns the subscriptions RenewEnded and ExpireEnded look at in their current state.
+func (repo *MockSubsRepo) Ended() []domain.Subscription {
+	return repo.ended
+}
+
+// RenewEnded moves the end date of auto-renewing subscriptions which ended by now
+// and are neither expired nor cancelled, like SubsRepo.RenewEnded.
+func (repo *MockSubsRepo) RenewEnded(ctx context.Context, now time.Time) (int64, error) {
+	var renewed int64
+	for i, subs := range repo.ended {
+		if !subs.AutoRenew || !endedBy(subs, now) {
+			continue
+		}
+		repo.ended[i].EndDate = subs.RenewedEndDate(now)
+		if err := repo.record(domain.EventSubsRenewed, repo.ended[i]); err != nil {
+			return renewed, err
+		}
+		renewed++
+	}
+	return renewed, nil
+}
+
+// ExpireEnded expires subscriptions which ended by now, do not renew and are neither expired nor cancelled,
+// like SubsRepo.ExpireEnded.
+func (repo *MockSubsRepo) ExpireEnded(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	for i, subs := range repo.ended {
+		if subs.AutoRenew || !endedBy(subs, now) {
+			continue
+		}
+		repo.ended[i].Status, repo.ended[i].StatusChanged = domain.StatusExpired, now
+		if err := repo.record(domain.EventSubsExpired, repo.ended[i]); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// endedBy tells whether the live subscription ended by now.
+func endedBy(subs domain.Subscription, now time.Time) bool {
+	live := subs.Status != domain.StatusExpired && subs.Status != domain.StatusCancelled
+	return live && !subs.EndDate.IsZero() && !subs.EndDate.After(now)
+}
+
+// ConvertEndedTrials converts the last created subscription if its trial ended by now.
+func (repo *MockSubsRepo) ConvertEndedTrials(ctx context.Context, now time.Time) (int64, error) {
+	if repo.created.Status != domain.StatusTrialing || repo.created.BillingStart().After(now) {
+		return 0, nil
+	}
+	repo.created.Status = domain.StatusActive
+	return 1, nil
+}
+
+// TrialReport returns fixed trial outcomes of two services, filtered by the service name.
+// The service of the last created subscription with a trial is reported from that subscription
+// and its transitions out of trialing, like SubsRepo.TrialReport.
+func (repo *MockSubsRepo) TrialReport(ctx context.Context, filter domain.TrialReportFilter) ([]domain.TrialConversion, error) {
+	if created := repo.created; created.TrialEndDate != nil && created.ServiceName == filter.ServiceName {
+		return repo.createdTrialReport(), nil
+	}
+
+	report := []domain.TrialConversion{
+		{ServiceName: "Netflix", Trials: 10, Converted: 6, Cancelled: 1, Expired: 1, Trialing: 2},
+		{ServiceName: "Spotify", Trials: 3, Trialing: 3},
+	}
+	if filter.ServiceName == "" {
+		return report, nil
+	}
+
+	var filtered []domain.TrialConversion
+	for _, c := range report {
+		if c.ServiceName == filter.ServiceName {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+
+// createdTrialReport counts the trial of the last created subscription by the first transition out of trialing.
+// A subscription that has never been trialing is not counted.
+func (repo *MockSubsRepo) createdTrialReport() []domain.TrialConversion {
+	created := repo.created
+	conversion := domain.TrialConversion{ServiceName: created.ServiceName, Trials: 1}
+
+	i := slices.IndexFunc(repo.trialHistory, func(change domain.StatusChange) bool { return change.SubsID == created.ID })
+	switch {
+	case i >= 0 && repo.trialHistory[i].To == domain.StatusActive:
+		conversion.Converted = 1
+	case i >= 0 && repo.trialHistory[i].To == domain.StatusCancelled:
+		conversion.Cancelled = 1
+	case i >= 0 && repo.trialHistory[i].To == domain.StatusExpired:
+		conversion.Expired = 1
+	case i < 0 && created.Status == domain.StatusTrialing:
+		conversion.Trialing = 1
+	default:
+		return nil
+	}
+	return []domain.TrialConversion{conversion}
+}
+
+// ChurnReport returns a fixed churn of two services in the first bucket of the window, filtered by the service name.
+func (repo *MockSubsRepo) ChurnReport(ctx context.Context, filter domain.ChurnFilter) ([]domain.Churn, error) {
+	end := domain.BillingPeriod{Unit: filter.Bucket, Interval: 1}.Shift(filter.Start, 1)
+	report := []domain.Churn{
+		{ServiceName: "Netflix", Start: filter.Start, End: end, Cancellations: 3, AvgLifetimeDays: 120,
+			Reasons: map[domain.CancelReason]int{domain.CancelTooExpensive: 2, domain.CancelUnspecified: 1}},
+		{ServiceName: "Spotify", Start: filter.Start, End: end, Cancellations: 1, AvgLifetimeDays: 30,
+			Reasons: map[domain.CancelReason]int{domain.CancelNotUsing: 1}},
+	}
+	if filter.ServiceName == "" {
+		return report, nil
+	}
+
+	var filtered []domain.Churn
+	for _, c := range report {
+		if c.ServiceName == filter.ServiceName {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered, nil
+}
+func (repo *MockSubsRepo) StatusHistory(ctx context.Context, subsID string) ([]domain.StatusChange, error) {
+	return []domain.StatusChange{
+		{SubsID: subsID, From: domain.StatusActive, To: domain.StatusPaused},
+	}, nil
+}
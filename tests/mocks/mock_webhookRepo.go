@@ -0,0 +1,117 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"submanager/internal/core/domain"
+	"time"
+)
+
+// MockWebhookRepo keeps endpoints and deliveries in memory.
+type MockWebhookRepo struct {
+	endpoints  map[string]domain.WebhookEndpoint
+	Deliveries map[int64]*domain.WebhookDelivery
+}
+
+func NewMockWebhookRepo() *MockWebhookRepo {
+	return &MockWebhookRepo{
+		endpoints:  make(map[string]domain.WebhookEndpoint),
+		Deliveries: make(map[int64]*domain.WebhookDelivery),
+	}
+}
+
+func (repo *MockWebhookRepo) CreateEndpoint(ctx context.Context, endpoint domain.WebhookEndpoint) (string, error) {
+	endpoint.ID = fmt.Sprintf("endpoint-%d", len(repo.endpoints)+1)
+	repo.endpoints[endpoint.ID] = endpoint
+	return endpoint.ID, nil
+}
+func (repo *MockWebhookRepo) GetEndpoint(ctx context.Context, id string) (domain.WebhookEndpoint, error) {
+	endpoint, ok := repo.endpoints[id]
+	if !ok {
+		return domain.WebhookEndpoint{}, domain.ErrWebhookNotFound
+	}
+	return endpoint, nil
+}
+func (repo *MockWebhookRepo) ListEndpoints(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	var endpoints []domain.WebhookEndpoint
+	for _, endpoint := range repo.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
+func (repo *MockWebhookRepo) DeleteEndpoint(ctx context.Context, id string) error {
+	if _, ok := repo.endpoints[id]; !ok {
+		return domain.ErrWebhookNotFound
+	}
+	delete(repo.endpoints, id)
+	return nil
+}
+func (repo *MockWebhookRepo) EnqueueDeliveries(ctx context.Context, event domain.Event, payload []byte) (int64, error) {
+	var scheduled int64
+	for _, endpoint := range repo.endpoints {
+		if !endpoint.Accepts(event.Type) {
+			continue
+		}
+		id := int64(len(repo.Deliveries) + 1)
+		repo.Deliveries[id] = &domain.WebhookDelivery{
+			ID:         id,
+			EndpointID: endpoint.ID,
+			EventID:    event.ID,
+			EventType:  event.Type,
+			Payload:    payload,
+			Status:     domain.DeliveryPending,
+		}
+		scheduled++
+	}
+	return scheduled, nil
+}
+func (repo *MockWebhookRepo) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var claimed []domain.WebhookDelivery
+	for _, delivery := range repo.Deliveries {
+		if delivery.Status == domain.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(claimed) < limit {
+			delivery.NextAttemptAt = now.Add(lease)
+			endpoint := repo.endpoints[delivery.EndpointID]
+			delivery.URL, delivery.Secret = endpoint.URL, endpoint.Secret
+			claimed = append(claimed, *delivery)
+		}
+	}
+	return claimed, nil
+}
+func (repo *MockWebhookRepo) MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	delivery := repo.Deliveries[id]
+	delivery.Status, delivery.DeliveredAt = domain.DeliveryDelivered, &deliveredAt
+	delivery.Attempts++
+	return nil
+}
+func (repo *MockWebhookRepo) MarkDeliveryFailed(ctx context.Context, id int64, reason string, nextAttempt time.Time) error {
+	delivery := repo.Deliveries[id]
+	delivery.Attempts++
+	delivery.LastError = reason
+	if nextAttempt.IsZero() {
+		delivery.Status = domain.DeliveryDead
+	} else {
+		delivery.NextAttemptAt = nextAttempt
+	}
+	return nil
+}
+func (repo *MockWebhookRepo) ListDeliveries(ctx context.Context, status domain.DeliveryStatus) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range repo.Deliveries {
+		if delivery.Status == status {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	slices.SortFunc(deliveries, func(a, b domain.WebhookDelivery) int { return int(b.ID - a.ID) })
+	return deliveries, nil
+}
+func (repo *MockWebhookRepo) Redeliver(ctx context.Context, id int64) error {
+	delivery, ok := repo.Deliveries[id]
+	if !ok {
+		return domain.ErrDeliveryNotFound
+	}
+	if delivery.Status != domain.DeliveryPending {
+		delivery.Status, delivery.Attempts, delivery.NextAttemptAt = domain.DeliveryPending, 0, time.Now()
+	}
+	return nil
+}
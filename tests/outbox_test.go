@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"submanager/internal/adapters/publish"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+// flakyPublisher fails the first failures events and records the published ones.
+type flakyPublisher struct {
+	failures  int
+	published []domain.Event
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, event domain.Event) error {
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("broker is unavailable")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func newEvent(t *testing.T, eventType domain.EventType, subs domain.Subscription) domain.Event {
+	t.Helper()
+	event, err := domain.NewEvent(eventType, subs)
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	return event
+}
+
+func TestSubsEvents(t *testing.T) {
+	ctx := context.Background()
+	subsRepo := mock.NewMockSubsRepo()
+	subsServ := service.NewSubsService(subsRepo, logger.New(logger.Debug))
+
+	subs := domain.Subscription{ServiceName: "Netflix", UserID: "user123", StartDate: time.Now(), Price: 300}
+	if _, err := subsServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	subs.Price = 400
+	if err := subsServ.UpdateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := subsServ.PauseSubscription(ctx, "Netflix", "user123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := subsServ.DeleteSubscriptionByID(ctx, "Netflix-id"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := subsServ.DeleteSubscriptionList(ctx, "user123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := []domain.EventType{domain.EventSubsCreated, domain.EventSubsUpdated, domain.EventSubsStatusChanged,
+		domain.EventSubsDeleted, domain.EventSubsDeleted, domain.EventSubsDeleted}
+	events := subsRepo.Events()
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, event := range events {
+		if event.Type != want[i] || event.ID == "" {
+			t.Errorf("Expected event %d to be %s, got %+v", i, want[i], event)
+		}
+	}
+	if created := events[0].Subscription; created.ID != "Netflix-id" || created.Status != domain.StatusActive {
+		t.Errorf("Expected the created subscription in the event, got %+v", created)
+	}
+	if updated := events[1].Subscription; updated.Price != 400 {
+		t.Errorf("Expected the updated subscription in the event, got %+v", updated)
+	}
+	if paused := events[2].Subscription; paused.Status != domain.StatusPaused {
+		t.Errorf("Expected the paused status in the event, got %+v", paused)
+	}
+
+	// Failed changes write nothing
+	if err := subsServ.DeleteSubscription(ctx, "notexist", "user123"); err == nil {
+		t.Fatalf("Expected error for notexist subscription")
+	}
+	if len(subsRepo.Events()) != len(want) {
+		t.Errorf("Expected no further events, got %+v", subsRepo.Events()[len(want):])
+	}
+}
+
+func TestRelayEvents(t *testing.T) {
+	ctx := context.Background()
+	outboxRepo := mock.NewMockOutboxRepo(
+		newEvent(t, domain.EventSubsCreated, domain.Subscription{ID: "netflix-id"}),
+		newEvent(t, domain.EventSubsUpdated, domain.Subscription{ID: "netflix-id"}),
+		newEvent(t, domain.EventSubsCreated, domain.Subscription{ID: "spotify-id"}),
+	)
+	publisher := &flakyPublisher{failures: 1}
+	relay := service.NewOutboxRelay(outboxRepo, publisher, time.Minute, logger.New(logger.Debug))
+
+	published, err := relay.RelayEvents(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// The failed event holds back the later event of the same subscription only
+	if published != 1 || publisher.published[0].Subscription.ID != "spotify-id" {
+		t.Fatalf("Expected only the event of spotify-id to be published, got %d: %+v", published, publisher.published)
+	}
+
+	failed, held := outboxRepo.Rows[0], outboxRepo.Rows[1]
+	if failed.PublishedAt != nil || failed.LastError == "" || !failed.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("Expected the failed event to wait for a retry, got %+v", failed)
+	}
+	if held.PublishedAt != nil || held.Attempts != 0 {
+		t.Fatalf("Expected the later event not to be attempted, got %+v", held)
+	}
+
+	// Published events are not relayed again, the held back one waits for the failed one
+	held.NextAttemptAt = time.Now()
+	if published, _ := relay.RelayEvents(ctx); published != 0 {
+		t.Errorf("Expected nothing due before the retry, got %d", published)
+	}
+
+	failed.NextAttemptAt, held.NextAttemptAt = time.Now(), time.Now()
+	if published, _ := relay.RelayEvents(ctx); published != 2 || failed.PublishedAt == nil || failed.Attempts != 2 {
+		t.Fatalf("Expected the retry to publish both events, got %+v", failed)
+	}
+	if types := []domain.EventType{publisher.published[1].Type, publisher.published[2].Type}; types[0] != domain.EventSubsCreated || types[1] != domain.EventSubsUpdated {
+		t.Errorf("Expected the events of netflix-id in order, got %v", types)
+	}
+}
+
+func TestPublishers(t *testing.T) {
+	ctx := context.Background()
+	event := newEvent(t, domain.EventSubsDeleted, domain.Subscription{ID: "netflix-id"})
+
+	channel := publish.NewChannelPublisher(1)
+	var buf bytes.Buffer
+	fanout := publish.Fanout{channel, publish.NewWriterPublisher(&buf)}
+
+	if err := fanout.Publish(ctx, event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if received := <-channel.Events(); received.ID != event.ID {
+		t.Errorf("Expected the event on the channel, got %+v", received)
+	}
+
+	var written domain.Event
+	if err := json.Unmarshal(buf.Bytes(), &written); err != nil || written.ID != event.ID || written.Type != event.Type {
+		t.Errorf("Expected the event as a JSON line, got %q", buf.String())
+	}
+
+	// A full channel gives up with the context, and the fanout reports the failure
+	channel.Publish(ctx, event)
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := fanout.Publish(timeout, event); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
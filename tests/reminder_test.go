@@ -0,0 +1,301 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"submanager/internal/adapters/notify"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+// failingNotifier fails the first failures deliveries and records the delivered notifications.
+type failingNotifier struct {
+	failures  int
+	delivered []domain.Notification
+}
+
+func (n *failingNotifier) Notify(ctx context.Context, notification domain.Notification) error {
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("channel is unavailable")
+	}
+	n.delivered = append(n.delivered, notification)
+	return nil
+}
+
+var reminderPolicy = service.ReminderPolicy{DaysBefore: 3, MaxAttempts: 2, RetryDelay: time.Minute}
+
+func TestSendReminders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	reminderRepo := mock.NewMockReminderRepo(
+		domain.Reminder{ID: 1, SubsID: "soon-id", ServiceName: "Netflix", EndDate: now.AddDate(0, 0, 2)},
+		domain.Reminder{ID: 2, SubsID: "later-id", ServiceName: "Spotify", EndDate: now.AddDate(0, 0, 10)},
+	)
+	notifier := &failingNotifier{}
+	reminderServ := service.NewReminderService(reminderRepo, notifier, reminderPolicy, logger.New(logger.Debug))
+
+	sent, err := reminderServ.SendReminders(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Only subscriptions ending within the reminder window are reminded of
+	if sent != 1 || len(notifier.delivered) != 1 || notifier.delivered[0].SubsID != "soon-id" {
+		t.Fatalf("Expected a single reminder of soon-id, got %d: %+v", sent, notifier.delivered)
+	}
+	if !strings.Contains(notifier.delivered[0].Subject, "Netflix") {
+		t.Errorf("Expected subject to name the service, got %q", notifier.delivered[0].Subject)
+	}
+
+	// Each end date is reminded of once
+	if sent, _ := reminderServ.SendReminders(ctx); sent != 0 {
+		t.Errorf("Expected no reminders on the second run, got %d", sent)
+	}
+}
+
+func TestSendRemindersRetry(t *testing.T) {
+	ctx := context.Background()
+
+	reminderRepo := mock.NewMockReminderRepo(
+		domain.Reminder{ID: 1, SubsID: "soon-id", EndDate: time.Now().AddDate(0, 0, 1)},
+	)
+	notifier := &failingNotifier{failures: 2}
+	reminderServ := service.NewReminderService(reminderRepo, notifier, reminderPolicy, logger.New(logger.Debug))
+
+	if sent, err := reminderServ.SendReminders(ctx); err != nil || sent != 0 {
+		t.Fatalf("Expected a failed delivery, got %d sent, %v", sent, err)
+	}
+
+	reminder := reminderRepo.Reminders[1]
+	if reminder.Status != domain.ReminderPending || reminder.Attempts != 1 || reminder.LastError == "" {
+		t.Fatalf("Expected a pending reminder with a recorded attempt, got %+v", reminder)
+	}
+	if !reminder.NextAttemptAt.After(time.Now()) {
+		t.Errorf("Expected the retry to be delayed, got %v", reminder.NextAttemptAt)
+	}
+
+	// The retry is due, and the last allowed attempt fails as well
+	reminder.NextAttemptAt = time.Now()
+	if _, err := reminderServ.SendReminders(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reminder.Status != domain.ReminderFailed || reminder.Attempts != 2 {
+		t.Errorf("Expected the reminder to be given up after 2 attempts, got %+v", reminder)
+	}
+}
+
+func TestSendRemindersBackoffCap(t *testing.T) {
+	ctx := context.Background()
+	policy := service.ReminderPolicy{DaysBefore: 3, MaxAttempts: math.MaxInt, RetryDelay: time.Minute}
+
+	tests := []struct {
+		name     string
+		attempts int
+		maxDelay time.Duration
+	}{
+		{"first retry", 0, time.Minute},
+		{"doubled", 3, 8 * time.Minute},
+		{"capped", 20, 24 * time.Hour},
+		{"shift overflow", 64, 24 * time.Hour},
+		{"huge attempts", 1 << 40, 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminderRepo := mock.NewMockReminderRepo(
+				domain.Reminder{ID: 1, SubsID: "soon-id", EndDate: time.Now().AddDate(0, 0, 1), Attempts: tt.attempts},
+			)
+			reminderServ := service.NewReminderService(reminderRepo, &failingNotifier{failures: 1}, policy, logger.New(logger.Debug))
+
+			before := time.Now()
+			if _, err := reminderServ.SendReminders(ctx); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			delay := reminderRepo.Reminders[1].NextAttemptAt.Sub(before)
+			if delay <= 0 || delay > tt.maxDelay+time.Second {
+				t.Errorf("Expected a retry within %v, got %v", tt.maxDelay, delay)
+			}
+		})
+	}
+}
+
+func TestSendRemindersSkipsCancelled(t *testing.T) {
+	ctx := context.Background()
+	endDate := time.Now().AddDate(0, 0, 2)
+
+	reminderRepo := mock.NewMockReminderRepo(
+		domain.Reminder{ID: 1, SubsID: "cancelled-id", ServiceName: "Netflix", EndDate: endDate},
+		domain.Reminder{ID: 2, SubsID: "renewed-id", ServiceName: "Spotify", EndDate: endDate},
+		domain.Reminder{ID: 3, SubsID: "active-id", ServiceName: "Kinopoisk", EndDate: endDate},
+	)
+	// The reminders were scheduled before the first subscription was cancelled and the second renewed
+	reminderRepo.Subscriptions["cancelled-id"] = domain.Subscription{Status: domain.StatusCancelled, EndDate: endDate}
+	reminderRepo.Subscriptions["renewed-id"] = domain.Subscription{Status: domain.StatusActive, EndDate: endDate.AddDate(0, 1, 0)}
+	reminderRepo.Subscriptions["active-id"] = domain.Subscription{Status: domain.StatusActive, EndDate: endDate}
+
+	notifier := &failingNotifier{}
+	reminderServ := service.NewReminderService(reminderRepo, notifier, reminderPolicy, logger.New(logger.Debug))
+
+	sent, err := reminderServ.SendReminders(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent != 1 || len(notifier.delivered) != 1 || notifier.delivered[0].SubsID != "active-id" {
+		t.Fatalf("Expected a single reminder of active-id, got %d: %+v", sent, notifier.delivered)
+	}
+
+	for _, id := range []int64{1, 2} {
+		if status := reminderRepo.Reminders[id].Status; status != domain.ReminderSkipped {
+			t.Errorf("Expected reminder %d to be skipped, got %s", id, status)
+		}
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received domain.Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	notification := domain.Reminder{SubsID: "soon-id", UserID: "user123", ServiceName: "Netflix"}.Notification()
+	if err := notify.NewWebhookNotifier(server.URL).Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received.Kind != domain.NotificationRenewalReminder || received.SubsID != "soon-id" {
+		t.Errorf("Expected the reminder to be posted, got %+v", received)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	if err := notify.NewWebhookNotifier(failing.URL).Notify(context.Background(), notification); err == nil {
+		t.Errorf("Expected error on a non-2xx response")
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start SMTP stand-in: %v", err)
+	}
+	defer listener.Close()
+
+	messages := make(chan string, 1)
+	go serveSMTP(listener, messages)
+
+	smtpNotifier := notify.NewSMTPNotifier(notify.SMTPConfig{
+		Addr:  listener.Addr().String(),
+		From:  "submanager@localhost",
+		Inbox: "operator@localhost",
+	})
+	notification := domain.Reminder{SubsID: "soon-id", UserID: "user123", ServiceName: "Netflix"}.Notification()
+	if err := smtpNotifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case message := <-messages:
+		if !strings.Contains(message, "Subject: "+notification.Subject) || !strings.Contains(message, notification.Text) {
+			t.Errorf("Expected the reminder email, got %q", message)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the SMTP stand-in to receive a message")
+	}
+}
+
+func TestSMTPNotifierTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start SMTP stand-in: %v", err)
+	}
+	defer listener.Close()
+
+	// The server accepts the connection but never greets
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	smtpNotifier := notify.NewSMTPNotifier(notify.SMTPConfig{
+		Addr:    listener.Addr().String(),
+		From:    "submanager@localhost",
+		Inbox:   "operator@localhost",
+		Timeout: 50 * time.Millisecond,
+	})
+	notification := domain.Reminder{SubsID: "soon-id", UserID: "user123", ServiceName: "Netflix"}.Notification()
+
+	start := time.Now()
+	if err := smtpNotifier.Notify(context.Background(), notification); err == nil {
+		t.Errorf("Expected error from a hung SMTP server")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the delivery to give up after the timeout, took %v", elapsed)
+	}
+}
+
+// serveSMTP accepts a single SMTP session and sends the received message data to messages.
+func serveSMTP(listener net.Listener, messages chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost SMTP stand-in")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			messages <- data.String()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
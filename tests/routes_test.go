@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"submanager/internal/adapters/http/routers"
+	"submanager/internal/core/domain"
+	"submanager/internal/pkg/logger"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSubsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	routers.NewSubsHandler(serv, logger.New(logger.Debug)).RegisterSubsRoutes(engine.Group("/subs"))
+
+	const userID = "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+
+	// Services named like the views of a user are still addressable
+	for _, serviceName := range []string{"audit", "shared", "forecast"} {
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/subs/"+userID+"/"+serviceName, nil))
+
+		var subs domain.Subscription
+		if err := json.Unmarshal(rec.Body.Bytes(), &subs); err != nil || rec.Code != http.StatusOK || subs.ServiceName != serviceName {
+			t.Errorf("Expected the %s subscription, got %d: %s", serviceName, rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/subs/users/"+userID+"/audit", nil))
+
+	var page domain.AuditPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || rec.Code != http.StatusOK || len(page.Events) == 0 {
+		t.Errorf("Expected the audit log of the user, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestSpendSeriesFilterValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		filter   domain.SpendSeriesFilter
+		expected error
+	}{
+		{"month buckets", domain.SpendSeriesFilter{Start: date(2025, time.January, 1), End: date(2025, time.December, 31), Bucket: domain.PeriodMonth}, nil},
+		{"single day", domain.SpendSeriesFilter{Start: date(2025, time.May, 1), End: date(2025, time.May, 1), Bucket: domain.PeriodDay}, nil},
+		{"end before start", domain.SpendSeriesFilter{Start: date(2025, time.May, 2), End: date(2025, time.May, 1), Bucket: domain.PeriodDay}, domain.ErrInvalidDate},
+		{"unknown bucket", domain.SpendSeriesFilter{Start: date(2025, time.May, 1), End: date(2025, time.May, 31), Bucket: "quarter"}, domain.ErrInvalidBucket},
+		{"too many buckets", domain.SpendSeriesFilter{Start: date(2020, time.January, 1), End: date(2025, time.December, 31), Bucket: domain.PeriodDay}, domain.ErrInvalidBucket},
+	}
+
+	for _, c := range cases {
+		if err := c.filter.Validate(); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestGetSpendSeries(t *testing.T) {
+	ctx := context.Background()
+
+	// The bucket defaults to month
+	filter := domain.SpendSeriesFilter{Start: date(2025, time.January, 1), End: date(2025, time.June, 30), UserID: "user123"}
+	series, err := serv.GetSpendSeries(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if series.Bucket != domain.PeriodMonth || len(series.Points) != 6 || series.TotalPrice != 600 {
+		t.Errorf("Expected 6 monthly points totalling 600, got %s with %d points totalling %d",
+			series.Bucket, len(series.Points), series.TotalPrice)
+	}
+
+	filter = domain.SpendSeriesFilter{Start: date(2025, time.May, 1), End: date(2025, time.May, 7), Bucket: domain.PeriodDay, ServiceName: "notexist"}
+	series, err = serv.GetSpendSeries(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(series.Points) != 7 || series.TotalPrice != 0 {
+		t.Errorf("Expected 7 empty daily points, got %d points totalling %d", len(series.Points), series.TotalPrice)
+	}
+
+	filter.Bucket = "hour"
+	if _, err := serv.GetSpendSeries(ctx, filter); !errors.Is(err, domain.ErrInvalidBucket) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidBucket, err)
+	}
+}
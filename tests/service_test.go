@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"submanager/internal/core/domain"
@@ -40,18 +41,50 @@ func TestCreateSubscription(t *testing.T) {
 	}
 
 	// Default test case
-	err := serv.CreateSubscription(ctx, sampleSubscription)
+	id, err := serv.CreateSubscription(ctx, sampleSubscription)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
+	if id != "TestService-id" {
+		t.Errorf("Expected created subscription ID TestService-id, got %s", id)
+	}
 
 	// Check if subscription not unique
 	sampleSubscription.ServiceName = "notunique"
-	if err := serv.CreateSubscription(ctx, sampleSubscription); err == nil {
+	if _, err := serv.CreateSubscription(ctx, sampleSubscription); err == nil {
 		t.Errorf("Expected error %v, got %v", domain.ErrSubNotUnique, err)
 	}
 }
 
+func TestCreateSubscriptionBillingPeriod(t *testing.T) {
+	ctx := context.Background()
+
+	sampleSubscription := domain.Subscription{
+		ServiceName:   "TestService",
+		UserID:        "user123",
+		StartDate:     time.Now(),
+		Price:         100,
+		BillingPeriod: domain.BillingPeriod{Unit: domain.PeriodDay, Interval: 45},
+	}
+
+	// Custom billing period
+	if _, err := serv.CreateSubscription(ctx, sampleSubscription); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// Check if billing period is not supported
+	sampleSubscription.BillingPeriod = domain.BillingPeriod{Unit: "fortnight", Interval: 1}
+	if _, err := serv.CreateSubscription(ctx, sampleSubscription); err != domain.ErrInvalidBillingPeriod {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidBillingPeriod, err)
+	}
+
+	// Check if interval is not positive
+	sampleSubscription.BillingPeriod = domain.BillingPeriod{Unit: domain.PeriodMonth, Interval: 0}
+	if _, err := serv.CreateSubscription(ctx, sampleSubscription); err != domain.ErrInvalidBillingPeriod {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidBillingPeriod, err)
+	}
+}
+
 func TestGetSubscription(t *testing.T) {
 	ctx := context.Background()
 
@@ -74,19 +107,49 @@ func TestGetSubscription(t *testing.T) {
 func TestGetSubscriptionList(t *testing.T) {
 	ctx := context.Background()
 	userId := "user123"
+	page := domain.Pagination{PageNumber: 1, PageSize: 1}
 
 	// Default test case
-	if _, err := serv.GetSubscriptionList(ctx, userId); err != nil {
+	list, err := serv.GetSubscriptionList(ctx, domain.ListFilter{UserID: userId, Pagination: page})
+	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
+	// Check cursors of the first page
+	if list.NextCursor == "" || list.PrevCursor != "" {
+		t.Errorf("Expected only next cursor, got next %q and prev %q", list.NextCursor, list.PrevCursor)
+	}
+
+	cursor, err := domain.DecodeCursor(list.NextCursor)
+	if err != nil || cursor.ID != list.Subscriptions[0].ID {
+		t.Errorf("Expected cursor of the last subscription, got %+v, %v", cursor, err)
+	}
+
 	// Check if subscription list not found
 	userId = "notexist"
-	if _, err := serv.GetSubscriptionList(ctx, userId); err == nil {
+	if _, err := serv.GetSubscriptionList(ctx, domain.ListFilter{UserID: userId, Pagination: page}); err == nil {
 		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
 	}
 }
 
+func TestDecodeCursor(t *testing.T) {
+	cursor := domain.Cursor{StartDate: date(2025, time.May, 1), ID: "185925eb-2114-4c2a-bae7-6fdafa58d1d4"}
+
+	decoded, err := domain.DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !decoded.StartDate.Equal(cursor.StartDate) || decoded.ID != cursor.ID {
+		t.Errorf("Expected %+v, got %+v", cursor, decoded)
+	}
+
+	// Check if cursor is malformed
+	if _, err := domain.DecodeCursor("not a cursor"); err != domain.ErrInvalidCursor {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidCursor, err)
+	}
+}
+
 func TestUpdateSubscription(t *testing.T) {
 	ctx := context.Background()
 
@@ -142,18 +205,185 @@ func TestDeleteSubscriptionList(t *testing.T) {
 	}
 }
 
+func TestRestoreSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	// Default test case
+	serviceName, userID := "TestService", "user123"
+	subs, err := serv.RestoreSubscription(ctx, serviceName, userID)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if subs.ServiceName != serviceName {
+		t.Errorf("Expected restored subscription %s, got %s", serviceName, subs.ServiceName)
+	}
+
+	// Check if there is no deleted subscription
+	serviceName = "notdeleted"
+	if _, err := serv.RestoreSubscription(ctx, serviceName, userID); !errors.Is(err, domain.ErrSubsNotFound) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+
+	// Check if the service has been subscribed to again
+	serviceName = "notunique"
+	if _, err := serv.RestoreSubscription(ctx, serviceName, userID); !errors.Is(err, domain.ErrSubNotUnique) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubNotUnique, err)
+	}
+}
+
+func TestPurgeDeletedSubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	purged, err := serv.PurgeDeletedSubscriptions(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if purged != 2 {
+		t.Errorf("Expected 2 purged subscriptions, got %d", purged)
+	}
+}
+
 func TestGetSummaryByFilter(t *testing.T) {
 	ctx := context.Background()
 
 	// Default test case
-	start, end, serviceName, userID, pageNum, pageSize := time.Now(), time.Now(), "TestService", "user123", 1, 10
-	if _, err := serv.GetSummaryByFilter(ctx, start, end, serviceName, userID, pageNum, pageSize); err != nil {
+	filter := domain.SummaryFilter{
+		Start:       time.Now(),
+		End:         time.Now(),
+		ServiceName: "TestService",
+		UserID:      "user123",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	if _, err := serv.GetSummaryByFilter(ctx, filter); err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
+	// Check if a page past the end still reports the totals of the whole set
+	filter.PageNumber = 2
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.TotalItems != 1 || summary.TotalPages != 1 || len(summary.Subscriptions) != 0 {
+		t.Errorf("Expected 1 item on 1 page and an empty page, got %+v", summary)
+	}
+	filter.PageNumber = 1
+
+	// Check if filter mode is not supported
+	filter.Mode = "unknown"
+	if _, err := serv.GetSummaryByFilter(ctx, filter); err != domain.ErrInvalidFilterMode {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidFilterMode, err)
+	}
+
 	// Check if subscription not found
-	serviceName = "notexist"
-	if _, err := serv.GetSummaryByFilter(ctx, start, end, serviceName, userID, pageNum, pageSize); err == nil {
+	filter.Mode = domain.FilterOverlap
+	filter.ServiceName = "notexist"
+	if _, err := serv.GetSummaryByFilter(ctx, filter); err == nil {
 		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
 	}
 }
+
+func TestGetSummaryByFilterAgreesWithSQL(t *testing.T) {
+	ctx := context.Background()
+
+	// The subscription starts at the end of January in Moscow, which is still Jan 30 in UTC
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.February, 27),
+		End:         date(2025, time.February, 27),
+		ServiceName: "moscow",
+		UserID:      "user123",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The page total calculated in Go matches the total calculated by subscription_cost
+	if summary.PageTotal != summary.TotalPrice {
+		t.Errorf("Expected page total %d to match the SQL total %d", summary.PageTotal, summary.TotalPrice)
+	}
+}
+
+func TestGetSummaryByFilterProration(t *testing.T) {
+	ctx := context.Background()
+
+	// Mar 1 - Apr 30 covers the half of Feb 15 - Mar 15 cycle, the whole Mar 15 - Apr 15 cycle
+	// and the half of Apr 15 - May 15 cycle
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.March, 1),
+		End:         date(2025, time.April, 30),
+		ServiceName: "prorated",
+		UserID:      "user123",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(summary.Breakdown) != 1 {
+		t.Fatalf("Expected 1 breakdown item, got %d", len(summary.Breakdown))
+	}
+
+	cost := summary.Breakdown[0]
+	if cost.FullCycles != 1 {
+		t.Errorf("Expected 1 full cycle, got %d", cost.FullCycles)
+	}
+
+	if cost.ProratedDays != 30 {
+		t.Errorf("Expected 30 prorated days, got %v", cost.ProratedDays)
+	}
+
+	// 300 + 300*14/28 + 300*16/30
+	if summary.PageTotal != 610 {
+		t.Errorf("Expected page total 610, got %d", summary.PageTotal)
+	}
+
+	if summary.TotalPages != 1 {
+		t.Errorf("Expected 1 total page, got %d", summary.TotalPages)
+	}
+}
+
+func TestGetSummaryByFilterPriceHistory(t *testing.T) {
+	ctx := context.Background()
+
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.March, 1),
+		End:         date(2025, time.April, 30),
+		ServiceName: "repriced",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Price changed to 600 on Apr 1, so only the cycle billed on Apr 15 uses it:
+	// 300*14/28 + 300 + 600*16/30
+	if summary.PageTotal != 770 {
+		t.Errorf("Expected page total 770, got %d", summary.PageTotal)
+	}
+}
+
+func TestPriceAt(t *testing.T) {
+	subs := domain.Subscription{
+		Price: 500,
+		PriceHistory: []domain.PricePoint{
+			{Price: 300, EffectiveFrom: date(2025, time.January, 1)},
+			{Price: 500, EffectiveFrom: date(2025, time.June, 1)},
+		},
+	}
+
+	cases := map[time.Time]int{
+		date(2024, time.December, 1): 300,
+		date(2025, time.January, 1):  300,
+		date(2025, time.May, 31):     300,
+		date(2025, time.June, 1):     500,
+	}
+	for at, expected := range cases {
+		if got := subs.PriceAt(at); got != expected {
+			t.Errorf("Expected price %d at %v, got %d", expected, at, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestShareOf(t *testing.T) {
+	subs := mock.ProratedSubs
+	subs.Participants = []domain.Participant{
+		{UserID: "weighted", Weight: 2, Status: domain.ShareAccepted},
+		{UserID: "fixed", Amount: 60, Status: domain.ShareAccepted},
+		{UserID: "invited", Weight: 1, Status: domain.ShareInvited},
+	}
+
+	cases := []struct {
+		name     string
+		userID   string
+		price    float64
+		expected float64
+	}{
+		// 300 - 60 fixed leaves 240 split 1:2 between the owner and the weighted participant
+		{"owner", "user123", 300, 80},
+		{"weighted participant", "weighted", 300, 160},
+		{"fixed participant", "fixed", 300, 60},
+		{"invited participant pays nothing", "invited", 300, 0},
+		{"stranger pays nothing", "stranger", 300, 0},
+		// Fixed amounts over the price are scaled down and nothing remains to split
+		{"fixed over price", "fixed", 30, 30},
+		{"owner with fixed over price", "user123", 30, 0},
+	}
+
+	for _, c := range cases {
+		if got := subs.ShareOf(c.userID, c.price); got != c.expected {
+			t.Errorf("%s: expected share %v, got %v", c.name, c.expected, got)
+		}
+	}
+
+	if !subs.IsShared() {
+		t.Errorf("Expected subscription with accepted participants to be shared")
+	}
+}
+
+func TestValidateParticipants(t *testing.T) {
+	cases := []struct {
+		name         string
+		participants []domain.Participant
+		expected     error
+	}{
+		{"no participants", nil, nil},
+		{"weight and amount", []domain.Participant{{UserID: "a", Weight: 1}, {UserID: "b", Amount: 100}}, nil},
+		{"both weight and amount", []domain.Participant{{UserID: "a", Weight: 1, Amount: 100}}, domain.ErrInvalidShare},
+		{"neither weight nor amount", []domain.Participant{{UserID: "a"}}, domain.ErrInvalidShare},
+		{"negative amount", []domain.Participant{{UserID: "a", Amount: -1}}, domain.ErrInvalidShare},
+		{"listed twice", []domain.Participant{{UserID: "a", Weight: 1}, {UserID: "a", Weight: 2}}, domain.ErrInvalidShare},
+		{"owner listed", []domain.Participant{{UserID: "user123", Weight: 1}}, domain.ErrInvalidShare},
+	}
+
+	for _, c := range cases {
+		if err := domain.ValidateParticipants("user123", c.participants); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestGetSummaryByFilterShare(t *testing.T) {
+	ctx := context.Background()
+
+	// Mar 1 - Apr 30 costs the whole subscription 610, the owner pays a third of every charge
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.March, 1),
+		End:         date(2025, time.April, 30),
+		ServiceName: "shared",
+		UserID:      "user123",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 100*14/28 + 100 + 100*16/30
+	cost := summary.Breakdown[0]
+	if cost.Amount != 203 || cost.ShareOf != "user123" {
+		t.Errorf("Expected the owner share 203, got %d of %q", cost.Amount, cost.ShareOf)
+	}
+}
+
+func TestRespondToShare(t *testing.T) {
+	ctx := context.Background()
+	subsServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug))
+
+	participants := []domain.Participant{{UserID: "friend456", Weight: 2}}
+	subs, err := subsServ.SetSubscriptionParticipants(ctx, "Netflix-id", participants)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(subs.Participants) != 1 || subs.Participants[0].Status != domain.ShareInvited {
+		t.Fatalf("Expected an invited participant, got %+v", subs.Participants)
+	}
+
+	subs, err = subsServ.RespondToShare(ctx, "Netflix-id", "friend456", domain.ShareAccepted)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if subs.UserShare == nil || *subs.UserShare != 200 {
+		t.Errorf("Expected the participant share 200, got %v", subs.UserShare)
+	}
+
+	cases := []struct {
+		name     string
+		userID   string
+		to       domain.ShareStatus
+		expected error
+	}{
+		{"accept twice", "friend456", domain.ShareAccepted, domain.ErrShareAnswered},
+		{"not invited", "stranger", domain.ShareAccepted, domain.ErrShareNotFound},
+		{"invalid answer", "friend456", domain.ShareInvited, domain.ErrInvalidShareStatus},
+		{"leave", "friend456", domain.ShareDeclined, nil},
+		{"accept after leaving", "friend456", domain.ShareAccepted, domain.ErrShareAnswered},
+	}
+
+	for _, c := range cases {
+		if _, err := subsServ.RespondToShare(ctx, "Netflix-id", c.userID, c.to); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+
+	shared, err := subsServ.GetSharedSubscriptions(ctx, "friend456")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(shared) != 1 || shared[0].UserShare == nil || *shared[0].UserShare != 0 {
+		t.Errorf("Expected a declined share costing nothing, got %+v", shared)
+	}
+
+	owner := []domain.Participant{{UserID: "user123", Weight: 1}}
+	if _, err := subsServ.SetSubscriptionParticipants(ctx, "Netflix-id", owner); !errors.Is(err, domain.ErrInvalidShare) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidShare, err)
+	}
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"testing"
+	"time"
+)
+
+func TestStatusCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to domain.Status
+		allowed  bool
+	}{
+		{domain.StatusTrialing, domain.StatusActive, true},
+		{domain.StatusTrialing, domain.StatusCancelled, true},
+		{domain.StatusActive, domain.StatusPaused, true},
+		{domain.StatusPaused, domain.StatusActive, true},
+		{domain.StatusActive, domain.StatusCancelled, true},
+		{domain.StatusCancelled, domain.StatusExpired, true},
+		{domain.StatusPaused, domain.StatusExpired, true},
+		{domain.StatusExpired, domain.StatusExpired, false},
+		{domain.StatusCancelled, domain.StatusActive, false},
+		{domain.StatusPaused, domain.StatusCancelled, true},
+		{domain.StatusPaused, domain.StatusTrialing, false},
+		{domain.StatusTrialing, domain.StatusPaused, false},
+	}
+
+	for _, c := range cases {
+		if got := c.from.CanTransition(c.to); got != c.allowed {
+			t.Errorf("Expected %s -> %s allowed to be %v, got %v", c.from, c.to, c.allowed, got)
+		}
+	}
+}
+
+func TestChangeSubscriptionStatus(t *testing.T) {
+	ctx := context.Background()
+
+	// Default test case
+	subs, err := serv.ChangeSubscriptionStatus(ctx, "TestService", "user123", domain.StatusPaused)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if subs.Status != domain.StatusPaused || subs.StatusChanged.IsZero() {
+		t.Errorf("Expected paused status with change time, got %s at %v", subs.Status, subs.StatusChanged)
+	}
+
+	// Check if transition is not allowed
+	_, err = serv.ChangeSubscriptionStatus(ctx, "paused", "user123", domain.StatusTrialing)
+	var transitionErr *domain.TransitionError
+	if !errors.As(err, &transitionErr) || !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidTransition, err)
+	}
+
+	// Check if status is unknown
+	if _, err := serv.ChangeSubscriptionStatus(ctx, "TestService", "user123", "deleted"); err != domain.ErrInvalidStatus {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidStatus, err)
+	}
+
+	// Check if subscription not found
+	if _, err := serv.ChangeSubscriptionStatus(ctx, "notexist", "user123", domain.StatusPaused); err != domain.ErrSubsNotFound {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+}
+
+func TestPauseAndResumeSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	// Default test case
+	subs, err := serv.PauseSubscription(ctx, "pausable", "user123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if subs.Status != domain.StatusPaused {
+		t.Errorf("Expected status %s, got %s", domain.StatusPaused, subs.Status)
+	}
+
+	// Check if subscription is already paused
+	if _, err := serv.PauseSubscription(ctx, "pausable", "user123"); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidTransition, err)
+	}
+
+	subs, err = serv.ResumeSubscription(ctx, "pausable", "user123", true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if subs.Status != domain.StatusActive {
+		t.Errorf("Expected status %s, got %s", domain.StatusActive, subs.Status)
+	}
+
+	// Check if subscription is not paused
+	if _, err := serv.ResumeSubscription(ctx, "pausable", "user123", false); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidTransition, err)
+	}
+}
+
+func TestGetSummaryByFilterExcludesPauses(t *testing.T) {
+	ctx := context.Background()
+
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.March, 1),
+		End:         date(2025, time.April, 30),
+		ServiceName: "pausedprorated",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cost := summary.Breakdown[0]
+	if cost.FullCycles != 0 || cost.PausedDays != 31 {
+		t.Errorf("Expected no full cycles and 31 paused days, got %d and %v", cost.FullCycles, cost.PausedDays)
+	}
+
+	// 300*14/28 + 300*16/30, the whole Mar 15 - Apr 15 cycle is paused
+	if summary.PageTotal != 310 {
+		t.Errorf("Expected page total 310, got %d", summary.PageTotal)
+	}
+}
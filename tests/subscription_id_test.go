@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestUniquenessPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	sampleSubscription := domain.Subscription{
+		ServiceName: "notunique",
+		UserID:      "user123",
+		StartDate:   time.Now(),
+		Price:       100,
+	}
+
+	// Several subscriptions of the same service are allowed without the per-service policy
+	multiServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug),
+		service.WithUniquenessPolicy(domain.UniqueNone))
+	if _, err := multiServ.CreateSubscription(ctx, sampleSubscription); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// Default policy allows a single subscription per service
+	if _, err := serv.CreateSubscription(ctx, sampleSubscription); !errors.Is(err, domain.ErrSubNotUnique) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubNotUnique, err)
+	}
+
+	if !domain.UniquePerService.IsValid() || domain.UniquenessPolicy("user").IsValid() {
+		t.Errorf("Expected only known uniqueness policies to be valid")
+	}
+}
+
+func TestGetSubscriptionAmbiguous(t *testing.T) {
+	ctx := context.Background()
+
+	// Name-based lookups cannot pick one of several subscriptions of the service
+	if _, err := serv.GetSubscription(ctx, "ambiguous", "user123"); !errors.Is(err, domain.ErrSubsAmbiguous) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsAmbiguous, err)
+	}
+
+	subs, err := serv.GetSubscriptionByID(ctx, "ambiguous-id")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if subs.ID != "ambiguous-id" {
+		t.Errorf("Expected subscription ambiguous-id, got %s", subs.ID)
+	}
+
+	if _, err := serv.GetSubscriptionByID(ctx, "notexist-id"); !errors.Is(err, domain.ErrSubsNotFound) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+}
+
+func TestPatchSubscription(t *testing.T) {
+	ctx := context.Background()
+
+	price := 450
+	subs, err := serv.PatchSubscription(ctx, "patched-id", domain.SubsPatch{Price: &price})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if subs.Price != price {
+		t.Errorf("Expected price %d, got %d", price, subs.Price)
+	}
+	// Fields missing from the patch are kept
+	if !subs.StartDate.Equal(mock.ProratedSubs.StartDate) || !subs.EndDate.Equal(mock.ProratedSubs.EndDate) {
+		t.Errorf("Expected term %v - %v to be kept, got %v - %v",
+			mock.ProratedSubs.StartDate, mock.ProratedSubs.EndDate, subs.StartDate, subs.EndDate)
+	}
+
+	// Check if patched end date is before start date
+	endDate := mock.ProratedSubs.StartDate.AddDate(0, 0, -1)
+	if _, err := serv.PatchSubscription(ctx, "patched-id", domain.SubsPatch{EndDate: &endDate}); !errors.Is(err, domain.ErrInvalidDate) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidDate, err)
+	}
+
+	price = 0
+	if _, err := serv.PatchSubscription(ctx, "patched-id", domain.SubsPatch{Price: &price}); !errors.Is(err, domain.ErrPriceField) {
+		t.Errorf("Expected error %v, got %v", domain.ErrPriceField, err)
+	}
+}
+
+func TestDeleteSubscriptionByID(t *testing.T) {
+	ctx := context.Background()
+
+	if err := serv.DeleteSubscriptionByID(ctx, "TestService-id"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if err := serv.DeleteSubscriptionByID(ctx, "notexist-id"); !errors.Is(err, domain.ErrSubsNotFound) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags := domain.NormalizeTags([]string{" Work", "family", "", "work ", "  "})
+	if expected := []string{"family", "work"}; !slices.Equal(tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+}
+
+func TestCreateSubscriptionTags(t *testing.T) {
+	ctx := context.Background()
+	subsRepo := mock.NewMockSubsRepo()
+	tagServ := service.NewSubsService(subsRepo, logger.New(logger.Debug))
+
+	_, err := tagServ.CreateSubscription(ctx, domain.Subscription{
+		ServiceName: "netflix",
+		UserID:      "user123",
+		StartDate:   time.Now(),
+		Price:       100,
+		Tags:        []string{"Entertainment", "family", "entertainment"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if tags, expected := subsRepo.Created().Tags, []string{"entertainment", "family"}; !slices.Equal(tags, expected) {
+		t.Errorf("Expected stored tags %v, got %v", expected, tags)
+	}
+}
+
+func TestSetSubscriptionTags(t *testing.T) {
+	ctx := context.Background()
+	tagServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug))
+
+	subs, err := tagServ.SetSubscriptionTags(ctx, "netflix-id", []string{"Work", " work"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := []string{"work"}; !slices.Equal(subs.Tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, subs.Tags)
+	}
+
+	if _, err := tagServ.SetSubscriptionTags(ctx, "notexist-id", nil); !errors.Is(err, domain.ErrSubsNotFound) {
+		t.Errorf("Expected error %v, got %v", domain.ErrSubsNotFound, err)
+	}
+}
+
+func TestGetSubscriptionListByTags(t *testing.T) {
+	ctx := context.Background()
+	filter := domain.ListFilter{
+		UserID:     "user123",
+		Tags:       []string{"Work"},
+		Pagination: domain.Pagination{PageNumber: 1, PageSize: 1},
+	}
+
+	list, err := serv.GetSubscriptionList(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Tag filters are matched case-insensitively
+	if expected := []string{"work"}; !slices.Equal(list.Subscriptions[0].Tags, expected) {
+		t.Errorf("Expected filter tags %v, got %v", expected, list.Subscriptions[0].Tags)
+	}
+}
+
+func TestSummaryGroupBy(t *testing.T) {
+	ctx := context.Background()
+	filter := domain.SummaryFilter{
+		Start:      date(2025, time.January, 1),
+		End:        date(2025, time.December, 31),
+		Tags:       []string{"Family"},
+		Pagination: domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+
+	// Groups are only calculated on request
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(summary.Groups) != 0 {
+		t.Errorf("Expected no groups, got %v", summary.Groups)
+	}
+
+	filter.GroupBy = domain.GroupByTag
+	summary, err = serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(summary.Groups) != 1 || summary.Groups[0].Key != "family" {
+		t.Errorf("Expected a single family group, got %v", summary.Groups)
+	}
+
+	filter.GroupBy = "vendor"
+	if _, err := serv.GetSummaryByFilter(ctx, filter); !errors.Is(err, domain.ErrInvalidGroupBy) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidGroupBy, err)
+	}
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+func TestValidateTrial(t *testing.T) {
+	trialEnd := date(2025, time.February, 1)
+	beforeStart := date(2024, time.December, 1)
+
+	cases := []struct {
+		name     string
+		subs     domain.Subscription
+		expected error
+	}{
+		{"no trial", domain.Subscription{}, nil},
+		{"free trial", domain.Subscription{TrialEndDate: &trialEnd}, nil},
+		{"trial price without trial", domain.Subscription{TrialPrice: 10}, domain.ErrInvalidTrial},
+		{"negative trial price", domain.Subscription{TrialEndDate: &trialEnd, TrialPrice: -1}, domain.ErrInvalidTrial},
+		{"trial before start", domain.Subscription{TrialEndDate: &beforeStart}, domain.ErrInvalidTrial},
+		{"trial after end", domain.Subscription{TrialEndDate: &trialEnd, EndDate: date(2025, time.January, 20)}, domain.ErrInvalidTrial},
+	}
+
+	for _, c := range cases {
+		c.subs.StartDate = date(2025, time.January, 1)
+		if err := c.subs.ValidateTrial(); !errors.Is(err, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.name, c.expected, err)
+		}
+	}
+}
+
+func TestNextRenewalTrial(t *testing.T) {
+	trialEnd := date(2025, time.January, 15)
+	subs := domain.Subscription{
+		StartDate:     date(2025, time.January, 1),
+		TrialEndDate:  &trialEnd,
+		BillingPeriod: domain.DefaultBillingPeriod,
+	}
+
+	// The first charge is at the end of the trial, the following ones are counted from it
+	if got := subs.NextRenewal(date(2025, time.January, 5)); !got.Equal(trialEnd) {
+		t.Errorf("Expected %v, got %v", trialEnd, got)
+	}
+	if got := subs.NextRenewal(trialEnd); !got.Equal(date(2025, time.February, 15)) {
+		t.Errorf("Expected %v, got %v", date(2025, time.February, 15), got)
+	}
+}
+
+func TestCreateSubscriptionTrial(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewMockSubsRepo()
+	subsServ := service.NewSubsService(repo, logger.New(logger.Debug))
+
+	start := time.Now().Truncate(24 * time.Hour)
+	trialEnd := start.AddDate(0, 0, 14)
+	subs := domain.Subscription{
+		ServiceName:  "Netflix",
+		Price:        500,
+		UserID:       "user123",
+		StartDate:    start,
+		TrialEndDate: &trialEnd,
+	}
+	if _, err := subsServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	created := repo.Created()
+	if created.Status != domain.StatusTrialing {
+		t.Errorf("Expected a running trial to start as trialing, got %s", created.Status)
+	}
+	// The default term covers one paid period after the trial
+	if expected := trialEnd.AddDate(0, 1, 0); !created.EndDate.Equal(expected) {
+		t.Errorf("Expected end date %v, got %v", expected, created.EndDate)
+	}
+
+	// Nothing to convert while the trial is running
+	if converted, err := subsServ.ConvertTrials(ctx); err != nil || converted != 0 {
+		t.Fatalf("Expected no conversions, got %d, %v", converted, err)
+	}
+
+	// A trial which has already ended starts as active
+	ended := start.AddDate(0, 0, -1)
+	subs.StartDate, subs.TrialEndDate = start.AddDate(0, 0, -15), &ended
+	if _, err := subsServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if status := repo.Created().Status; status != domain.StatusActive {
+		t.Errorf("Expected an ended trial to start as active, got %s", status)
+	}
+
+	subs.TrialPrice = -1
+	if _, err := subsServ.CreateSubscription(ctx, subs); !errors.Is(err, domain.ErrInvalidTrial) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidTrial, err)
+	}
+}
+
+func TestConvertTrials(t *testing.T) {
+	ctx := context.Background()
+	repo := mock.NewMockSubsRepo()
+	subsServ := service.NewSubsService(repo, logger.New(logger.Debug))
+
+	// Trials are only created as trialing while running, so the trial is shortened to end in a moment
+	trialEnd := time.Now().Add(50 * time.Millisecond)
+	subs := domain.Subscription{
+		ServiceName:  "Netflix",
+		Price:        500,
+		UserID:       "user123",
+		StartDate:    time.Now().Truncate(24 * time.Hour),
+		TrialEndDate: &trialEnd,
+	}
+	if _, err := subsServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	converted, err := subsServ.ConvertTrials(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if converted != 1 || repo.Created().Status != domain.StatusActive {
+		t.Errorf("Expected the ended trial to be converted, got %d, %s", converted, repo.Created().Status)
+	}
+}
+
+func TestGetSummaryByFilterTrial(t *testing.T) {
+	ctx := context.Background()
+
+	// The Jan 15 - Mar 1 trial is not billed by cycles, the Mar 1 - Apr 1 cycle is charged in full
+	filter := domain.SummaryFilter{
+		Start:       date(2025, time.January, 1),
+		End:         date(2025, time.March, 31),
+		ServiceName: "trial",
+		UserID:      "user123",
+		Pagination:  domain.Pagination{PageNumber: 1, PageSize: 10},
+	}
+	summary, err := serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cost := summary.Breakdown[0]
+	if cost.TrialDays != 45 || cost.TrialAmount != 50 {
+		t.Errorf("Expected 45 trial days charged 50, got %v days charged %d", cost.TrialDays, cost.TrialAmount)
+	}
+	if !cost.BilledFrom.Equal(mock.ProratedTrialEnd) || cost.FullCycles != 1 {
+		t.Errorf("Expected 1 full cycle billed from the end of the trial, got %d from %v", cost.FullCycles, cost.BilledFrom)
+	}
+	if cost.Amount != 350 {
+		t.Errorf("Expected amount 350, got %d", cost.Amount)
+	}
+
+	// The trial price is only charged by the window the subscription starts in
+	filter.Start, filter.End = date(2025, time.February, 1), date(2025, time.February, 28)
+	summary, err = serv.GetSummaryByFilter(ctx, filter)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cost := summary.Breakdown[0]; cost.Amount != 0 || cost.TrialDays != 28 {
+		t.Errorf("Expected a free February of the trial, got %+v", cost)
+	}
+}
+
+func TestGetTrialReport(t *testing.T) {
+	ctx := context.Background()
+
+	report, err := serv.GetTrialReport(ctx, domain.TrialReportFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 services, got %d", len(report))
+	}
+
+	// Running trials are not counted into the rate
+	if report[0].ConversionRate != 0.75 {
+		t.Errorf("Expected conversion rate 0.75, got %v", report[0].ConversionRate)
+	}
+	if report[1].ConversionRate != 0 {
+		t.Errorf("Expected no conversion rate without ended trials, got %v", report[1].ConversionRate)
+	}
+
+	filter := domain.TrialReportFilter{Start: date(2025, time.May, 1), End: date(2025, time.April, 1)}
+	if _, err := serv.GetTrialReport(ctx, filter); !errors.Is(err, domain.ErrInvalidDate) {
+		t.Errorf("Expected error %v, got %v", domain.ErrInvalidDate, err)
+	}
+}
+
+func TestGetTrialReportEndedTrial(t *testing.T) {
+	ctx := context.Background()
+	subsServ := service.NewSubsService(mock.NewMockSubsRepo(), logger.New(logger.Debug))
+
+	// The subscription is created after its trial ended, so it starts as active
+	start := time.Now().Truncate(24*time.Hour).AddDate(0, 0, -20)
+	trialEnd := start.AddDate(0, 0, 14)
+	subs := domain.Subscription{
+		ServiceName:  "Kinopoisk",
+		Price:        300,
+		UserID:       "user123",
+		StartDate:    start,
+		TrialEndDate: &trialEnd,
+	}
+	if _, err := subsServ.CreateSubscription(ctx, subs); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	report, err := subsServ.GetTrialReport(ctx, domain.TrialReportFilter{ServiceName: "Kinopoisk"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The trial counts as converted rather than as a trial without an outcome
+	expected := domain.TrialConversion{ServiceName: "Kinopoisk", Trials: 1, Converted: 1, ConversionRate: 1}
+	if len(report) != 1 || report[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, report)
+	}
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"submanager/internal/adapters/notify"
+	"submanager/internal/core/domain"
+	"submanager/internal/core/service"
+	"submanager/internal/pkg/logger"
+	mock "submanager/tests/mocks"
+	"testing"
+	"time"
+)
+
+// failingSender fails the first failures deliveries and records the delivered ones.
+type failingSender struct {
+	failures  int
+	delivered []domain.WebhookDelivery
+}
+
+func (s *failingSender) Send(ctx context.Context, delivery domain.WebhookDelivery) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("endpoint is unavailable")
+	}
+	s.delivered = append(s.delivered, delivery)
+	return nil
+}
+
+var webhookPolicy = service.WebhookPolicy{MaxAttempts: 2, RetryDelay: time.Minute}
+
+func TestExpireSubscriptions(t *testing.T) {
+	expired, err := serv.ExpireSubscriptions(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("Expected a single expired subscription, got %d", expired)
+	}
+}
+
+func TestExpireSubscriptionsRenewal(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	subsRepo := mock.NewMockSubsRepo()
+	subsServ := service.NewSubsService(subsRepo, logger.New(logger.Debug))
+
+	start := now.AddDate(0, -2, -10)
+	renewing := domain.Subscription{ID: "renewing-id", StartDate: start, EndDate: start.AddDate(0, 1, 0),
+		BillingPeriod: domain.DefaultBillingPeriod, Status: domain.StatusActive, AutoRenew: true}
+	paused := renewing
+	paused.ID, paused.Status = "paused-id", domain.StatusPaused
+	ending := renewing
+	ending.ID, ending.AutoRenew = "ending-id", false
+	running := renewing
+	running.ID, running.EndDate = "running-id", now.AddDate(0, 0, 5)
+	subsRepo.SetEnded(renewing, paused, ending, running)
+
+	expired, err := subsServ.ExpireSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("Expected only the non-renewing subscription to expire, got %d", expired)
+	}
+
+	ended := subsRepo.Ended()
+	// Auto-renewing subscriptions renew to the end of the running term, a paused one stays paused
+	for _, subs := range ended[:2] {
+		if !subs.EndDate.After(now) || !subs.EndDate.Equal(subs.BillingPeriod.Shift(start, 3)) {
+			t.Errorf("Expected %s to renew to %v, got %v", subs.ID, subs.BillingPeriod.Shift(start, 3), subs.EndDate)
+		}
+	}
+	if ended[1].Status != domain.StatusPaused {
+		t.Errorf("Expected the renewed subscription to stay paused, got %s", ended[1].Status)
+	}
+
+	// A non-renewing subscription expires at its end date and is not renewed
+	if ending := ended[2]; ending.Status != domain.StatusExpired || !ending.EndDate.Equal(start.AddDate(0, 1, 0)) {
+		t.Errorf("Expected the non-renewing subscription to expire at its end date, got %+v", ending)
+	}
+	if !ended[3].EndDate.Equal(running.EndDate) || ended[3].Status != domain.StatusActive {
+		t.Errorf("Expected the running term to be left as it is, got %+v", ended[3])
+	}
+
+	want := []domain.EventType{domain.EventSubsRenewed, domain.EventSubsRenewed, domain.EventSubsExpired}
+	if events := subsRepo.Events(); len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, event := range subsRepo.Events() {
+		if event.Type != want[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, want[i], event.Type)
+		}
+	}
+
+	// Nothing is renewed or expired again on the next run
+	if expired, _ := subsServ.ExpireSubscriptions(ctx); expired != 0 || len(subsRepo.Events()) != len(want) {
+		t.Errorf("Expected nothing to change on the next run, got %d expired and %+v", expired, subsRepo.Events())
+	}
+}
+
+func TestWebhookEndpoints(t *testing.T) {
+	ctx := context.Background()
+	webhookServ := service.NewWebhookService(mock.NewMockWebhookRepo(), &failingSender{}, webhookPolicy, logger.New(logger.Debug))
+
+	tests := []struct {
+		name     string
+		endpoint domain.WebhookEndpoint
+		err      error
+	}{
+		{
+			name:     "All events",
+			endpoint: domain.WebhookEndpoint{URL: "https://billing.example.com/hooks"},
+		},
+		{
+			name:     "Relative URL",
+			endpoint: domain.WebhookEndpoint{URL: "/hooks"},
+			err:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:     "Unknown event type",
+			endpoint: domain.WebhookEndpoint{URL: "https://billing.example.com/hooks", EventTypes: []domain.EventType{"subscription.renamed"}},
+			err:      domain.ErrInvalidWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := webhookServ.CreateEndpoint(ctx, tt.endpoint)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+
+			// The secret is generated and only shown on creation
+			if len(endpoint.Secret) == 0 {
+				t.Errorf("Expected a generated secret")
+			}
+			stored, err := webhookServ.GetEndpoint(ctx, endpoint.ID)
+			if err != nil || stored.Secret != "" {
+				t.Errorf("Expected the stored endpoint without its secret, got %+v, %v", stored, err)
+			}
+		})
+	}
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := mock.NewMockWebhookRepo()
+	sender := &failingSender{failures: 2}
+	webhookServ := service.NewWebhookService(webhookRepo, sender, webhookPolicy, logger.New(logger.Debug))
+
+	filtered := domain.WebhookEndpoint{URL: "https://billing.example.com/hooks", EventTypes: []domain.EventType{domain.EventSubsDeleted}}
+	if _, err := webhookServ.CreateEndpoint(ctx, filtered); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Only events of the filtered types are scheduled
+	webhookServ.Publish(ctx, newEvent(t, domain.EventSubsCreated, domain.Subscription{ID: "netflix-id"}))
+	webhookServ.Publish(ctx, newEvent(t, domain.EventSubsDeleted, domain.Subscription{ID: "netflix-id"}))
+	if len(webhookRepo.Deliveries) != 1 {
+		t.Fatalf("Expected a single scheduled delivery, got %d", len(webhookRepo.Deliveries))
+	}
+
+	if delivered, err := webhookServ.DeliverWebhooks(ctx); err != nil || delivered != 0 {
+		t.Fatalf("Expected a failed delivery, got %d delivered, %v", delivered, err)
+	}
+	delivery := webhookRepo.Deliveries[1]
+	if delivery.Status != domain.DeliveryPending || delivery.Attempts != 1 || !delivery.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("Expected a delayed retry, got %+v", delivery)
+	}
+
+	// The last allowed attempt fails as well and the delivery becomes a dead letter
+	delivery.NextAttemptAt = time.Now()
+	webhookServ.DeliverWebhooks(ctx)
+	dead, err := webhookServ.GetDeadLetters(ctx)
+	if err != nil || len(dead) != 1 || dead[0].Attempts != 2 {
+		t.Fatalf("Expected a dead letter after 2 attempts, got %+v, %v", dead, err)
+	}
+
+	// Manual redelivery starts over and succeeds
+	if err := webhookServ.Redeliver(ctx, dead[0].ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if delivered, _ := webhookServ.DeliverWebhooks(ctx); delivered != 1 || delivery.Status != domain.DeliveryDelivered {
+		t.Errorf("Expected the redelivery to succeed, got %+v", delivery)
+	}
+
+	if err := webhookServ.Redeliver(ctx, 42); !errors.Is(err, domain.ErrDeliveryNotFound) {
+		t.Errorf("Expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestSignedSender(t *testing.T) {
+	payload, _ := json.Marshal(newEvent(t, domain.EventSubsExpired, domain.Subscription{ID: "netflix-id"}))
+	delivery := domain.WebhookDelivery{EventID: "event-1", EventType: domain.EventSubsExpired, Payload: payload, Secret: "secret"}
+
+	var signatureValid bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		expected := notify.Sign(delivery.Secret, r.Header.Get(notify.TimestampHeader), body)
+		signatureValid = r.Header.Get(notify.SignatureHeader) == expected &&
+			r.Header.Get(notify.EventHeader) == string(domain.EventSubsExpired)
+	}))
+	defer server.Close()
+
+	delivery.URL = server.URL
+	if err := notify.NewSignedSender().Send(context.Background(), delivery); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !signatureValid {
+		t.Errorf("Expected the delivery to carry a valid signature")
+	}
+
+	if notify.Sign("other", "0", payload) == notify.Sign("secret", "0", payload) {
+		t.Errorf("Expected signatures of different secrets to differ")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	delivery.URL = failing.URL
+	if err := notify.NewSignedSender().Send(context.Background(), delivery); err == nil {
+		t.Errorf("Expected error on a non-2xx response")
+	}
+}